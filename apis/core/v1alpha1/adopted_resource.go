@@ -15,6 +15,7 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // AdoptedResourceSpec defines the desired state of the AdoptedResource.
@@ -23,13 +24,54 @@ type AdoptedResourceSpec struct {
 	Kubernetes *TargetKubernetesResource `json:"kubernetes"`
 	// +kubebuilder:validation:Required
 	AWS *AWSIdentifiers `json:"aws"`
+	// DriftPolicy determines whether the differences between the Spec of the
+	// target resource and the adopted AWS resource, such as the ones
+	// introduced by a Spec override, are applied to the AWS resource right
+	// after the adoption. Defaults to Observe.
+	// +kubebuilder:validation:Enum=Observe;Enforce
+	// +optional
+	DriftPolicy *AdoptionDriftPolicy `json:"driftPolicy,omitempty"`
 }
 
+// AdoptionDriftPolicy describes how the ACK service controller handles the
+// differences between the Spec of a newly adopted resource and the state of
+// the adopted AWS resource.
+type AdoptionDriftPolicy string
+
+const (
+	// AdoptionDriftPolicyObserve leaves the adopted AWS resource untouched
+	// until the Spec of the target resource is modified. The differences are
+	// reported in an ACK.Advisory condition on the target resource.
+	AdoptionDriftPolicyObserve AdoptionDriftPolicy = "Observe"
+	// AdoptionDriftPolicyEnforce applies the Spec of the target resource to
+	// the adopted AWS resource on the first reconciliation of the target
+	// resource.
+	AdoptionDriftPolicyEnforce AdoptionDriftPolicy = "Enforce"
+)
+
 // AdoptedResourceStatus defines the observed status of the AdoptedResource.
 type AdoptedResourceStatus struct {
 	// A collection of `ackv1alpha1.Condition` objects that describe the various
 	// terminal states of the adopted resource CR and its target custom resource
 	Conditions []*Condition `json:"conditions"`
+	// Target is a reference to the custom resource that was created for the
+	// adopted AWS resource
+	// +optional
+	Target *AdoptedResourceTarget `json:"target,omitempty"`
+	// ARN is the AWS Resource Name of the adopted AWS resource, if the
+	// resource has one
+	// +optional
+	ARN *AWSResourceName `json:"arn,omitempty"`
+}
+
+// AdoptedResourceTarget identifies the custom resource created by the ACK
+// service controller for an adopted AWS resource.
+type AdoptedResourceTarget struct {
+	Group     string    `json:"group"`
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	UID       types.UID `json:"uid,omitempty"`
 }
 
 // AdoptedResource is the schema for the AdoptedResource API.
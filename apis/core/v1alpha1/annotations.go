@@ -32,6 +32,10 @@ const (
 	// the AWS IAM Role that the ACK service controller runs as needs to have
 	// the ability to call the AWS STS::AssumeRole API call and assume an IAM
 	// Role in the target AWS Account.
+	// An AWS account other than the default AWS account of the CR's
+	// namespace is rejected, unless an AdoptedResource in the CR's
+	// namespace adopted the CR from that AWS account, and the service
+	// controller is started with the `--allow-cross-account-adoption` flag.
 	// TODO(jaypipes): Link to documentation on cross-account resource
 	// management
 	AnnotationOwnerAccountID = AnnotationPrefix + "owner-account-id"
@@ -59,4 +63,176 @@ const (
 	// will either use the default behavior	of aws-sdk-go to create endpoints or
 	// aws-endpoint-url if it is set in controller binary flags and environment variables.
 	AnnotationEndpointURL = AnnotationPrefix + "endpoint-url"
+	// AnnotationAllowReferencesFrom is an annotation whose value is a
+	// comma-separated list of namespaces whose resources are allowed to
+	// reference the resources living in the annotated namespace. The special
+	// value "*" allows references from every namespace. If this annotation is
+	// not set on a namespace, its resources can only be referenced by
+	// resources in the same namespace.
+	AnnotationAllowReferencesFrom = AnnotationPrefix + "allow-references-from"
+	// AnnotationCascadeDeletion is an annotation whose value is a boolean
+	// value. By default, the deletion of a CR is held for as long as other CRs
+	// reference it. If this annotation is set to true on a CR, the ACK service
+	// controller deletes all the CRs referencing it before deleting the CR
+	// itself.
+	AnnotationCascadeDeletion = AnnotationPrefix + "cascade-deletion"
+	// AnnotationDeletionProtection is an annotation whose value is a boolean
+	// value. If this annotation is set to true on a CR, the ACK service
+	// controller refuses to delete the backend AWS service API resource and
+	// keeps the CR's finalizer in place, leaving the CR stuck in deletion
+	// until the annotation is removed or set to false.
+	AnnotationDeletionProtection = AnnotationPrefix + "deletion-protection"
+	// AnnotationDefaultDeletionProtection is an annotation whose value is a
+	// boolean value. If this annotation is set on a namespace, the Kubernetes
+	// user is indicating the default deletion protection of the CRs living
+	// in the namespace, if a deletion-protection annotation is not set on the
+	// CR metadata.
+	AnnotationDefaultDeletionProtection = AnnotationPrefix + "default-deletion-protection"
+	// AnnotationDefaultTags is an annotation whose value is a JSON object
+	// mapping AWS tag keys to tag values. If this annotation is set on a
+	// namespace, the Kubernetes user is indicating the tags that the ACK
+	// service controller should set on the AWS resources of the CRs living in
+	// the namespace, in addition to the controller's default tags. The tag
+	// values support the same tokens as the controller's `--resource-tags`.
+	// The tags set in the Spec of a CR override the namespace default tags,
+	// which override the controller's default tags. Tag keys prefixed with
+	// `services.k8s.aws/` are reserved to the ACK service controller and
+	// cannot be set this way.
+	AnnotationDefaultTags = AnnotationPrefix + "default-tags"
+	// AnnotationAdoptionPolicy is an annotation whose value is one of the
+	// AdoptionPolicy values. If this annotation is set on a CR, the Kubernetes
+	// user is indicating how the ACK service controller should handle a
+	// backend AWS service API resource that may or may not already exist.
+	// If this annotation is not set, the CR behaves as `adopt-only` when the
+	// AnnotationAdopted annotation is set to true, and as `create-only`
+	// otherwise.
+	AnnotationAdoptionPolicy = AnnotationPrefix + "adoption-policy"
+	// AnnotationAdoptionDriftPolicy is an annotation set by the ACK service
+	// controller on the CRs it creates for adopted AWS resources. Its value is
+	// the AdoptionDriftPolicy of the AdoptedResource. If this annotation is
+	// set to `Observe`, the ACK service controller does not apply the
+	// differences between the CR's Spec and the AWS resource until the CR's
+	// Spec is modified, that is until the CR's `metadata.generation` is
+	// higher than the value of the AnnotationAdoptionObservedGeneration
+	// annotation.
+	AnnotationAdoptionDriftPolicy = AnnotationPrefix + "adoption-drift-policy"
+	// AnnotationAdoptionObservedGeneration is an annotation set by the ACK
+	// service controller on the CRs it creates for adopted AWS resources with
+	// the `Observe` AdoptionDriftPolicy. Its value is the
+	// `metadata.generation` of the CR as adopted, including the changes made
+	// to the CR's Spec by the ACK service controller itself, such as late
+	// initialized fields. This annotation should not be modified.
+	AnnotationAdoptionObservedGeneration = AnnotationPrefix + "adoption-observed-generation"
+	// AnnotationConversionData is an annotation set by the conversion webhook
+	// of the ACK service controller when a CR is converted from the hub API
+	// version to an older API version. Its value is a JSON object holding the
+	// fields of the CR that have no equivalent in the older API version, so
+	// that they are restored when the CR is converted back to the hub API
+	// version instead of being lost. This annotation should not be modified.
+	AnnotationConversionData = AnnotationPrefix + "conversion-data"
+	// AnnotationIgnorePaths is an annotation whose value is a comma-separated
+	// list of dotted field paths, e.g. `spec.policyDocument,spec.tags`. If
+	// this annotation is set on a CR, the Kubernetes user is indicating that
+	// the ACK service controller should ignore the differences between the CR
+	// and the backend AWS service API resource at, or nested in, those paths
+	// when deciding whether to update the AWS resource. Field names are
+	// matched case-insensitively, so both the JSON names of the fields and
+	// their Go names, e.g. `Spec.PolicyDocument`, are accepted. A `*` part
+	// matches any field name, list index or map key, e.g.
+	// `spec.rules.*.priority`.
+	//
+	// The ignored differences are left out of the Delta passed to the
+	// resource manager when the AWS resource is updated for other
+	// differences. Resource managers sending every field of the Spec in their
+	// update calls, instead of only the differing ones, still send the values
+	// of the ignored fields set in the CR.
+	AnnotationIgnorePaths = AnnotationPrefix + "ignore-paths"
+	// AnnotationLastAppliedSpec is an annotation set by the ACK service
+	// controller on a CR after successfully creating or updating its backend
+	// AWS service API resource. Its value is the JSON representation of the
+	// fields of the Spec that the user declared when it was applied, without
+	// the fields the ACK service controller copied from the AWS resource. It
+	// is used to tell the fields the user declared, or removed from the Spec,
+	// from the fields the user never set, whose values may be modified
+	// out-of-band without being reverted by the ACK service controller,
+	// unless the CR's AnnotationDriftPolicy is `strict`. The annotation is
+	// not set if the declared Spec exceeds 64KiB. This annotation should not
+	// be modified.
+	AnnotationLastAppliedSpec = AnnotationPrefix + "last-applied-spec"
+	// AnnotationLastWrittenSpec is an annotation set by the ACK service
+	// controller on a CR having an AnnotationLastAppliedSpec annotation,
+	// whenever the ACK service controller writes the CR's Spec. Its value is
+	// the JSON representation of the Spec that was written. It is used to
+	// tell the fields the user modified since. The annotation is not set if
+	// the Spec exceeds 64KiB. This annotation should not be modified.
+	AnnotationLastWrittenSpec = AnnotationPrefix + "last-written-spec"
+	// AnnotationDriftPolicy is an annotation whose value is one of the
+	// DriftPolicy values. If this annotation is set on a CR, the Kubernetes
+	// user is indicating which differences between the CR's Spec and the
+	// backend AWS service API resource the ACK service controller should
+	// apply to the AWS resource. If this annotation is not set, the CR
+	// behaves as `declared`.
+	AnnotationDriftPolicy = AnnotationPrefix + "drift-policy"
+	// AnnotationImmutableFieldPolicy is an annotation whose value is one of the
+	// ImmutableFieldPolicy values. If this annotation is set on a CR, the
+	// Kubernetes user is indicating how the ACK service controller should
+	// handle the modification of Spec fields that cannot be changed after the
+	// backend AWS service API resource was created. Only the immutable fields
+	// the user declared in, or modified since, the Spec last applied are
+	// considered modified. If this annotation is not set, the CR behaves as
+	// `advisory`.
+	AnnotationImmutableFieldPolicy = AnnotationPrefix + "immutable-field-policy"
+)
+
+// AdoptionPolicy describes how the ACK service controller handles a backend
+// AWS service API resource that may or may not already exist when reconciling
+// a CR that is not yet managed by ACK.
+type AdoptionPolicy string
+
+const (
+	// AdoptionPolicyAdoptOrCreate adopts the backend AWS service API resource
+	// identified by the CR if it exists, and creates it otherwise.
+	AdoptionPolicyAdoptOrCreate AdoptionPolicy = "adopt-or-create"
+	// AdoptionPolicyAdoptOnly adopts the backend AWS service API resource
+	// identified by the CR, and fails if it does not exist.
+	AdoptionPolicyAdoptOnly AdoptionPolicy = "adopt-only"
+	// AdoptionPolicyCreateOnly creates the backend AWS service API resource,
+	// and fails if a resource identified by the CR already exists.
+	AdoptionPolicyCreateOnly AdoptionPolicy = "create-only"
+)
+
+// DriftPolicy describes which differences between the Spec of a CR and its
+// backend AWS service API resource the ACK service controller applies to the
+// AWS resource.
+type DriftPolicy string
+
+const (
+	// DriftPolicyDeclared only applies the differences at the fields that
+	// the user set in the Spec the last time it was applied, or modified in
+	// the Spec since. The fields the user never set, including the ones the
+	// ACK service controller copied from the AWS resource to the Spec, may be
+	// modified out-of-band. The CRs without an AnnotationLastAppliedSpec
+	// annotation, e.g. because they have not been created or updated since it
+	// was introduced or because their Spec is too large, behave as `strict`.
+	DriftPolicyDeclared DriftPolicy = "declared"
+	// DriftPolicyStrict applies all the differences, reverting the
+	// out-of-band modifications of every field of the AWS resource.
+	DriftPolicyStrict DriftPolicy = "strict"
+)
+
+// ImmutableFieldPolicy describes how the ACK service controller handles the
+// modification of the immutable Spec fields of a CR.
+type ImmutableFieldPolicy string
+
+const (
+	// ImmutableFieldPolicyAdvisory sets an ACK.Advisory condition naming the
+	// modified immutable fields, and still applies the modifications of the
+	// other fields to the AWS resource. The modified immutable fields are
+	// never applied.
+	ImmutableFieldPolicyAdvisory ImmutableFieldPolicy = "advisory"
+	// ImmutableFieldPolicyTerminal sets an ACK.Advisory and an ACK.Terminal
+	// condition naming the modified immutable fields, and does not apply any
+	// modification to the AWS resource, including the ones of the other
+	// fields, until the immutable fields are restored.
+	ImmutableFieldPolicyTerminal ImmutableFieldPolicy = "terminal"
 )
@@ -0,0 +1,116 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BulkAdoptionSpec defines the desired state of the BulkAdoption.
+type BulkAdoptionSpec struct {
+	// Kubernetes identifies the kind of the custom resources that are created
+	// for the matched AWS resources. The namespace, labels and annotations in
+	// the metadata are applied to every created custom resource. The name in
+	// the metadata is ignored in favour of NameTemplate.
+	// +kubebuilder:validation:Required
+	Kubernetes *TargetKubernetesResource `json:"kubernetes"`
+	// +kubebuilder:validation:Required
+	AWS *BulkAdoptionAWSFilter `json:"aws"`
+	// NameTemplate is used to render the name of every created custom
+	// resource. The following tokens are expanded:
+	//   - %NAME_OR_ID%: the name or identifier of the AWS resource, or the
+	//     resource portion of its ARN if no name or identifier is known
+	//   - %INDEX%: the position of the AWS resource in the list of matches.
+	//     The AWS resources matched by TagFilters are sorted by ARN, and then
+	//     by name or identifier.
+	//   - %BULK_ADOPTION_NAME%: the name of the BulkAdoption
+	// The rendered name is converted into a valid DNS-1123 subdomain. The
+	// AdoptedResource created for the AWS resource is given the same name.
+	// Defaults to "%NAME_OR_ID%".
+	// +optional
+	NameTemplate *string `json:"nameTemplate,omitempty"`
+	// DriftPolicy is the drift policy of every created AdoptedResource.
+	// Defaults to Observe.
+	// +kubebuilder:validation:Enum=Observe;Enforce
+	// +optional
+	DriftPolicy *AdoptionDriftPolicy `json:"driftPolicy,omitempty"`
+}
+
+// BulkAdoptionAWSFilter selects the AWS resources to adopt. Exactly one of
+// Identifiers or TagFilters must be supplied.
+type BulkAdoptionAWSFilter struct {
+	// Identifiers is an explicit list of the AWS resources to adopt.
+	// +optional
+	Identifiers []*AWSIdentifiers `json:"identifiers,omitempty"`
+	// TagFilters selects all AWS resources of the target kind whose tags match
+	// every one of the filters. The resource manager of the target kind must
+	// support listing resources.
+	// +optional
+	TagFilters []*AWSTagFilter `json:"tagFilters,omitempty"`
+}
+
+// AWSTagFilter matches AWS resources that have a tag with the supplied key
+// and, if any values are supplied, one of the supplied values.
+type AWSTagFilter struct {
+	// +kubebuilder:validation:Required
+	Key string `json:"key"`
+	// +optional
+	Values []string `json:"values,omitempty"`
+}
+
+// BulkAdoptionStatus defines the observed status of the BulkAdoption.
+type BulkAdoptionStatus struct {
+	// A collection of `ackv1alpha1.Condition` objects that describe the various
+	// terminal states of the bulk adoption
+	Conditions []*Condition `json:"conditions"`
+	// Matched is the number of AWS resources matched by the spec
+	// +optional
+	Matched int32 `json:"matched"`
+	// Created is the number of AdoptedResources that have been created for
+	// the matched AWS resources
+	// +optional
+	Created int32 `json:"created"`
+	// Adopted is the number of matched AWS resources that have been adopted
+	// +optional
+	Adopted int32 `json:"adopted"`
+	// Failed is the number of matched AWS resources that could not be adopted
+	// +optional
+	Failed int32 `json:"failed"`
+}
+
+// BulkAdoption is the schema for the BulkAdoption API. A BulkAdoption creates
+// an AdoptedResource for every AWS resource that it matches.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Matched",type=integer,JSONPath=`.status.matched`
+// +kubebuilder:printcolumn:name="Adopted",type=integer,JSONPath=`.status.adopted`
+// +kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.failed`
+type BulkAdoption struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              BulkAdoptionSpec   `json:"spec,omitempty"`
+	Status            BulkAdoptionStatus `json:"status,omitempty"`
+}
+
+// BulkAdoptionList defines a list of BulkAdoptions.
+// +kubebuilder:object:root=true
+type BulkAdoptionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BulkAdoption `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BulkAdoption{}, &BulkAdoptionList{})
+}
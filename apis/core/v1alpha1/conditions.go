@@ -81,4 +81,16 @@ type Condition struct {
 	// A human readable message indicating details about the transition.
 	// +optional
 	Message *string `json:"message,omitempty"`
+	// ObservedGeneration is the `metadata.generation` of the resource that
+	// the condition was set for. If it is lower than the resource's current
+	// generation, the condition is out of date with respect to the current
+	// Spec of the resource.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Last time the resource was synced with the backend AWS service API.
+	// Only set on the ACK.ResourceSynced condition. The status of a resource
+	// is not patched when a sync leaves it unchanged, so this is the time of
+	// the last sync that changed the status.
+	// +optional
+	LastSyncedTime *metav1.Time `json:"lastSyncedTime,omitempty"`
 }
@@ -13,6 +13,10 @@
 
 package v1alpha1
 
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
 // AWSIdentifiers provide all unique ways to reference an AWS resource.
 type AWSIdentifiers struct {
 	// ARN is the AWS Resource Name for the resource. It is a globally
@@ -24,6 +28,16 @@ type AWSIdentifiers struct {
 	// AdditionalKeys represents any additional arbitrary identifiers used when
 	// describing the target resource.
 	AdditionalKeys map[string]string `json:"additionalKeys,omitempty"`
+	// Region is the AWS region in which the resource resides. When omitted,
+	// the region is determined the same way as for any other resource in the
+	// namespace.
+	Region *AWSRegion `json:"region,omitempty"`
+	// OwnerAccountID is the AWS account in which the resource resides. When
+	// omitted, the account is determined the same way as for any other
+	// resource in the namespace. Accounts other than the one of the namespace
+	// are only allowed if the service controller is started with the
+	// `--allow-cross-account-adoption` flag.
+	OwnerAccountID *AWSAccountID `json:"ownerAccountID,omitempty"`
 }
 
 // TargetKubernetesResource provides all the values necessary to identify a given ACK type
@@ -34,6 +48,14 @@ type TargetKubernetesResource struct {
 	// +kubebuilder:validation:Required
 	Kind     string             `json:"kind"`
 	Metadata *PartialObjectMeta `json:"metadata,omitempty"`
+	// Spec contains Spec fields of the target resource that override the
+	// values observed in the AWS resource when the target resource is
+	// created. Objects are merged recursively, any other value replaces the
+	// observed value.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	// +optional
+	Spec *runtime.RawExtension `json:"spec,omitempty"`
 }
 
 // AWSResourceReferenceWrapper provides a wrapper around *AWSResourceReference
@@ -50,4 +72,14 @@ type AWSResourceReferenceWrapper struct {
 // k8s resource for finding the identifier(Id/ARN/Name)
 type AWSResourceReference struct {
 	Name *string `json:"name,omitempty"`
+	// Namespace is the namespace of the referenced k8s resource. When omitted,
+	// the referenced resource is looked up in the namespace of the referencing
+	// resource. Referencing a resource in another namespace is only permitted
+	// when the target namespace allows it using the
+	// `services.k8s.aws/allow-references-from` annotation.
+	Namespace *string `json:"namespace,omitempty"`
+	// ARN is the AWS Resource Name of the referenced AWS resource. When set,
+	// the identifier is taken directly from the ARN and no k8s resource is
+	// looked up. ARN cannot be used together with Name or Namespace.
+	ARN *AWSResourceName `json:"arn,omitempty"`
 }
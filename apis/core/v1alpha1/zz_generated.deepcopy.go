@@ -37,6 +37,16 @@ func (in *AWSIdentifiers) DeepCopyInto(out *AWSIdentifiers) {
 			(*out)[key] = val
 		}
 	}
+	if in.Region != nil {
+		in, out := &in.Region, &out.Region
+		*out = new(AWSRegion)
+		**out = **in
+	}
+	if in.OwnerAccountID != nil {
+		in, out := &in.OwnerAccountID, &out.OwnerAccountID
+		*out = new(AWSAccountID)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSIdentifiers.
@@ -57,6 +67,16 @@ func (in *AWSResourceReference) DeepCopyInto(out *AWSResourceReference) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Namespace != nil {
+		in, out := &in.Namespace, &out.Namespace
+		*out = new(string)
+		**out = **in
+	}
+	if in.ARN != nil {
+		in, out := &in.ARN, &out.ARN
+		*out = new(AWSResourceName)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSResourceReference.
@@ -89,6 +109,26 @@ func (in *AWSResourceReferenceWrapper) DeepCopy() *AWSResourceReferenceWrapper {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSTagFilter) DeepCopyInto(out *AWSTagFilter) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSTagFilter.
+func (in *AWSTagFilter) DeepCopy() *AWSTagFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSTagFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AdoptedResource) DeepCopyInto(out *AdoptedResource) {
 	*out = *in
@@ -161,6 +201,11 @@ func (in *AdoptedResourceSpec) DeepCopyInto(out *AdoptedResourceSpec) {
 		*out = new(AWSIdentifiers)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DriftPolicy != nil {
+		in, out := &in.DriftPolicy, &out.DriftPolicy
+		*out = new(AdoptionDriftPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdoptedResourceSpec.
@@ -187,6 +232,16 @@ func (in *AdoptedResourceStatus) DeepCopyInto(out *AdoptedResourceStatus) {
 			}
 		}
 	}
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(AdoptedResourceTarget)
+		**out = **in
+	}
+	if in.ARN != nil {
+		in, out := &in.ARN, &out.ARN
+		*out = new(AWSResourceName)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdoptedResourceStatus.
@@ -199,6 +254,178 @@ func (in *AdoptedResourceStatus) DeepCopy() *AdoptedResourceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdoptedResourceTarget) DeepCopyInto(out *AdoptedResourceTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdoptedResourceTarget.
+func (in *AdoptedResourceTarget) DeepCopy() *AdoptedResourceTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(AdoptedResourceTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BulkAdoption) DeepCopyInto(out *BulkAdoption) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BulkAdoption.
+func (in *BulkAdoption) DeepCopy() *BulkAdoption {
+	if in == nil {
+		return nil
+	}
+	out := new(BulkAdoption)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BulkAdoption) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BulkAdoptionAWSFilter) DeepCopyInto(out *BulkAdoptionAWSFilter) {
+	*out = *in
+	if in.Identifiers != nil {
+		in, out := &in.Identifiers, &out.Identifiers
+		*out = make([]*AWSIdentifiers, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(AWSIdentifiers)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.TagFilters != nil {
+		in, out := &in.TagFilters, &out.TagFilters
+		*out = make([]*AWSTagFilter, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(AWSTagFilter)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BulkAdoptionAWSFilter.
+func (in *BulkAdoptionAWSFilter) DeepCopy() *BulkAdoptionAWSFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(BulkAdoptionAWSFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BulkAdoptionList) DeepCopyInto(out *BulkAdoptionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BulkAdoption, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BulkAdoptionList.
+func (in *BulkAdoptionList) DeepCopy() *BulkAdoptionList {
+	if in == nil {
+		return nil
+	}
+	out := new(BulkAdoptionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BulkAdoptionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BulkAdoptionSpec) DeepCopyInto(out *BulkAdoptionSpec) {
+	*out = *in
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(TargetKubernetesResource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AWS != nil {
+		in, out := &in.AWS, &out.AWS
+		*out = new(BulkAdoptionAWSFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NameTemplate != nil {
+		in, out := &in.NameTemplate, &out.NameTemplate
+		*out = new(string)
+		**out = **in
+	}
+	if in.DriftPolicy != nil {
+		in, out := &in.DriftPolicy, &out.DriftPolicy
+		*out = new(AdoptionDriftPolicy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BulkAdoptionSpec.
+func (in *BulkAdoptionSpec) DeepCopy() *BulkAdoptionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BulkAdoptionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BulkAdoptionStatus) DeepCopyInto(out *BulkAdoptionStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]*Condition, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Condition)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BulkAdoptionStatus.
+func (in *BulkAdoptionStatus) DeepCopy() *BulkAdoptionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BulkAdoptionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Condition) DeepCopyInto(out *Condition) {
 	*out = *in
@@ -216,6 +443,10 @@ func (in *Condition) DeepCopyInto(out *Condition) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.LastSyncedTime != nil {
+		in, out := &in.LastSyncedTime, &out.LastSyncedTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
@@ -313,6 +544,11 @@ func (in *TargetKubernetesResource) DeepCopyInto(out *TargetKubernetesResource)
 		*out = new(PartialObjectMeta)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetKubernetesResource.
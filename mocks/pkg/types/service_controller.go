@@ -19,6 +19,8 @@ import (
 	types "github.com/aws-controllers-k8s/runtime/pkg/types"
 
 	v1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+
+	webhook "github.com/aws-controllers-k8s/runtime/pkg/webhook"
 )
 
 // ServiceController is an autogenerated mock type for the ServiceController type
@@ -142,3 +144,19 @@ func (_m *ServiceController) WithResourceManagerFactories(_a0 []types.AWSResourc
 
 	return r0
 }
+
+// WithWebhookRegistry provides a mock function with given fields: _a0
+func (_m *ServiceController) WithWebhookRegistry(_a0 *webhook.WebhookRegistry) types.ServiceController {
+	ret := _m.Called(_a0)
+
+	var r0 types.ServiceController
+	if rf, ok := ret.Get(0).(func(*webhook.WebhookRegistry) types.ServiceController); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(types.ServiceController)
+		}
+	}
+
+	return r0
+}
@@ -0,0 +1,210 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package compare
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// Comparator returns true if the supplied values of a field are equal. The
+// values are the ones held by a Difference, so they may be pointers.
+type Comparator func(a, b interface{}) bool
+
+// JSONEqual is a Comparator considering equal the strings holding the same
+// JSON document, regardless of whitespace and of the order of object keys,
+// e.g. IAM policies normalised by the AWS service. Values that are not valid
+// JSON are compared as strings.
+func JSONEqual(a, b interface{}) bool {
+	as, aok := stringValue(a)
+	bs, bok := stringValue(b)
+	if !aok || !bok {
+		return aok == bok && indirectEqual(a, b)
+	}
+	var aDoc, bDoc interface{}
+	if json.Unmarshal([]byte(as), &aDoc) != nil ||
+		json.Unmarshal([]byte(bs), &bDoc) != nil {
+		return as == bs
+	}
+	return reflect.DeepEqual(aDoc, bDoc)
+}
+
+// CaseInsensitive is a Comparator considering equal the strings that only
+// differ by their case, e.g. enumeration values returned in upper case by
+// the AWS service.
+func CaseInsensitive(a, b interface{}) bool {
+	as, aok := stringValue(a)
+	bs, bok := stringValue(b)
+	if !aok || !bok {
+		return aok == bok && indirectEqual(a, b)
+	}
+	return strings.EqualFold(as, bs)
+}
+
+// SetEqual is a Comparator considering equal the slices holding the same
+// elements, regardless of their order and of duplicated elements.
+func SetEqual(a, b interface{}) bool {
+	av := deref(reflect.ValueOf(a))
+	bv := deref(reflect.ValueOf(b))
+	if !isList(av) || !isList(bv) {
+		return indirectEqual(a, b)
+	}
+	return containsAll(av, bv) && containsAll(bv, av)
+}
+
+// NumericTolerance returns a Comparator considering equal the numbers whose
+// difference is at most the supplied tolerance, e.g. floating point values
+// rounded by the AWS service.
+func NumericTolerance(tolerance float64) Comparator {
+	return func(a, b interface{}) bool {
+		af, aok := floatValue(a)
+		bf, bok := floatValue(b)
+		if !aok || !bok {
+			return aok == bok && indirectEqual(a, b)
+		}
+		return math.Abs(af-bf) <= tolerance
+	}
+}
+
+// ComparatorRegistry contains the Comparators of the fields of a resource
+// whose values are normalised by the AWS service, and which would otherwise
+// be reported as different on every reconciliation.
+type ComparatorRegistry struct {
+	comparators []pathComparator
+}
+
+// pathComparator is a Comparator registered for the fields at a path
+type pathComparator struct {
+	path    Path
+	compare Comparator
+}
+
+// Register registers the supplied Comparator for the fields at the supplied
+// path, e.g. "Spec.PolicyDocument". The path is parsed with NewPath and a
+// "*" part matches any field name, slice index or map key. Comparators
+// registered first take precedence. The registry is returned so that calls
+// can be chained.
+func (r *ComparatorRegistry) Register(
+	path string,
+	c Comparator,
+) *ComparatorRegistry {
+	r.comparators = append(r.comparators, pathComparator{NewPath(path), c})
+	return r
+}
+
+// ComparatorFor returns the Comparator registered for the field at the
+// supplied path, or nil if there is none
+func (r *ComparatorRegistry) ComparatorFor(path Path) Comparator {
+	if r == nil {
+		return nil
+	}
+	for _, pc := range r.comparators {
+		if path.Len() == pc.path.Len() && path.HasPrefix(pc.path) {
+			return pc.compare
+		}
+	}
+	return nil
+}
+
+// Filter returns a Delta containing the Differences of the supplied Delta,
+// except the ones for which a Comparator is registered and reports the
+// values as equal
+func (r *ComparatorRegistry) Filter(delta *Delta) *Delta {
+	return delta.Filter(func(diff *Difference) bool {
+		c := r.ComparatorFor(diff.Path)
+		return c == nil || !c(diff.A, diff.B)
+	})
+}
+
+// NewComparatorRegistry returns a new, empty ComparatorRegistry
+func NewComparatorRegistry() *ComparatorRegistry {
+	return &ComparatorRegistry{}
+}
+
+// deref dereferences the supplied value until it is neither a pointer nor
+// an interface
+func deref(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// indirectEqual returns true if the values held by, or pointed to by, the
+// supplied values are deeply equal
+func indirectEqual(a, b interface{}) bool {
+	return reflect.DeepEqual(
+		valueInterface(deref(reflect.ValueOf(a))),
+		valueInterface(deref(reflect.ValueOf(b))),
+	)
+}
+
+// stringValue returns the string held by, or pointed to by, the supplied
+// value, and whether there is one
+func stringValue(i interface{}) (string, bool) {
+	v := deref(reflect.ValueOf(i))
+	if !v.IsValid() || v.Kind() != reflect.String {
+		return "", false
+	}
+	return v.String(), true
+}
+
+// floatValue returns the number held by, or pointed to by, the supplied
+// value, and whether there is one
+func floatValue(i interface{}) (float64, bool) {
+	v := deref(reflect.ValueOf(i))
+	if !v.IsValid() {
+		return 0, false
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// isList returns true if the supplied value is a slice or an array
+func isList(v reflect.Value) bool {
+	return v.IsValid() && (v.Kind() == reflect.Slice || v.Kind() == reflect.Array)
+}
+
+// containsAll returns true if every element of the list b is equal to an
+// element of the list a
+func containsAll(a, b reflect.Value) bool {
+	for i := 0; i < b.Len(); i++ {
+		found := false
+		for j := 0; j < a.Len(); j++ {
+			if reflect.DeepEqual(
+				valueInterface(deref(a.Index(j))),
+				valueInterface(deref(b.Index(i))),
+			) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,115 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package compare_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws-controllers-k8s/runtime/pkg/compare"
+)
+
+func TestComparators(t *testing.T) {
+	require := require.New(t)
+
+	policy := `{"Version": "2012-10-17", "Statement": []}`
+	require.True(compare.JSONEqual(&policy, `{"Statement":[],"Version":"2012-10-17"}`))
+	require.False(compare.JSONEqual(policy, `{"Version":"2008-10-17","Statement":[]}`))
+	require.True(compare.JSONEqual("not json", "not json"))
+	require.False(compare.JSONEqual("not json", "not  json"))
+	require.True(compare.JSONEqual((*string)(nil), nil))
+	require.False(compare.JSONEqual(&policy, nil))
+
+	enabled := "ENABLED"
+	require.True(compare.CaseInsensitive(&enabled, "Enabled"))
+	require.False(compare.CaseInsensitive(&enabled, "Disabled"))
+
+	require.True(compare.SetEqual([]string{"a", "b", "a"}, []*string{strPtr("b"), strPtr("a")}))
+	require.False(compare.SetEqual([]string{"a", "b"}, []string{"a"}))
+
+	tolerance := compare.NumericTolerance(0.01)
+	require.True(tolerance(1.005, int64(1)))
+	require.False(tolerance(1.02, int64(1)))
+	require.False(tolerance(1.0, "1"))
+}
+
+func TestComparatorRegistry(t *testing.T) {
+	require := require.New(t)
+
+	registry := compare.NewComparatorRegistry().
+		Register("Spec.Policy", compare.JSONEqual).
+		Register("Spec.Rules[*].Protocol", compare.CaseInsensitive)
+
+	require.NotNil(registry.ComparatorFor(compare.NewPath("Spec.Policy")))
+	require.NotNil(registry.ComparatorFor(compare.NewPath("Spec.Rules[3].Protocol")))
+	require.Nil(registry.ComparatorFor(compare.NewPath("Spec.Policy.Version")))
+	require.Nil(registry.ComparatorFor(compare.NewPath("Spec")))
+
+	delta := compare.NewDelta()
+	delta.Add("Spec.Policy", `{"a": 1}`, `{"a":1}`)
+	delta.Add("Spec.Rules[0].Protocol", "tcp", "TCP")
+	delta.Add("Spec.Rules[1].Protocol", "tcp", "udp")
+	delta.Add("Spec.Name", "a", "b")
+
+	filtered := registry.Filter(delta)
+	require.Len(filtered.Differences, 2)
+	require.True(filtered.DifferentAt("Spec.Rules.1.Protocol"))
+	require.True(filtered.DifferentAt("Spec.Name"))
+	require.False(filtered.DifferentAt("Spec.Policy"))
+	// The supplied Delta is left untouched
+	require.Len(delta.Differences, 4)
+
+	require.Len(filtered.Without("Spec.Rules").Differences, 1)
+	require.Len(filtered.Without("Spec.*.*.Protocol", "Spec.Name").Differences, 0)
+	// The JSON names of the fields are accepted as well
+	require.Len(filtered.Without("spec.rules").Differences, 1)
+	require.Len(filtered.Without("spec.rules.*.protocol", "spec.name").Differences, 0)
+}
+
+func TestDeepDelta_Comparators(t *testing.T) {
+	require := require.New(t)
+
+	type document struct {
+		Policy   *string
+		Protocol string
+		Weight   float64
+		Zones    []string
+	}
+	a := document{
+		Policy:   strPtr(`{"Version": "2012-10-17"}`),
+		Protocol: "tcp",
+		Weight:   0.5,
+		Zones:    []string{"us-west-2a", "us-west-2b"},
+	}
+	b := document{
+		Policy:   strPtr(`{"Version":"2012-10-17"}`),
+		Protocol: "TCP",
+		Weight:   0.5001,
+		Zones:    []string{"us-west-2b", "us-west-2a", "us-west-2a"},
+	}
+	require.Len(compare.DeepDelta(a, b).Differences, 6)
+
+	registry := compare.NewComparatorRegistry().
+		Register("Policy", compare.JSONEqual).
+		Register("Protocol", compare.CaseInsensitive).
+		Register("Weight", compare.NumericTolerance(0.001)).
+		Register("Zones", compare.SetEqual)
+	require.Empty(compare.DeepDelta(a, b, compare.Comparators(registry)).Differences)
+
+	b.Zones = []string{"us-west-2c"}
+	delta := compare.DeepDelta(a, b, compare.Comparators(registry))
+	require.Len(delta.Differences, 1)
+	require.Equal("Zones", delta.Differences[0].Path.String())
+}
@@ -0,0 +1,159 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package compare
+
+import "reflect"
+
+// CopyPaths sets the fields of the supplied destination object at the supplied
+// paths to the values they have in the supplied source object. The paths are
+// dotted field paths that may contain `[*]` wildcards, e.g. `Spec.Title` or
+// `Spec.Chapters[*].Name`, like the immutable fields of a resource. A field
+// that is not set in the source object is reset to its zero value. The
+// destination object must be a pointer, and the values are copied shallowly.
+// Paths that cannot be resolved against the destination object are skipped.
+func CopyPaths(dst interface{}, src interface{}, paths ...string) {
+	dv := reflect.ValueOf(dst)
+	if !dv.IsValid() || dv.Kind() != reflect.Ptr || dv.IsNil() {
+		return
+	}
+	for _, path := range paths {
+		p := NewPath(path)
+		if isRootPath(p) {
+			continue
+		}
+		copyPath(dv.Elem(), reflect.ValueOf(src), p.segments)
+	}
+}
+
+// copyPath sets the field of the supplied destination value at the supplied
+// segments to the value it has in the supplied source value, which is invalid
+// when the source has no such field
+func copyPath(dst reflect.Value, src reflect.Value, segments []Segment) {
+	src = indirectValue(src)
+	if len(segments) == 0 {
+		if !dst.CanSet() {
+			return
+		}
+		if src.IsValid() && src.Type().AssignableTo(dst.Type()) {
+			dst.Set(src)
+		} else if src.IsValid() && dst.Kind() == reflect.Ptr &&
+			src.Type().AssignableTo(dst.Type().Elem()) {
+			ptr := reflect.New(src.Type())
+			ptr.Elem().Set(src)
+			dst.Set(ptr)
+		} else {
+			dst.Set(reflect.Zero(dst.Type()))
+		}
+		return
+	}
+	for dst.Kind() == reflect.Ptr {
+		if dst.IsNil() {
+			// Nothing to reset below a field that is not set, unless the
+			// source sets it
+			if !src.IsValid() || !dst.CanSet() {
+				return
+			}
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		dst = dst.Elem()
+	}
+	s, rest := segments[0], segments[1:]
+	switch {
+	case s.Kind == SegmentField && dst.Kind() == reflect.Struct:
+		field := dst.FieldByName(s.Name)
+		if !field.IsValid() {
+			return
+		}
+		var srcField reflect.Value
+		if src.IsValid() && src.Kind() == reflect.Struct {
+			srcField = src.FieldByName(s.Name)
+		}
+		copyPath(field, srcField, rest)
+	case (s.Kind == SegmentIndex || s.Kind == SegmentWildcard) &&
+		(dst.Kind() == reflect.Slice || dst.Kind() == reflect.Array):
+		for i := 0; i < dst.Len(); i++ {
+			if s.Kind == SegmentIndex && s.Index != i {
+				continue
+			}
+			var srcElem reflect.Value
+			if src.IsValid() && (src.Kind() == reflect.Slice || src.Kind() == reflect.Array) &&
+				i < src.Len() {
+				srcElem = src.Index(i)
+			}
+			copyPath(dst.Index(i), srcElem, rest)
+		}
+	case s.Kind != SegmentIndex && dst.Kind() == reflect.Map &&
+		dst.Type().Key().Kind() == reflect.String:
+		keys := []reflect.Value{}
+		if s.Kind == SegmentWildcard {
+			keys = dst.MapKeys()
+		} else {
+			keys = append(keys, reflect.ValueOf(s.Name).Convert(dst.Type().Key()))
+		}
+		for _, key := range keys {
+			var srcElem reflect.Value
+			if src.IsValid() && src.Kind() == reflect.Map &&
+				src.Type().Key() == dst.Type().Key() {
+				srcElem = src.MapIndex(key)
+			}
+			copyMapElem(dst, key, srcElem, rest)
+		}
+	}
+}
+
+// copyMapElem sets the field of the element of the supplied destination map
+// at the supplied key and segments to the value it has in the supplied source
+// element. Map elements are not addressable, so the element is copied, set
+// and stored back, or removed when it is reset as a whole.
+func copyMapElem(
+	dst reflect.Value,
+	key reflect.Value,
+	srcElem reflect.Value,
+	segments []Segment,
+) {
+	current := dst.MapIndex(key)
+	if len(segments) == 0 && (!srcElem.IsValid() || !srcElem.Type().AssignableTo(dst.Type().Elem())) {
+		if current.IsValid() {
+			dst.SetMapIndex(key, reflect.Value{})
+		}
+		return
+	}
+	if !current.IsValid() && !srcElem.IsValid() {
+		return
+	}
+	if dst.IsNil() {
+		if !dst.CanSet() {
+			return
+		}
+		dst.Set(reflect.MakeMap(dst.Type()))
+	}
+	elem := reflect.New(dst.Type().Elem()).Elem()
+	if current.IsValid() {
+		elem.Set(current)
+	}
+	copyPath(elem, srcElem, segments)
+	dst.SetMapIndex(key, elem)
+}
+
+// indirectValue dereferences the supplied pointer and interface value, and
+// returns an invalid value if it is nil
+func indirectValue(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
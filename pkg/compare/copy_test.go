@@ -0,0 +1,88 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package compare_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws-controllers-k8s/runtime/pkg/compare"
+)
+
+func TestCopyPaths(t *testing.T) {
+	require := require.New(t)
+
+	src := &resource{Spec: spec{
+		Name: strPtr("latest"),
+		Rules: []rule{
+			{Name: "http", Enabled: boolPtr(true)},
+			{Name: "https"},
+		},
+		Labels:    map[string]string{"env": "prod"},
+		Endpoints: map[string]*rule{"api": {Name: "latest"}},
+	}}
+	dst := &resource{Spec: spec{
+		Name: strPtr("desired"),
+		Rules: []rule{
+			{Name: "web", Enabled: boolPtr(false)},
+			{Name: "tls"},
+			{Name: "extra"},
+		},
+		Labels: map[string]string{"env": "dev", "team": "payments"},
+		Endpoints: map[string]*rule{
+			"api":   {Name: "desired", Ports: []int64{443}},
+			"admin": {Name: "desired"},
+		},
+		Config: "desired",
+	}}
+
+	compare.CopyPaths(
+		dst, src,
+		"Spec.Name", "Spec.Rules[*].Name", "Spec.Labels.env",
+		"Spec.Endpoints[*].Name", "Spec.Config", "Spec.Unknown",
+	)
+	require.Equal("latest", *dst.Spec.Name)
+	// The values are copied, not shared
+	require.NotSame(src.Spec.Name, dst.Spec.Name)
+	// Elements missing from the source are reset
+	require.Equal([]rule{
+		{Name: "http", Enabled: boolPtr(false)},
+		{Name: "https"},
+		{Name: ""},
+	}, dst.Spec.Rules)
+	require.Equal(map[string]string{"env": "prod", "team": "payments"}, dst.Spec.Labels)
+	require.Equal("latest", dst.Spec.Endpoints["api"].Name)
+	require.Equal([]int64{443}, dst.Spec.Endpoints["api"].Ports)
+	require.Equal("", dst.Spec.Endpoints["admin"].Name)
+	require.Nil(dst.Spec.Config)
+
+	// Fields set in the source are set below unset fields of the destination
+	dst = &resource{}
+	compare.CopyPaths(dst, src, "Spec.Name", "Spec.Labels.env", "Spec.Rules[0].Enabled")
+	require.Equal("latest", *dst.Spec.Name)
+	require.Equal(map[string]string{"env": "prod"}, dst.Spec.Labels)
+	require.Nil(dst.Spec.Rules)
+
+	// Map entries missing from the source are removed
+	dst = &resource{Spec: spec{Labels: map[string]string{"team": "payments"}}}
+	compare.CopyPaths(dst, &resource{}, "Spec.Labels.team")
+	require.Empty(dst.Spec.Labels)
+
+	// Destinations that are not pointers are left alone
+	require.NotPanics(func() {
+		compare.CopyPaths(resource{}, src, "Spec.Name")
+		compare.CopyPaths(nil, src, "Spec.Name")
+	})
+}
@@ -0,0 +1,115 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package compare
+
+import "reflect"
+
+// DeclaredIn returns a new Delta containing the Differences of the Delta at
+// the fields that are declared in at least one of the supplied objects. A
+// field is declared if it, and every field containing it, is neither nil nor
+// a zero value. Differences at paths that cannot be resolved against an
+// object, e.g. because it has no field with that name, are always kept.
+func (d *Delta) DeclaredIn(objs ...interface{}) *Delta {
+	return d.Filter(func(diff *Difference) bool {
+		for _, obj := range objs {
+			if isDeclared(obj, diff.Path) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// DeclaredOrChanged returns a new Delta containing the Differences of the
+// Delta at the fields that are declared in the supplied object, as defined by
+// DeclaredIn, or whose paths are equal to, nested in or contain the path of
+// one of the Differences of the supplied changes.
+//
+// Comparing a desired CR with the latest observed state of its AWS resource,
+// with the Spec the user declared when it was last applied and the changes
+// the user made to the Spec since, this is a three-way delta that leaves alone
+// the fields the user never set, which may have been modified out-of-band,
+// while still enforcing the fields the user set or removed from the Spec.
+func (d *Delta) DeclaredOrChanged(declared interface{}, changes *Delta) *Delta {
+	return d.Filter(func(diff *Difference) bool {
+		return isDeclared(declared, diff.Path) || changes.overlaps(diff.Path)
+	})
+}
+
+// overlaps returns true if the supplied path is equal to, nested in or
+// contains the path of one of the Differences of the Delta
+func (d *Delta) overlaps(path Path) bool {
+	for _, diff := range d.Differences {
+		if path.HasPrefix(diff.Path) || diff.Path.HasPrefix(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// isDeclared returns true if the field at the supplied path is declared in
+// the supplied object, or if the path cannot be resolved against it
+func isDeclared(obj interface{}, path Path) bool {
+	v := reflect.ValueOf(obj)
+	if !v.IsValid() {
+		return false
+	}
+	if isRootPath(path) {
+		return true
+	}
+	t := v.Type()
+	for _, s := range path.segments {
+		if !isSet(v) {
+			return false
+		}
+		t, v = indirect(t, v)
+		if t == nil {
+			return false
+		}
+		switch {
+		case s.Kind == SegmentIndex && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array):
+			if s.Index >= v.Len() {
+				return false
+			}
+			v = v.Index(s.Index)
+			t = t.Elem()
+		case s.Kind != SegmentIndex && t.Kind() == reflect.Map && t.Key().Kind() == reflect.String:
+			v = v.MapIndex(reflect.ValueOf(s.Name).Convert(t.Key()))
+			t = t.Elem()
+		case s.Kind == SegmentField && t.Kind() == reflect.Struct:
+			sf, found := t.FieldByName(s.Name)
+			if !found {
+				return true
+			}
+			v = fieldByIndex(v, sf.Index)
+			t = sf.Type
+		default:
+			return true
+		}
+	}
+	return isSet(v)
+}
+
+// isSet returns true if the supplied value is neither invalid, nil nor a zero
+// value. Pointers to zero values are set.
+func isSet(v reflect.Value) bool {
+	if !v.IsValid() {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		return !v.IsNil()
+	}
+	return !v.IsZero()
+}
@@ -0,0 +1,97 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package compare_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws-controllers-k8s/runtime/pkg/compare"
+)
+
+type resource struct {
+	Spec spec
+}
+
+func TestDelta_DeclaredIn(t *testing.T) {
+	require := require.New(t)
+
+	delta := compare.NewDelta()
+	delta.Add("Spec.Name", "desired", "console")
+	delta.Add("Spec.Rules[0].Enabled", nil, true)
+	delta.Add("Spec.Rules[1].Name", nil, "console")
+	delta.Add("Spec.Labels.env", nil, "console")
+	delta.Add("Spec.Labels.team", nil, "console")
+	delta.Add("Spec.Config", nil, "console")
+	delta.Add("Spec.Unknown", nil, "console")
+
+	desired := &resource{Spec: spec{
+		Name:  strPtr("desired"),
+		Rules: []rule{{Name: "http", Enabled: boolPtr(false)}},
+	}}
+	lastApplied := &resource{Spec: spec{
+		Labels: map[string]string{"env": "prod"},
+	}}
+
+	declared := delta.DeclaredIn(desired, lastApplied)
+	paths := []string{}
+	for _, diff := range declared.Differences {
+		paths = append(paths, diff.Path.String())
+	}
+	require.Equal([]string{
+		// Set in the desired resource. Pointers to zero values are set.
+		"Spec.Name",
+		"Spec.Rules[0].Enabled",
+		// Removed from the Spec since it was last applied
+		"Spec.Labels.env",
+		// Unknown to the resource type
+		"Spec.Unknown",
+	}, paths)
+
+	// Without objects to resolve against, nothing is declared
+	require.Empty(delta.DeclaredIn().Differences)
+	require.Len(delta.DeclaredIn(nil, desired).Differences, 3)
+}
+
+func TestDelta_DeclaredOrChanged(t *testing.T) {
+	require := require.New(t)
+
+	delta := compare.NewDelta()
+	delta.Add("Spec.Name", "desired", "console")
+	delta.Add("Spec.Rules[0].Name", "http", "console")
+	delta.Add("Spec.Labels.env", nil, "console")
+	delta.Add("Spec.Labels.team", "desired", "console")
+	delta.Add("Spec.Config", "written", "console")
+
+	// The Config was written to the Spec by the controller, and the user
+	// added a team label and modified the first rule since the Spec was
+	// last applied
+	lastApplied := &resource{Spec: spec{
+		Labels: map[string]string{"env": "prod"},
+	}}
+	changes := compare.NewDelta()
+	changes.Add("Spec.Labels.team", "desired", nil)
+	changes.Add("Spec.Rules", []string{"http"}, nil)
+
+	paths := []string{}
+	for _, diff := range delta.DeclaredOrChanged(lastApplied, changes).Differences {
+		paths = append(paths, diff.Path.String())
+	}
+	require.Equal([]string{
+		"Spec.Rules[0].Name",
+		"Spec.Labels.env",
+		"Spec.Labels.team",
+	}, paths)
+}
@@ -0,0 +1,353 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package compare
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+// DeepDeltaOption configures the comparison made by DeepDelta
+type DeepDeltaOption func(*deepDeltaOptions)
+
+// deepDeltaOptions contains the options of a DeepDelta comparison
+type deepDeltaOptions struct {
+	// ignoredPaths contains the paths of the fields that are not compared
+	ignoredPaths []Path
+	// unorderedSlices contains the paths of the slices whose order is
+	// ignored
+	unorderedSlices []Path
+	// allSlicesUnordered is true if the order of every slice is ignored
+	allSlicesUnordered bool
+	// nilEqualsEmpty is true if nil and empty slices and maps are equal
+	nilEqualsEmpty bool
+	// comparators contains the Comparators of the fields that are not
+	// compared by value
+	comparators *ComparatorRegistry
+}
+
+// IgnorePaths returns a DeepDeltaOption skipping the comparison of the fields
+// at the supplied paths, e.g. "Spec.Tags", and of everything they contain.
+// The paths are parsed with NewPath and matched like in Path.Contains, so a
+// "*" part matches any field name, slice index or map key.
+func IgnorePaths(paths ...string) DeepDeltaOption {
+	return func(o *deepDeltaOptions) {
+		for _, p := range paths {
+			o.ignoredPaths = append(o.ignoredPaths, NewPath(p))
+		}
+	}
+}
+
+// UnorderedSlices returns a DeepDeltaOption comparing the slices at the
+// supplied paths regardless of the order of their elements. The paths are
+// parsed with NewPath and matched like in Path.Contains, so a "*" part
+// matches any field name, slice index or map key. If no path is supplied,
+// the order of every slice is ignored. Unordered slices that differ
+// are reported as a single Difference at the path of the slice.
+func UnorderedSlices(paths ...string) DeepDeltaOption {
+	return func(o *deepDeltaOptions) {
+		if len(paths) == 0 {
+			o.allSlicesUnordered = true
+		}
+		for _, p := range paths {
+			o.unorderedSlices = append(o.unorderedSlices, NewPath(p))
+		}
+	}
+}
+
+// NilEqualsEmpty returns a DeepDeltaOption considering nil slices and maps
+// equal to empty ones
+func NilEqualsEmpty() DeepDeltaOption {
+	return func(o *deepDeltaOptions) {
+		o.nilEqualsEmpty = true
+	}
+}
+
+// Comparators returns a DeepDeltaOption comparing the fields for which a
+// Comparator is registered in the supplied ComparatorRegistry with that
+// Comparator instead of walking them. A difference is added at the path of
+// the field if the Comparator reports its values as different.
+func Comparators(registry *ComparatorRegistry) DeepDeltaOption {
+	return func(o *deepDeltaOptions) {
+		o.comparators = registry
+	}
+}
+
+// DeepDelta returns a Delta containing the differences between the supplied
+// values, which are usually the Spec of two resources. Structs, pointers,
+// interfaces, slices, arrays and maps are walked, and every difference is
+// added with the path of the field, slice index or map key that differs, e.g.
+// `Tags[0].Key` or `Labels["env"]`. Struct fields are named after their Go
+// field name and unexported ones are ignored. Structs with a semantic
+// equality function in `k8s.io/apimachinery/pkg/api/equality.Semantic` or
+// an `Equal` method, such as `metav1.Time` and `time.Time`, and structs with
+// only unexported fields are compared as a whole.
+func DeepDelta(a, b interface{}, opts ...DeepDeltaOption) *Delta {
+	o := &deepDeltaOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	w := &deepDeltaWalker{
+		delta: NewDelta(),
+		opts:  o,
+	}
+	w.walk(Path{}, reflect.ValueOf(a), reflect.ValueOf(b))
+	return w.delta
+}
+
+// deepDeltaWalker walks two values and records their differences
+type deepDeltaWalker struct {
+	delta *Delta
+	opts  *deepDeltaOptions
+}
+
+// add adds a Difference at the supplied path. Differences between the
+// supplied values themselves have the same empty path as the ones added with
+// `Delta.Add("", ...)`.
+func (w *deepDeltaWalker) add(path Path, a, b reflect.Value) {
+	if path.Len() == 0 {
+		path = NewPath("")
+	}
+	w.delta.Differences = append(w.delta.Differences, &Difference{
+		Path: path,
+		A:    valueInterface(a),
+		B:    valueInterface(b),
+	})
+}
+
+// walk compares the supplied values found at the supplied path
+func (w *deepDeltaWalker) walk(path Path, a, b reflect.Value) {
+	for _, ignored := range w.opts.ignoredPaths {
+		if path.HasPrefix(ignored) {
+			return
+		}
+	}
+	if c := w.opts.comparators.ComparatorFor(path); c != nil {
+		if !c(valueInterface(a), valueInterface(b)) {
+			w.add(path, a, b)
+		}
+		return
+	}
+	if !a.IsValid() || !b.IsValid() {
+		if a.IsValid() != b.IsValid() {
+			w.add(path, a, b)
+		}
+		return
+	}
+	if a.Type() != b.Type() {
+		w.add(path, a, b)
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				w.add(path, a, b)
+			}
+			return
+		}
+		w.walk(path, a.Elem(), b.Elem())
+	case reflect.Struct:
+		if isOpaqueStruct(a.Type()) {
+			w.walkLeaf(path, a, b)
+			return
+		}
+		for i := 0; i < a.NumField(); i++ {
+			field := a.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			w.walk(
+				path.with(Segment{Kind: SegmentField, Name: field.Name}),
+				a.Field(i), b.Field(i),
+			)
+		}
+	case reflect.Slice:
+		if a.IsNil() != b.IsNil() && !(w.opts.nilEqualsEmpty && a.Len() == 0 && b.Len() == 0) {
+			w.add(path, a, b)
+			return
+		}
+		w.walkList(path, a, b)
+	case reflect.Array:
+		w.walkList(path, a, b)
+	case reflect.Map:
+		if a.IsNil() != b.IsNil() && !(w.opts.nilEqualsEmpty && a.Len() == 0 && b.Len() == 0) {
+			w.add(path, a, b)
+			return
+		}
+		w.walkMap(path, a, b)
+	default:
+		w.walkLeaf(path, a, b)
+	}
+}
+
+// walkLeaf compares the supplied values as a whole, with their semantic
+// equality function or `Equal` method if they have one
+func (w *deepDeltaWalker) walkLeaf(path Path, a, b reflect.Value) {
+	var equal bool
+	switch {
+	case hasSemanticEquality(a.Type()):
+		equal = equality.Semantic.DeepEqual(a.Interface(), b.Interface())
+	case hasEqualMethod(a.Type()):
+		equal = a.MethodByName("Equal").Call([]reflect.Value{b})[0].Bool()
+	default:
+		equal = reflect.DeepEqual(a.Interface(), b.Interface())
+	}
+	if !equal {
+		w.add(path, a, b)
+	}
+}
+
+// equal returns true if the supplied values have no difference, regardless
+// of the paths that are ignored
+func (w *deepDeltaWalker) equal(a, b reflect.Value) bool {
+	sub := &deepDeltaWalker{
+		delta: NewDelta(),
+		opts: &deepDeltaOptions{
+			unorderedSlices:    w.opts.unorderedSlices,
+			allSlicesUnordered: w.opts.allSlicesUnordered,
+			nilEqualsEmpty:     w.opts.nilEqualsEmpty,
+			comparators:        w.opts.comparators,
+		},
+	}
+	sub.walk(Path{}, a, b)
+	return len(sub.delta.Differences) == 0
+}
+
+// walkList compares the supplied slices or arrays element by element, or
+// regardless of their order if they are unordered. Elements missing from
+// either list are compared to an invalid value.
+func (w *deepDeltaWalker) walkList(path Path, a, b reflect.Value) {
+	if w.isUnordered(path) {
+		if !w.unorderedEqual(a, b) {
+			w.add(path, a, b)
+		}
+		return
+	}
+	for i := 0; i < a.Len() || i < b.Len(); i++ {
+		var aElem, bElem reflect.Value
+		if i < a.Len() {
+			aElem = a.Index(i)
+		}
+		if i < b.Len() {
+			bElem = b.Index(i)
+		}
+		w.walk(path.with(Segment{Kind: SegmentIndex, Index: i}), aElem, bElem)
+	}
+}
+
+// walkMap compares the values of the supplied maps key by key. Keys missing
+// from either map are compared to an invalid value.
+func (w *deepDeltaWalker) walkMap(path Path, a, b reflect.Value) {
+	keys := map[string]reflect.Value{}
+	for _, m := range []reflect.Value{a, b} {
+		for _, k := range m.MapKeys() {
+			keys[fmt.Sprint(k.Interface())] = k
+		}
+	}
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		k := keys[name]
+		w.walk(
+			path.with(Segment{Kind: SegmentKey, Name: name}),
+			a.MapIndex(k), b.MapIndex(k),
+		)
+	}
+}
+
+// isUnordered returns true if the order of the slice at the supplied path is
+// ignored
+func (w *deepDeltaWalker) isUnordered(path Path) bool {
+	if w.opts.allSlicesUnordered {
+		return true
+	}
+	for _, unordered := range w.opts.unorderedSlices {
+		if path.Len() == unordered.Len() && path.HasPrefix(unordered) {
+			return true
+		}
+	}
+	return false
+}
+
+// unorderedEqual returns true if every element of either supplied list is
+// equal to a distinct element of the other list
+func (w *deepDeltaWalker) unorderedEqual(a, b reflect.Value) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	matched := make([]bool, b.Len())
+	for i := 0; i < a.Len(); i++ {
+		found := false
+		for j := 0; j < b.Len(); j++ {
+			if !matched[j] && w.equal(a.Index(i), b.Index(j)) {
+				matched[j] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// isOpaqueStruct returns true if the supplied struct type is compared as a
+// whole instead of field by field, because it has a semantic equality
+// function or an `Equal` method, e.g. `metav1.Time`, or because it only has
+// unexported fields
+func isOpaqueStruct(t reflect.Type) bool {
+	if hasSemanticEquality(t) || hasEqualMethod(t) {
+		return true
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath == "" {
+			return false
+		}
+	}
+	return t.NumField() > 0
+}
+
+// hasSemanticEquality returns true if a semantic equality function is
+// registered for the supplied type in `equality.Semantic`
+func hasSemanticEquality(t reflect.Type) bool {
+	_, found := equality.Semantic.Equalities[t]
+	return found
+}
+
+// hasEqualMethod returns true if the supplied type has an `Equal` method
+// accepting a value of the same type and returning a boolean, like
+// `time.Time`
+func hasEqualMethod(t reflect.Type) bool {
+	m, found := t.MethodByName("Equal")
+	return found && m.Type.NumIn() == 2 && m.Type.In(1) == t &&
+		m.Type.NumOut() == 1 && m.Type.Out(0).Kind() == reflect.Bool
+}
+
+// valueInterface returns the value held by the supplied reflect.Value, or
+// nil if it is invalid
+func valueInterface(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
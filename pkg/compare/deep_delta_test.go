@@ -0,0 +1,216 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package compare_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws-controllers-k8s/runtime/pkg/compare"
+)
+
+type tag struct {
+	Key   *string
+	Value *string
+}
+
+type rule struct {
+	Name    string
+	Ports   []int64
+	Enabled *bool
+}
+
+type spec struct {
+	Name        *string
+	Tags        []*tag
+	Rules       []rule
+	Labels      map[string]string
+	Endpoints   map[string]*rule
+	CreatedAt   *metav1.Time
+	ExpiresAt   time.Time
+	Config      interface{}
+	Fingerprint [2]byte
+	unexported  string
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func newSpec() *spec {
+	now := metav1.NewTime(time.Unix(100, 0))
+	return &spec{
+		Name: strPtr("my-bucket"),
+		Tags: []*tag{
+			{Key: strPtr("env"), Value: strPtr("prod")},
+			{Key: strPtr("team"), Value: strPtr("storage")},
+		},
+		Rules: []rule{
+			{Name: "http", Ports: []int64{80, 8080}, Enabled: boolPtr(true)},
+		},
+		Labels: map[string]string{"app": "web"},
+		Endpoints: map[string]*rule{
+			"public": {Name: "public"},
+		},
+		CreatedAt:   &now,
+		ExpiresAt:   time.Unix(300, 0).UTC(),
+		Config:      map[string]interface{}{"size": 1},
+		Fingerprint: [2]byte{1, 2},
+		unexported:  "a",
+	}
+}
+
+func diffPaths(d *compare.Delta) []string {
+	res := []string{}
+	for _, diff := range d.Differences {
+		res = append(res, diff.Path.String())
+	}
+	return res
+}
+
+func TestDeepDelta_Equal(t *testing.T) {
+	require := require.New(t)
+	a := newSpec()
+	b := newSpec()
+	b.unexported = "b"
+	b.ExpiresAt = b.ExpiresAt.In(time.FixedZone("UTC+1", 3600))
+	require.Empty(compare.DeepDelta(a, b).Differences)
+	require.Empty(compare.DeepDelta(nil, nil).Differences)
+	require.Empty(compare.DeepDelta(*a, *newSpec()).Differences)
+}
+
+func TestDeepDelta_Differences(t *testing.T) {
+	require := require.New(t)
+	a := newSpec()
+	b := newSpec()
+	b.Name = strPtr("other-bucket")
+	b.Tags[1].Value = strPtr("network")
+	b.Tags = append(b.Tags, &tag{Key: strPtr("owner")})
+	b.Rules[0].Ports[1] = 8443
+	b.Rules[0].Enabled = nil
+	b.Labels["app"] = "api"
+	b.Labels["tier"] = "backend"
+	delete(b.Endpoints, "public")
+	later := metav1.NewTime(time.Unix(200, 0))
+	b.CreatedAt = &later
+	b.Config = map[string]interface{}{"size": 2}
+	b.ExpiresAt = b.ExpiresAt.Add(time.Second)
+	b.Fingerprint[0] = 0
+
+	d := compare.DeepDelta(a, b)
+	require.Equal([]string{
+		"Name",
+		"Tags[1].Value",
+		"Tags[2]",
+		"Rules[0].Ports[1]",
+		"Rules[0].Enabled",
+		`Labels["app"]`,
+		`Labels["tier"]`,
+		`Endpoints["public"]`,
+		"CreatedAt",
+		"ExpiresAt",
+		`Config["size"]`,
+		"Fingerprint[0]",
+	}, diffPaths(d))
+
+	require.Equal("my-bucket", d.Differences[0].A)
+	require.Equal("other-bucket", d.Differences[0].B)
+	require.Nil(d.Differences[2].A)
+	require.Equal(int64(8080), d.Differences[3].A)
+	require.Equal(int64(8443), d.Differences[3].B)
+	require.Nil(d.Differences[6].A)
+	require.Equal("backend", d.Differences[6].B)
+
+	require.True(d.DifferentAt("Tags"))
+	require.True(d.DifferentAt("Tags[2]"))
+	require.True(d.DifferentAt(`Labels["app"]`))
+	require.True(d.DifferentAt("Labels.app"))
+	require.False(d.DifferentAt("Labels.web"))
+	require.False(d.DifferentAt("Tags[0]"))
+
+	// Differences between the values themselves
+	d = compare.DeepDelta(a, nil)
+	require.Len(d.Differences, 1)
+	require.True(d.DifferentAt(""))
+	d = compare.DeepDelta(a, *b)
+	require.Len(d.Differences, 1)
+	require.True(d.DifferentAt(""))
+}
+
+func TestDeepDelta_IgnorePaths(t *testing.T) {
+	require := require.New(t)
+	a := newSpec()
+	b := newSpec()
+	b.Tags[0].Value = strPtr("dev")
+	b.Tags[1].Value = strPtr("network")
+	b.Labels["app"] = "api"
+	b.Name = nil
+
+	d := compare.DeepDelta(a, b, compare.IgnorePaths("Tags[*].Value", "Labels"))
+	require.Equal([]string{"Name"}, diffPaths(d))
+	d = compare.DeepDelta(a, b, compare.IgnorePaths("Tags.0", "Name", `Labels["app"]`))
+	require.Equal([]string{"Tags[1].Value"}, diffPaths(d))
+}
+
+func TestDeepDelta_UnorderedSlices(t *testing.T) {
+	require := require.New(t)
+	a := newSpec()
+	b := newSpec()
+	b.Tags[0], b.Tags[1] = b.Tags[1], b.Tags[0]
+	b.Rules[0].Ports = []int64{8080, 80}
+
+	require.Len(compare.DeepDelta(a, b).Differences, 6)
+	require.Empty(compare.DeepDelta(a, b, compare.UnorderedSlices()).Differences)
+	d := compare.DeepDelta(a, b, compare.UnorderedSlices("Rules.*.Ports"))
+	require.Equal([]string{
+		"Tags[0].Key",
+		"Tags[0].Value",
+		"Tags[1].Key",
+		"Tags[1].Value",
+	}, diffPaths(d))
+
+	// Unordered slices that differ are reported as a whole
+	b.Tags[0].Value = strPtr("network")
+	d = compare.DeepDelta(a, b, compare.UnorderedSlices("Tags", "Rules.*.Ports"))
+	require.Equal([]string{"Tags"}, diffPaths(d))
+	b.Tags = append(b.Tags, b.Tags[0])
+	d = compare.DeepDelta(a, b, compare.UnorderedSlices())
+	require.Equal([]string{"Tags"}, diffPaths(d))
+}
+
+func TestDeepDelta_NilEqualsEmpty(t *testing.T) {
+	require := require.New(t)
+	a := newSpec()
+	b := newSpec()
+	a.Tags = nil
+	b.Tags = []*tag{}
+	a.Labels = map[string]string{}
+	b.Labels = nil
+
+	d := compare.DeepDelta(a, b)
+	require.Equal([]string{"Tags", "Labels"}, diffPaths(d))
+	require.Empty(compare.DeepDelta(a, b, compare.NilEqualsEmpty()).Differences)
+
+	// Nil and non-empty values still differ
+	b.Labels = map[string]string{"app": "web"}
+	d = compare.DeepDelta(a, b, compare.NilEqualsEmpty())
+	require.Equal([]string{`Labels["app"]`}, diffPaths(d))
+}
@@ -34,6 +34,38 @@ func (d *Delta) DifferentAt(subject string) bool {
 	return false
 }
 
+// Filter returns a new Delta containing the Differences of the Delta for which
+// the supplied function returns true
+func (d *Delta) Filter(keep func(*Difference) bool) *Delta {
+	filtered := NewDelta()
+	for _, diff := range d.Differences {
+		if keep(diff) {
+			filtered.Differences = append(filtered.Differences, diff)
+		}
+	}
+	return filtered
+}
+
+// Without returns a new Delta containing the Differences of the Delta, except
+// the ones at, or nested in, the supplied paths, e.g. "Spec.Tags". The paths
+// are parsed with NewPath, so a "*" part matches any field name, slice index
+// or map key. Field names are matched case-insensitively, so the JSON names of
+// the fields are accepted as well, e.g. "spec.tags".
+func (d *Delta) Without(paths ...string) *Delta {
+	ignored := make([]Path, 0, len(paths))
+	for _, p := range paths {
+		ignored = append(ignored, NewPath(p))
+	}
+	return d.Filter(func(diff *Difference) bool {
+		for _, p := range ignored {
+			if diff.Path.HasPrefixFold(p) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
 // Add adds a new Difference to the Delta
 func (d *Delta) Add(
 	path string,
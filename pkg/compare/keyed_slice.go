@@ -0,0 +1,156 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package compare
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// KeyFunc returns the key identifying the supplied element of a slice, e.g.
+// the Key of a tag or the ID of a security group rule. The key must be
+// comparable, or a pointer to a comparable value.
+type KeyFunc func(elem interface{}) interface{}
+
+// SliceSetEqual returns true if the supplied slices of structs or pointers to
+// structs contain the same elements regardless of order, matching the
+// elements by the key returned by the supplied KeyFunc. Elements with the
+// same key as an earlier element of the same slice are ignored.
+func SliceSetEqual(a, b interface{}, key KeyFunc, opts ...DeepDeltaOption) bool {
+	return len(SliceSetDelta("", a, b, key, opts...).Differences) == 0
+}
+
+// SliceMultisetEqual returns true if the supplied slices of structs or
+// pointers to structs contain the same elements, as many times, regardless of
+// order, matching the elements by the key returned by the supplied KeyFunc.
+func SliceMultisetEqual(a, b interface{}, key KeyFunc, opts ...DeepDeltaOption) bool {
+	return len(SliceMultisetDelta("", a, b, key, opts...).Differences) == 0
+}
+
+// SliceSetDelta returns a Delta containing the differences between the
+// supplied slices of structs or pointers to structs, found at the supplied
+// path, e.g. "Spec.Tags". The elements are matched by the key returned by
+// the supplied KeyFunc, regardless of order, and elements with the same key
+// as an earlier element of the same slice are ignored.
+//
+// An element that is only in one of the slices is reported as a Difference
+// at the path of its key, e.g. `Spec.Tags["env"]`, whose other value is nil.
+// The differences between elements with the same key are found with
+// DeepDelta and the supplied options, and reported at the path of their
+// fields, e.g. `Spec.Tags["env"].Value`.
+func SliceSetDelta(
+	path string,
+	a, b interface{},
+	key KeyFunc,
+	opts ...DeepDeltaOption,
+) *Delta {
+	return sliceKeyedDelta(path, a, b, key, false, opts)
+}
+
+// SliceMultisetDelta returns a Delta containing the differences between the
+// supplied slices of structs or pointers to structs like SliceSetDelta, but
+// considering the number of elements with each key. The elements with the
+// same key are matched in order, and the extra ones are reported as a
+// Difference at the path of their key.
+func SliceMultisetDelta(
+	path string,
+	a, b interface{},
+	key KeyFunc,
+	opts ...DeepDeltaOption,
+) *Delta {
+	return sliceKeyedDelta(path, a, b, key, true, opts)
+}
+
+// sliceKeyedDelta returns a Delta containing the differences between the
+// supplied slices, matching their elements by key. If multiset is false,
+// elements with the same key as an earlier element are ignored.
+func sliceKeyedDelta(
+	path string,
+	a, b interface{},
+	key KeyFunc,
+	multiset bool,
+	opts []DeepDeltaOption,
+) *Delta {
+	o := &deepDeltaOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	w := &deepDeltaWalker{
+		delta: NewDelta(),
+		opts:  o,
+	}
+	var root Path
+	if path != "" {
+		root = NewPath(path)
+	}
+
+	aKeys, aElems := groupByKey(a, key, multiset)
+	bKeys, bElems := groupByKey(b, key, multiset)
+	for _, k := range aKeys {
+		elemPath := root.with(Segment{Kind: SegmentKey, Name: fmt.Sprint(k)})
+		as, bs := aElems[k], bElems[k]
+		for i := 0; i < len(as) || i < len(bs); i++ {
+			var aElem, bElem reflect.Value
+			if i < len(as) {
+				aElem = as[i]
+			}
+			if i < len(bs) {
+				bElem = bs[i]
+			}
+			w.walk(elemPath, aElem, bElem)
+		}
+	}
+	for _, k := range bKeys {
+		if _, found := aElems[k]; found {
+			continue
+		}
+		elemPath := root.with(Segment{Kind: SegmentKey, Name: fmt.Sprint(k)})
+		for _, bElem := range bElems[k] {
+			w.walk(elemPath, reflect.Value{}, bElem)
+		}
+	}
+	return w.delta
+}
+
+// groupByKey returns the keys of the elements of the supplied slice, in order
+// of appearance, and the elements with each key. If multiset is false, only
+// the first element with each key is kept.
+func groupByKey(
+	slice interface{},
+	key KeyFunc,
+	multiset bool,
+) ([]interface{}, map[interface{}][]reflect.Value) {
+	keys := []interface{}{}
+	elems := map[interface{}][]reflect.Value{}
+	v := reflect.ValueOf(slice)
+	if !v.IsValid() {
+		return keys, elems
+	}
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		// Should never happen... if it does, it's buggy code.
+		panic(fmt.Sprintf("expected a slice or an array, got %s", v.Type()))
+	}
+	for i := 0; i < v.Len(); i++ {
+		elem := v.Index(i)
+		k := valueInterface(deref(reflect.ValueOf(key(elem.Interface()))))
+		existing, found := elems[k]
+		if !found {
+			keys = append(keys, k)
+		} else if !multiset {
+			continue
+		}
+		elems[k] = append(existing, elem)
+	}
+	return keys, elems
+}
@@ -0,0 +1,105 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package compare_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws-controllers-k8s/runtime/pkg/compare"
+)
+
+func tagKey(elem interface{}) interface{} {
+	return elem.(*tag).Key
+}
+
+func TestSliceSetDelta(t *testing.T) {
+	require := require.New(t)
+
+	a := []*tag{
+		{Key: strPtr("env"), Value: strPtr("prod")},
+		{Key: strPtr("team"), Value: strPtr("ack")},
+		{Key: strPtr("owner"), Value: strPtr("alice")},
+	}
+	b := []*tag{
+		{Key: strPtr("team"), Value: strPtr("ack")},
+		{Key: strPtr("env"), Value: strPtr("dev")},
+		{Key: strPtr("cost-center"), Value: strPtr("42")},
+	}
+
+	require.True(compare.SliceSetEqual(a, a, tagKey))
+	require.True(compare.SliceSetEqual(a, []*tag{a[2], a[1], a[0], a[1]}, tagKey))
+	require.False(compare.SliceSetEqual(a, b, tagKey))
+	require.True(compare.SliceSetEqual(nil, []*tag{}, tagKey))
+
+	delta := compare.SliceSetDelta("Spec.Tags", a, b, tagKey)
+	require.Len(delta.Differences, 3)
+	// Changed element
+	require.Equal(`Spec.Tags["env"].Value`, delta.Differences[0].Path.String())
+	require.Equal("prod", delta.Differences[0].A)
+	require.Equal("dev", delta.Differences[0].B)
+	// Removed element
+	require.Equal(`Spec.Tags["owner"]`, delta.Differences[1].Path.String())
+	require.Equal(a[2], delta.Differences[1].A)
+	require.Nil(delta.Differences[1].B)
+	// Added element
+	require.Equal(`Spec.Tags["cost-center"]`, delta.Differences[2].Path.String())
+	require.Nil(delta.Differences[2].A)
+	require.Equal(b[2], delta.Differences[2].B)
+
+	require.True(delta.DifferentAt("Spec.Tags"))
+	require.True(delta.DifferentAt("Spec.Tags.env.Value"))
+
+	// Options are applied to the elements, at their full path
+	delta = compare.SliceSetDelta(
+		"Spec.Tags", a, b, tagKey, compare.IgnorePaths("Spec.Tags.*.Value"),
+	)
+	require.Len(delta.Differences, 2)
+}
+
+func TestSliceMultisetDelta(t *testing.T) {
+	require := require.New(t)
+
+	ruleKey := func(elem interface{}) interface{} {
+		return elem.(rule).Name
+	}
+	a := []rule{
+		{Name: "http", Ports: []int64{80}},
+		{Name: "http", Ports: []int64{8080}},
+		{Name: "ssh", Ports: []int64{22}},
+	}
+	b := []rule{
+		{Name: "ssh", Ports: []int64{22}},
+		{Name: "http", Ports: []int64{8080}},
+		{Name: "http", Ports: []int64{80}},
+	}
+
+	require.False(compare.SliceMultisetEqual(a, b, ruleKey))
+	require.True(compare.SliceMultisetEqual(a, []rule{a[0], a[2], a[1]}, ruleKey))
+	require.False(compare.SliceMultisetEqual(a, a[1:], ruleKey))
+	// Sets ignore the elements with the same key as an earlier one
+	require.True(compare.SliceSetEqual(a, []rule{a[2], a[0]}, ruleKey))
+
+	delta := compare.SliceMultisetDelta("Spec.Rules", a, a[1:], ruleKey)
+	require.Len(delta.Differences, 2)
+	require.Equal(`Spec.Rules["http"].Ports[0]`, delta.Differences[0].Path.String())
+	require.Equal(`Spec.Rules["http"]`, delta.Differences[1].Path.String())
+	require.Equal(a[1], delta.Differences[1].A)
+	require.Nil(delta.Differences[1].B)
+
+	require.Panics(func() {
+		compare.SliceMultisetDelta("Spec.Rules", a[0], b, ruleKey)
+	})
+}
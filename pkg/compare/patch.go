@@ -0,0 +1,176 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package compare
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// JSONPatchOperation is an operation of a JSON Patch document as defined in
+// RFC 6902
+type JSONPatchOperation struct {
+	// Op is the operation, one of "add", "remove" or "replace"
+	Op string `json:"op"`
+	// Path is the JSON Pointer of the field the operation applies to
+	Path string `json:"path"`
+	// Value is the value the field is set to by "add" and "replace"
+	// operations
+	Value interface{} `json:"value,omitempty"`
+}
+
+// JSONPatch returns the operations of the JSON Patch turning the second
+// resource under comparison into the first one, which is the desired
+// resource. Differences whose first value is nil remove the field, and
+// differences whose second value is nil add it. The removals of array
+// elements come last, from the highest index to the lowest, so that they
+// don't shift the indices of the other operations.
+func (d *Delta) JSONPatch(opts ...RenderOption) []JSONPatchOperation {
+	o := newRenderOptions(opts)
+	ops := make([]JSONPatchOperation, 0, len(d.Differences))
+	elemRemovals := []JSONPatchOperation{}
+	for _, diff := range d.Differences {
+		r := o.resolve(diff)
+		op := JSONPatchOperation{Path: r.pointer()}
+		switch {
+		case IsNil(diff.A):
+			op.Op = "remove"
+			if isElementPath(diff.Path) {
+				elemRemovals = append([]JSONPatchOperation{op}, elemRemovals...)
+				continue
+			}
+		case IsNil(diff.B):
+			op.Op = "add"
+			op.Value = r.valueOf(diff.A)
+		default:
+			op.Op = "replace"
+			op.Value = r.valueOf(diff.A)
+		}
+		ops = append(ops, op)
+	}
+	return append(ops, elemRemovals...)
+}
+
+// isElementPath returns true if the supplied Path is the one of an array
+// element
+func isElementPath(p Path) bool {
+	return p.Len() > 0 && p.segments[p.Len()-1].Kind == SegmentIndex
+}
+
+// ToJSONPatch returns the RFC 6902 JSON Patch document turning the second
+// resource under comparison into the first one, which is the desired
+// resource. See JSONPatch.
+func (d *Delta) ToJSONPatch(opts ...RenderOption) ([]byte, error) {
+	return json.Marshal(d.JSONPatch(opts...))
+}
+
+// ToMergePatch returns the RFC 7386 JSON Merge Patch document turning the
+// second resource under comparison into the first one, which is the desired
+// resource. Differences whose first value is nil remove the field. As merge
+// patches replace arrays as a whole, the differences within an array are
+// rendered as the whole array of the desired object, which must be supplied
+// with WithDesired.
+func (d *Delta) ToMergePatch(opts ...RenderOption) ([]byte, error) {
+	o := newRenderOptions(opts)
+	var patch interface{} = map[string]interface{}{}
+	for _, diff := range d.Differences {
+		r := o.resolve(diff)
+		if isRootPath(diff.Path) {
+			patch = r.valueOf(diff.A)
+			continue
+		}
+		tokens := r.tokens
+		value := r.valueOf(diff.A)
+		if r.firstIndex != -1 {
+			if !r.firstArray.IsValid() {
+				return nil, fmt.Errorf(
+					"cannot render the difference at %s in a merge patch "+
+						"without the desired array", diff.Path,
+				)
+			}
+			tokens = tokens[:r.firstIndex]
+			value = r.valueOf(r.firstArray.Interface())
+		}
+		patchMap, ok := patch.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		setMergePatchValue(patchMap, tokens, value)
+	}
+	return json.Marshal(patch)
+}
+
+// setMergePatchValue sets the supplied value at the supplied path of the
+// supplied merge patch, unless a parent of the path is already set to a
+// value replacing it as a whole
+func setMergePatchValue(
+	patch map[string]interface{},
+	tokens []string,
+	value interface{},
+) {
+	for _, token := range tokens[:len(tokens)-1] {
+		current, found := patch[token]
+		if !found {
+			child := map[string]interface{}{}
+			patch[token] = child
+			patch = child
+			continue
+		}
+		child, ok := current.(map[string]interface{})
+		if !ok {
+			return
+		}
+		patch = child
+	}
+	patch[tokens[len(tokens)-1]] = value
+}
+
+// UnifiedDiff returns a human-readable rendering of the Delta in the style of
+// a unified diff, meant for logs and Events. Every Difference is rendered as
+// a hunk whose header is its path, followed by the YAML representation of
+// the second value prefixed with "-" and of the first value prefixed with
+// "+", as the first resource under comparison is the desired one.
+func (d *Delta) UnifiedDiff(opts ...RenderOption) string {
+	o := newRenderOptions(opts)
+	var sb strings.Builder
+	if len(d.Differences) == 0 {
+		return ""
+	}
+	sb.WriteString("--- latest\n+++ desired\n")
+	for _, diff := range d.Differences {
+		r := o.resolve(diff)
+		sb.WriteString("@@ " + diff.Path.String() + " @@\n")
+		writeDiffLines(&sb, "-", r.valueOf(diff.B))
+		writeDiffLines(&sb, "+", r.valueOf(diff.A))
+	}
+	return sb.String()
+}
+
+// writeDiffLines writes the lines of the YAML representation of the supplied
+// value with the supplied prefix. Nothing is written for nil values.
+func writeDiffLines(sb *strings.Builder, prefix string, value interface{}) {
+	if value == nil {
+		return
+	}
+	out, err := yaml.Marshal(value)
+	if err != nil {
+		out = []byte(fmt.Sprintf("%v\n", value))
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(string(out), "\n"), "\n") {
+		sb.WriteString(prefix + line + "\n")
+	}
+}
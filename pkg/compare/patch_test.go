@@ -0,0 +1,156 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package compare_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	"github.com/aws-controllers-k8s/runtime/pkg/compare"
+)
+
+type bucketRule struct {
+	ID    *string `json:"id,omitempty"`
+	Ports []int64 `json:"ports,omitempty"`
+}
+
+type bucketSpec struct {
+	BucketName *string                         `json:"bucketName,omitempty"`
+	Rules      []*bucketRule                   `json:"rules,omitempty"`
+	Tags       map[string]*string              `json:"tags,omitempty"`
+	Password   *ackv1alpha1.SecretKeyReference `json:"password,omitempty"`
+	Token      *string                         `json:"token,omitempty"`
+}
+
+type bucket struct {
+	Spec bucketSpec `json:"spec"`
+}
+
+func newBuckets() (*bucket, *bucket) {
+	desired := &bucket{Spec: bucketSpec{
+		BucketName: strPtr("new-name"),
+		Rules: []*bucketRule{
+			{ID: strPtr("a"), Ports: []int64{80}},
+		},
+		Tags: map[string]*string{"env": strPtr("prod")},
+		Password: &ackv1alpha1.SecretKeyReference{
+			SecretReference: corev1.SecretReference{Name: "new-secret"},
+			Key:             "password",
+		},
+		Token: strPtr("new-token"),
+	}}
+	latest := &bucket{Spec: bucketSpec{
+		BucketName: strPtr("old-name"),
+		Rules: []*bucketRule{
+			{ID: strPtr("b"), Ports: []int64{80, 443, 8080}},
+			{ID: strPtr("c")},
+		},
+		Tags: map[string]*string{"team": strPtr("storage")},
+		Password: &ackv1alpha1.SecretKeyReference{
+			SecretReference: corev1.SecretReference{Name: "old-secret"},
+			Key:             "password",
+		},
+		Token: strPtr("old-token"),
+	}}
+	return desired, latest
+}
+
+func TestDelta_ToJSONPatch(t *testing.T) {
+	require := require.New(t)
+	desired, latest := newBuckets()
+	d := compare.DeepDelta(desired, latest)
+
+	patch, err := d.ToJSONPatch(
+		compare.WithDesired(desired),
+		compare.WithRedactor(compare.RedactPaths("Spec.Token")),
+	)
+	require.Nil(err)
+	require.JSONEq(`[
+		{"op": "replace", "path": "/spec/bucketName", "value": "new-name"},
+		{"op": "replace", "path": "/spec/rules/0/id", "value": "a"},
+		{"op": "add", "path": "/spec/tags/env", "value": "prod"},
+		{"op": "remove", "path": "/spec/tags/team"},
+		{"op": "replace", "path": "/spec/password/name", "value": "<redacted>"},
+		{"op": "replace", "path": "/spec/token", "value": "<redacted>"},
+		{"op": "remove", "path": "/spec/rules/1"},
+		{"op": "remove", "path": "/spec/rules/0/ports/2"},
+		{"op": "remove", "path": "/spec/rules/0/ports/1"}
+	]`, string(patch))
+
+	// Without the desired object, the Go field names are used and only the
+	// SecretKeyReference values are redacted
+	d = compare.NewDelta()
+	d.Add("Spec.BucketName", desired.Spec.BucketName, latest.Spec.BucketName)
+	d.Add("Spec.Password", desired.Spec.Password, latest.Spec.Password)
+	patch, err = d.ToJSONPatch()
+	require.Nil(err)
+	require.JSONEq(`[
+		{"op": "replace", "path": "/Spec/BucketName", "value": "new-name"},
+		{"op": "replace", "path": "/Spec/Password", "value": "<redacted>"}
+	]`, string(patch))
+}
+
+func TestDelta_ToMergePatch(t *testing.T) {
+	require := require.New(t)
+	desired, latest := newBuckets()
+	d := compare.DeepDelta(desired, latest, compare.IgnorePaths("Spec.Token"))
+
+	patch, err := d.ToMergePatch(compare.WithDesired(desired))
+	require.Nil(err)
+	require.JSONEq(`{"spec": {
+		"bucketName": "new-name",
+		"rules": [{"id": "a", "ports": [80]}],
+		"tags": {"env": "prod", "team": null},
+		"password": {"name": "<redacted>"}
+	}}`, string(patch))
+
+	// Differences within arrays can't be rendered without the desired object
+	_, err = d.ToMergePatch()
+	require.NotNil(err)
+
+	// Differences between the resources themselves replace the document
+	d = compare.NewDelta()
+	d.Add("", map[string]string{"a": "b"}, nil)
+	patch, err = d.ToMergePatch()
+	require.Nil(err)
+	require.JSONEq(`{"a": "b"}`, string(patch))
+}
+
+func TestDelta_UnifiedDiff(t *testing.T) {
+	require := require.New(t)
+	desired, latest := newBuckets()
+	d := compare.DeepDelta(desired, latest, compare.IgnorePaths("Spec.Rules", "Spec.Tags"))
+	d.Add("Spec.Rules", desired.Spec.Rules, nil)
+
+	require.Equal(`--- latest
++++ desired
+@@ Spec.BucketName @@
+-old-name
++new-name
+@@ Spec.Password.SecretReference.Name @@
+-<redacted>
++<redacted>
+@@ Spec.Token @@
+-old-token
++new-token
+@@ Spec.Rules @@
++- id: a
++  ports:
++  - 80
+`, d.UnifiedDiff(compare.WithDesired(desired)))
+	require.Equal("", compare.NewDelta().UnifiedDiff())
+}
@@ -15,14 +15,98 @@ package compare
 
 import (
 	"encoding/json"
+	"fmt"
+	"strconv"
 	"strings"
 )
 
+// SegmentKind is the kind of a Path Segment
+type SegmentKind int
+
+const (
+	// SegmentField is a Segment selecting a struct field or object member by
+	// name, e.g. `Spec`
+	SegmentField SegmentKind = iota
+	// SegmentIndex is a Segment selecting a slice element by index, e.g.
+	// `[2]`
+	SegmentIndex
+	// SegmentKey is a Segment selecting a map value by key, e.g. `["env"]`
+	SegmentKey
+	// SegmentWildcard is a Segment matching any other Segment, e.g. `[*]`. It
+	// is only meaningful in the subjects of Path.Contains.
+	SegmentWildcard
+)
+
+// Segment is a single step of a Path
+type Segment struct {
+	// Kind is the kind of the Segment
+	Kind SegmentKind
+	// Name is the name of the field of SegmentField segments, or the key of
+	// SegmentKey segments
+	Name string
+	// Index is the index of SegmentIndex segments
+	Index int
+}
+
+// String returns the representation of the Segment in a Path string, e.g.
+// `.Spec`, `[2]` or `["env"]`
+func (s Segment) String() string {
+	switch s.Kind {
+	case SegmentIndex:
+		return "[" + strconv.Itoa(s.Index) + "]"
+	case SegmentKey:
+		return "[" + strconv.Quote(s.Name) + "]"
+	case SegmentWildcard:
+		return "[*]"
+	default:
+		return "." + s.Name
+	}
+}
+
+// token returns the reference token of the Segment in a JSON Pointer, before
+// escaping
+func (s Segment) token() string {
+	switch s.Kind {
+	case SegmentIndex:
+		return strconv.Itoa(s.Index)
+	case SegmentWildcard:
+		return "*"
+	default:
+		return s.Name
+	}
+}
+
+// matches returns true if the supplied segment, which is part of a
+// Path.Contains subject, matches the Segment. Besides wildcards, a field
+// segment matches the index segment whose index it spells and the key
+// segment whose key it spells, so that the dotted subject "Tags.0.Key"
+// matches the `Tags[0].Key` path.
+func (s Segment) matches(subject Segment) bool {
+	if subject.Kind == SegmentWildcard || s.Kind == SegmentWildcard {
+		return true
+	}
+	if subject.Kind == SegmentField {
+		return subject.Name == s.token()
+	}
+	return subject == s
+}
+
+// matchesFold is like matches, except that field names are compared
+// case-insensitively, so that the JSON name of a field, e.g. `policyDocument`,
+// matches its Go name, e.g. `PolicyDocument`
+func (s Segment) matchesFold(subject Segment) bool {
+	if subject.Kind == SegmentField && s.Kind == SegmentField {
+		return strings.EqualFold(subject.Name, s.Name)
+	}
+	return s.matches(subject)
+}
+
 // Path provides a JSONPath-like struct and field-member "route" to a
-// particular field within a compared struct. Path implements json.Marshaler
-// interface.
+// particular field within a compared struct, made of field, index and key
+// Segments, e.g. `Spec.Rules[2].Filter` or `Spec.Tags["env"]`. Path
+// implements json.Marshaler interface.
 type Path struct {
-	parts []string
+	segments []Segment
 }
 
 // MarshalJSON returns the JSON encoding of a Path object.
@@ -30,53 +114,280 @@ func (p Path) MarshalJSON() ([]byte, error) {
 	// Since json.Marshall doesn't encode unexported struct fields we have to
 	// copy the Path instance into a new struct object with exported fields.
 	// See https://github.com/aws-controllers-k8s/community/issues/772
+	parts := make([]string, 0, len(p.segments))
+	for _, s := range p.segments {
+		parts = append(parts, s.token())
+	}
 	return json.Marshal(
 		struct {
 			Parts []string
 		}{
-			p.parts,
+			parts,
 		},
 	)
 }
 
-// Push adds a new part to the Path.
-func (p Path) Push(part string) {
-	p.parts = append(p.parts, part)
+// Segments returns a copy of the Segments of the Path
+func (p Path) Segments() []Segment {
+	return append([]Segment{}, p.segments...)
+}
+
+// Len returns the number of Segments of the Path
+func (p Path) Len() int {
+	return len(p.segments)
+}
+
+// Push adds a new field part to the Path.
+func (p *Path) Push(part string) {
+	p.PushField(part)
+}
+
+// PushField adds a new field Segment to the Path
+func (p *Path) PushField(name string) {
+	p.segments = append(p.segments, Segment{Kind: SegmentField, Name: name})
+}
+
+// PushIndex adds a new slice index Segment to the Path
+func (p *Path) PushIndex(index int) {
+	p.segments = append(p.segments, Segment{Kind: SegmentIndex, Index: index})
+}
+
+// PushKey adds a new map key Segment to the Path
+func (p *Path) PushKey(key string) {
+	p.segments = append(p.segments, Segment{Kind: SegmentKey, Name: key})
 }
 
 // Pop removes the last part from the Path
-func (p Path) Pop() {
-	if len(p.parts) > 0 {
-		p.parts = p.parts[:len(p.parts)-1]
+func (p *Path) Pop() {
+	if len(p.segments) > 0 {
+		p.segments = p.segments[:len(p.segments)-1]
 	}
 }
 
-//	Contains returns true if the supplied string, delimited on ".", matches
-//	p.parts up to the length of the supplied string.
-//		e.g. if the Path p represents "A.B":
+// with returns a copy of the Path with the supplied Segment appended, leaving
+// the Path untouched
+func (p Path) with(s Segment) Path {
+	segments := make([]Segment, 0, len(p.segments)+1)
+	return Path{append(append(segments, p.segments...), s)}
+}
+
+// String returns the Path in the notation accepted by NewPath, e.g.
+// `Spec.Rules[2].Filter` or `Spec.Tags["env"]`
+func (p Path) String() string {
+	return strings.TrimPrefix(p.render(), ".")
+}
+
+// JSONPath returns the Path as a JSONPath expression, e.g.
+// `$.Spec.Rules[2].Filter` or `$.Spec.Tags["env"]`
+func (p Path) JSONPath() string {
+	return "$" + p.render()
+}
+
+// render returns the concatenated representations of the Segments
+func (p Path) render() string {
+	var sb strings.Builder
+	for _, s := range p.segments {
+		sb.WriteString(s.String())
+	}
+	return sb.String()
+}
+
+// JSONPointer returns the Path as a JSON Pointer as defined in RFC 6901, e.g.
+// `/Spec/Rules/2/Filter` or `/Spec/Tags/env`
+func (p Path) JSONPointer() string {
+	var sb strings.Builder
+	for _, s := range p.segments {
+		sb.WriteString("/")
+		sb.WriteString(jsonPointerEscaper.Replace(s.token()))
+	}
+	return sb.String()
+}
+
+var (
+	jsonPointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	jsonPointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+//	Contains returns true if the supplied subject, parsed with NewPath,
+//	matches the Path up to the length of the subject. Wildcards in the
+//	subject match any segment, and dotted parts spelling an index or a key
+//	match that index or key.
+//		e.g. if the Path p represents `A.B[2]["c"]`:
 //			subject "A" -> true
 //			subject "A.B" -> true
-//			subject "A.B.C" -> false
+//			subject "A.B[2]" -> true
+//			subject "A.B.2" -> true
+//			subject "A.B[*].c" -> true
+//			subject "A.B[3]" -> false
+//			subject "A.B[2].c.D" -> false
 //			subject "B" -> false
-//			subject "A.C" -> false
 func (p Path) Contains(subject string) bool {
-	subjectSplit := strings.Split(subject, ".")
+	return p.HasPrefix(NewPath(subject))
+}
 
-	if len(subjectSplit) > len(p.parts) {
+// HasPrefix returns true if the supplied Path matches the Path up to the
+// length of the supplied Path, with the same semantics as Contains
+func (p Path) HasPrefix(prefix Path) bool {
+	if len(prefix.segments) > len(p.segments) {
 		return false
 	}
-
-	for i, s := range subjectSplit {
-		if p.parts[i] != s {
+	for i, s := range prefix.segments {
+		if !p.segments[i].matches(s) {
 			return false
 		}
 	}
+	return true
+}
 
+// HasPrefixFold is like HasPrefix, except that field names are compared
+// case-insensitively, so that prefixes made of the JSON names of the fields,
+// e.g. `spec.policyDocument`, are accepted. Map keys are still compared
+// case-sensitively.
+func (p Path) HasPrefixFold(prefix Path) bool {
+	if len(prefix.segments) > len(p.segments) {
+		return false
+	}
+	for i, s := range prefix.segments {
+		if !p.segments[i].matchesFold(s) {
+			return false
+		}
+	}
 	return true
 }
 
-// NewPath returns a new Path struct pointer from a dotted-notation string,
-// e.g. "Author.Name"
+// ParsePath parses a Path from dotted field names followed by optional
+// bracketed indices, wildcards and quoted keys, e.g. `Spec.Rules[2].Filter`,
+// `Spec.Tags["env"]`, `Spec.Tags['env']` or `Spec.Rules[*]`. A leading `$`
+// is ignored, so JSONPath expressions returned by Path.JSONPath are
+// accepted.
+func ParsePath(subject string) (Path, error) {
+	p := Path{segments: []Segment{}}
+	s := strings.TrimPrefix(subject, "$")
+	if subject != s {
+		s = strings.TrimPrefix(s, ".")
+	}
+	expectField := true
+	for len(s) > 0 {
+		switch {
+		case s[0] == '[':
+			end, seg, err := parseBracket(s)
+			if err != nil {
+				return Path{}, fmt.Errorf("invalid path %q: %v", subject, err)
+			}
+			p.segments = append(p.segments, seg)
+			s = s[end:]
+			expectField = false
+		case s[0] == '.' && !expectField:
+			s = s[1:]
+			expectField = true
+		case expectField:
+			end := strings.IndexAny(s, ".[")
+			if end == -1 {
+				end = len(s)
+			}
+			p.segments = append(p.segments, fieldSegment(s[:end]))
+			s = s[end:]
+			expectField = false
+		default:
+			return Path{}, fmt.Errorf("invalid path %q: unexpected %q", subject, s[0])
+		}
+	}
+	if expectField && len(p.segments) > 0 {
+		// A trailing dot selects an empty field name
+		p.segments = append(p.segments, fieldSegment(""))
+	}
+	return p, nil
+}
+
+// fieldSegment returns the Segment of the supplied dotted part, which is a
+// wildcard if the part is "*"
+func fieldSegment(name string) Segment {
+	if name == "*" {
+		return Segment{Kind: SegmentWildcard}
+	}
+	return Segment{Kind: SegmentField, Name: name}
+}
+
+// parseBracket parses the bracketed segment at the start of the supplied
+// string, and returns the position following it
+func parseBracket(s string) (int, Segment, error) {
+	if len(s) > 1 && (s[1] == '"' || s[1] == '\'') {
+		quote := s[1]
+		for i := 2; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				i++
+			case quote:
+				if i+1 >= len(s) || s[i+1] != ']' {
+					return 0, Segment{}, fmt.Errorf("unterminated key")
+				}
+				raw := s[2:i]
+				if quote == '\'' {
+					raw = strings.ReplaceAll(strings.ReplaceAll(raw, `\'`, "'"), `"`, `\"`)
+				}
+				key, err := strconv.Unquote(`"` + raw + `"`)
+				if err != nil {
+					return 0, Segment{}, fmt.Errorf("invalid key %s: %v", s[1:i+1], err)
+				}
+				return i + 2, Segment{Kind: SegmentKey, Name: key}, nil
+			}
+		}
+		return 0, Segment{}, fmt.Errorf("unterminated key")
+	}
+	end := strings.IndexByte(s, ']')
+	if end == -1 {
+		return 0, Segment{}, fmt.Errorf("unterminated index")
+	}
+	if s[1:end] == "*" {
+		return end + 1, Segment{Kind: SegmentWildcard}, nil
+	}
+	index, err := strconv.Atoi(s[1:end])
+	if err != nil || index < 0 {
+		return 0, Segment{}, fmt.Errorf("invalid index %q", s[1:end])
+	}
+	return end + 1, Segment{Kind: SegmentIndex, Index: index}, nil
+}
+
+// ParseJSONPointer parses a Path from a JSON Pointer as defined in RFC 6901,
+// e.g. `/Spec/Rules/2/Filter`. As JSON Pointers do not distinguish object
+// members from array elements, reference tokens that are non-negative
+// integers become index segments, and every other token a field segment.
+func ParseJSONPointer(pointer string) (Path, error) {
+	p := Path{segments: []Segment{}}
+	if pointer == "" {
+		return p, nil
+	}
+	if pointer[0] != '/' {
+		return Path{}, fmt.Errorf("invalid JSON pointer %q: must start with /", pointer)
+	}
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = jsonPointerUnescaper.Replace(token)
+		if index, err := strconv.Atoi(token); err == nil && index >= 0 &&
+			strconv.Itoa(index) == token {
+			p.PushIndex(index)
+			continue
+		}
+		p.PushField(token)
+	}
+	return p, nil
+}
+
+// NewPath returns a new Path struct from a dotted-notation string, e.g.
+// "Author.Name", which may contain the bracketed indices and keys accepted by
+// ParsePath, e.g. `Author.Books[2].Title`. Strings that ParsePath rejects are
+// split on "." into field segments.
 func NewPath(dotted string) Path {
-	return Path{strings.Split(dotted, ".")}
+	if p, err := ParsePath(dotted); err == nil {
+		if len(p.segments) == 0 {
+			// The empty string is the path of a single, empty field name, as
+			// used by `Delta.Add("", ...)`
+			p.PushField("")
+		}
+		return p
+	}
+	p := Path{}
+	for _, part := range strings.Split(dotted, ".") {
+		p.PushField(part)
+	}
+	return p
 }
@@ -0,0 +1,173 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package compare_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws-controllers-k8s/runtime/pkg/compare"
+)
+
+func TestPath_Builders(t *testing.T) {
+	require := require.New(t)
+	p := compare.Path{}
+	p.Push("Spec")
+	p.PushField("Rules")
+	p.PushIndex(2)
+	p.PushField("Filter")
+	require.Equal("Spec.Rules[2].Filter", p.String())
+	require.Equal(4, p.Len())
+
+	p.Pop()
+	p.Pop()
+	p.PushField("Tags")
+	p.PushKey("env")
+	require.Equal(`Spec.Rules.Tags["env"]`, p.String())
+	require.Equal([]compare.Segment{
+		{Kind: compare.SegmentField, Name: "Spec"},
+		{Kind: compare.SegmentField, Name: "Rules"},
+		{Kind: compare.SegmentField, Name: "Tags"},
+		{Kind: compare.SegmentKey, Name: "env"},
+	}, p.Segments())
+
+	p = compare.Path{}
+	p.Pop()
+	require.Equal(0, p.Len())
+}
+
+func TestPath_Render(t *testing.T) {
+	require := require.New(t)
+	p := compare.NewPath(`Spec.Rules[2].Tags["a/b~c"]`)
+	require.Equal(`Spec.Rules[2].Tags["a/b~c"]`, p.String())
+	require.Equal(`$.Spec.Rules[2].Tags["a/b~c"]`, p.JSONPath())
+	require.Equal(`/Spec/Rules/2/Tags/a~1b~0c`, p.JSONPointer())
+
+	b, err := json.Marshal(p)
+	require.Nil(err)
+	require.Equal(`{"Parts":["Spec","Rules","2","Tags","a/b~c"]}`, string(b))
+}
+
+func TestParsePath(t *testing.T) {
+	testCases := []struct {
+		name    string
+		subject string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "dotted fields",
+			subject: "Spec.Name",
+			want:    "Spec.Name",
+		},
+		{
+			name:    "index",
+			subject: "Spec.Rules[2].Filter",
+			want:    "Spec.Rules[2].Filter",
+		},
+		{
+			name:    "double quoted key",
+			subject: `Spec.Tags["env"]`,
+			want:    `Spec.Tags["env"]`,
+		},
+		{
+			name:    "single quoted key with special characters",
+			subject: `Spec.Tags['it\'s "a".key[0]']`,
+			want:    `Spec.Tags["it's \"a\".key[0]"]`,
+		},
+		{
+			name:    "JSONPath",
+			subject: "$.Spec.Rules[0][1]",
+			want:    "Spec.Rules[0][1]",
+		},
+		{
+			name:    "wildcards",
+			subject: "Spec.Rules[*].*",
+			want:    "Spec.Rules[*][*]",
+		},
+		{
+			name:    "unterminated index",
+			subject: "Spec.Rules[2",
+			wantErr: true,
+		},
+		{
+			name:    "invalid index",
+			subject: "Spec.Rules[-1]",
+			wantErr: true,
+		},
+		{
+			name:    "unterminated key",
+			subject: `Spec.Tags["env]`,
+			wantErr: true,
+		},
+		{
+			name:    "missing dot",
+			subject: "Spec.Rules[2]Filter",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := compare.ParsePath(tc.subject)
+			if tc.wantErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tc.want, p.String())
+		})
+	}
+}
+
+func TestParseJSONPointer(t *testing.T) {
+	require := require.New(t)
+	p, err := compare.ParseJSONPointer("/Spec/Rules/2/a~1b~0c/02")
+	require.Nil(err)
+	require.Equal(`Spec.Rules[2].a/b~c.02`, p.String())
+
+	p, err = compare.ParseJSONPointer("")
+	require.Nil(err)
+	require.Equal(0, p.Len())
+
+	_, err = compare.ParseJSONPointer("Spec")
+	require.NotNil(err)
+}
+
+func TestPath_Contains(t *testing.T) {
+	require := require.New(t)
+	p := compare.NewPath(`A.B[2]["c"]`)
+	require.True(p.Contains("A"))
+	require.True(p.Contains("A.B"))
+	require.True(p.Contains("A.B[2]"))
+	require.True(p.Contains("A.B.2"))
+	require.True(p.Contains(`A.B[2]["c"]`))
+	require.True(p.Contains("A.B[2].c"))
+	require.True(p.Contains("A.B[*].c"))
+	require.True(p.Contains("A.*"))
+	require.False(p.Contains("A.B[3]"))
+	require.False(p.Contains(`A.B[2]["d"]`))
+	require.False(p.Contains("A.B[2].c.D"))
+	require.False(p.Contains("B"))
+
+	// Indices and keys only match their own kind of segments
+	require.False(compare.NewPath("A.B").Contains("A[0]"))
+	require.False(compare.NewPath("A[0]").Contains(`A["0"]`))
+
+	// The empty path, used by Delta.Add("", ...), only contains itself
+	require.True(compare.NewPath("").Contains(""))
+	require.False(compare.NewPath("").Contains("A"))
+}
@@ -0,0 +1,287 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package compare
+
+import (
+	"reflect"
+	"strings"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+)
+
+// RedactedValue replaces the values of the redacted fields in the patches
+// and diffs rendered from a Delta
+const RedactedValue = "<redacted>"
+
+// Redactor returns true if the value of the field at the supplied Path must
+// be redacted from the patches and diffs rendered from a Delta
+type Redactor func(path Path) bool
+
+// RedactPaths returns a Redactor redacting the fields at the supplied paths,
+// and everything they contain. The paths are parsed with NewPath and matched
+// like in Path.Contains.
+func RedactPaths(paths ...string) Redactor {
+	parsed := make([]Path, 0, len(paths))
+	for _, p := range paths {
+		parsed = append(parsed, NewPath(p))
+	}
+	return func(path Path) bool {
+		for _, p := range parsed {
+			if path.HasPrefix(p) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// RenderOption configures the rendering of a Delta as a patch or a diff
+type RenderOption func(*renderOptions)
+
+// renderOptions contains the options of the rendering of a Delta
+type renderOptions struct {
+	// desired is the object the Delta was computed from
+	desired interface{}
+	// redactors contains the Redactors of the fields to redact
+	redactors []Redactor
+}
+
+// WithDesired returns a RenderOption rendering a Delta relative to the
+// supplied desired object, usually the CR the Delta was computed from. Its
+// JSON tags are used to translate the Go field names of the Difference paths
+// into JSON object member names, e.g. "Spec.BucketName" into
+// "/spec/bucketName", and its fields holding a SecretKeyReference are
+// redacted.
+func WithDesired(desired interface{}) RenderOption {
+	return func(o *renderOptions) {
+		o.desired = desired
+	}
+}
+
+// WithRedactor returns a RenderOption redacting the values of the fields for
+// which the supplied Redactor returns true, in addition to the fields
+// holding a SecretKeyReference, which are always redacted
+func WithRedactor(r Redactor) RenderOption {
+	return func(o *renderOptions) {
+		o.redactors = append(o.redactors, r)
+	}
+}
+
+// newRenderOptions returns the renderOptions set by the supplied
+// RenderOptions
+func newRenderOptions(opts []RenderOption) *renderOptions {
+	o := &renderOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// resolvedPath is a Difference path resolved against the desired object
+type resolvedPath struct {
+	// tokens contains the JSON object member names, array indices and map
+	// keys of the path
+	tokens []string
+	// firstIndex is the position of the first array index token in tokens,
+	// or -1 if there is none
+	firstIndex int
+	// firstArray is the value of the desired object at the array holding
+	// the first array index token, if any
+	firstArray reflect.Value
+	// redacted is true if the value at the path must be redacted
+	redacted bool
+}
+
+// pointer returns the JSON Pointer of the resolved path
+func (r *resolvedPath) pointer() string {
+	var sb strings.Builder
+	for _, token := range r.tokens {
+		sb.WriteString("/")
+		sb.WriteString(jsonPointerEscaper.Replace(token))
+	}
+	return sb.String()
+}
+
+var secretKeyReferenceType = reflect.TypeOf(ackv1alpha1.SecretKeyReference{})
+
+// resolve walks the desired object along the supplied Difference to
+// translate its path into JSON tokens, and to find out whether its value
+// must be redacted
+func (o *renderOptions) resolve(diff *Difference) *resolvedPath {
+	res := &resolvedPath{firstIndex: -1}
+	for _, r := range o.redactors {
+		if r(diff.Path) {
+			res.redacted = true
+		}
+	}
+	for _, value := range []interface{}{diff.A, diff.B} {
+		if isSecretKeyReference(reflect.TypeOf(value)) {
+			res.redacted = true
+		}
+	}
+	if isRootPath(diff.Path) {
+		return res
+	}
+
+	v := reflect.ValueOf(o.desired)
+	var t reflect.Type
+	if o.desired != nil {
+		t = v.Type()
+	}
+	for _, s := range diff.Path.segments {
+		t, v = indirect(t, v)
+		if isSecretKeyReference(t) {
+			res.redacted = true
+		}
+		token := s.token()
+		isList := t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array)
+		if s.Kind == SegmentIndex && res.firstIndex == -1 {
+			res.firstIndex = len(res.tokens)
+			if isList {
+				res.firstArray = v
+			}
+		}
+		switch {
+		case t == nil:
+		case s.Kind == SegmentIndex && isList:
+			if v.IsValid() && s.Index < v.Len() {
+				v = v.Index(s.Index)
+			} else {
+				v = reflect.Value{}
+			}
+			t = t.Elem()
+		case s.Kind != SegmentIndex && t.Kind() == reflect.Map && t.Key().Kind() == reflect.String:
+			if v.IsValid() && !v.IsNil() {
+				v = v.MapIndex(reflect.ValueOf(s.Name).Convert(t.Key()))
+			} else {
+				v = reflect.Value{}
+			}
+			t = t.Elem()
+		case s.Kind == SegmentField && t.Kind() == reflect.Struct:
+			sf, found := t.FieldByName(s.Name)
+			if !found {
+				t = nil
+				break
+			}
+			token = jsonName(sf)
+			v = fieldByIndex(v, sf.Index)
+			t = sf.Type
+			if isInlined(sf) {
+				// The fields of embedded structs without a JSON name are
+				// members of the enclosing JSON object
+				continue
+			}
+		default:
+			t = nil
+		}
+		if t == nil {
+			v = reflect.Value{}
+		}
+		res.tokens = append(res.tokens, token)
+	}
+	if t, _ := indirect(t, v); isSecretKeyReference(t) {
+		res.redacted = true
+	}
+	return res
+}
+
+// isRootPath returns true if the supplied Path is the one of a Difference
+// between the compared values themselves, as added with
+// `Delta.Add("", ...)`
+func isRootPath(p Path) bool {
+	return p.Len() == 0 || (p.Len() == 1 && p.segments[0] == Segment{Kind: SegmentField})
+}
+
+// isSecretKeyReference returns true if the supplied type is a
+// SecretKeyReference or a pointer to one
+func isSecretKeyReference(t reflect.Type) bool {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t == secretKeyReferenceType
+}
+
+// indirect dereferences the supplied pointer and interface type and value.
+// For interfaces, the type of the held value is returned, or nil if it is
+// unknown.
+func indirect(t reflect.Type, v reflect.Value) (reflect.Type, reflect.Value) {
+	for t != nil {
+		switch t.Kind() {
+		case reflect.Ptr:
+			t = t.Elem()
+		case reflect.Interface:
+			if !v.IsValid() || v.IsNil() {
+				return nil, reflect.Value{}
+			}
+			t = v.Elem().Type()
+		default:
+			return t, v
+		}
+		if v.IsValid() {
+			if v.IsNil() {
+				v = reflect.Value{}
+			} else {
+				v = v.Elem()
+			}
+		}
+	}
+	return t, v
+}
+
+// fieldByIndex returns the field of the supplied struct value at the
+// supplied index sequence, or an invalid value if it goes through a nil
+// embedded pointer
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if !v.IsValid() {
+			return v
+		}
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// jsonName returns the name of the supplied struct field in its JSON
+// encoding
+func jsonName(sf reflect.StructField) string {
+	name := strings.Split(sf.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return sf.Name
+	}
+	return name
+}
+
+// isInlined returns true if the supplied struct field is an embedded struct
+// whose fields are encoded as members of the enclosing JSON object
+func isInlined(sf reflect.StructField) bool {
+	return sf.Anonymous && strings.Split(sf.Tag.Get("json"), ",")[0] == ""
+}
+
+// valueOf returns the supplied value, or RedactedValue if it is redacted.
+// Nil values are returned as nil.
+func (r *resolvedPath) valueOf(value interface{}) interface{} {
+	if IsNil(value) {
+		return nil
+	}
+	if r.redacted {
+		return RedactedValue
+	}
+	return value
+}
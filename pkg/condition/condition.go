@@ -28,7 +28,8 @@ var (
 	NotManagedMessage = "Resource already exists"
 	NotManagedReason  = "This resource already exists but is not managed by ACK. " +
 		"To bring the resource under ACK management, you should explicitly adopt " +
-		"the resource by creating a services.k8s.aws/AdoptedResource"
+		"the resource by creating a services.k8s.aws/AdoptedResource, or set " +
+		"the services.k8s.aws/adoption-policy annotation to adopt-or-create"
 )
 
 // Synced returns the Condition in the resource's Conditions collection that is
@@ -45,6 +46,20 @@ func Terminal(subject acktypes.ConditionManager) *ackv1alpha1.Condition {
 	return FirstOfType(subject, ackv1alpha1.ConditionTypeTerminal)
 }
 
+// Recoverable returns the Condition in the resource's Conditions collection
+// that is of type ConditionTypeRecoverable. If no such condition is found,
+// returns nil.
+func Recoverable(subject acktypes.ConditionManager) *ackv1alpha1.Condition {
+	return FirstOfType(subject, ackv1alpha1.ConditionTypeRecoverable)
+}
+
+// Advisory returns the Condition in the resource's Conditions collection that
+// is of type ConditionTypeAdvisory. If no such condition is found, returns
+// nil.
+func Advisory(subject acktypes.ConditionManager) *ackv1alpha1.Condition {
+	return FirstOfType(subject, ackv1alpha1.ConditionTypeAdvisory)
+}
+
 // LateInitialized returns the Condition in the resource's Conditions collection that
 // is of type ConditionTypeLateInitialized. If no such condition is found, returns
 // nil.
@@ -104,9 +119,7 @@ func SetSynced(
 		}
 		allConds = append(allConds, c)
 	}
-	now := metav1.Now()
-	c.LastTransitionTime = &now
-	c.Status = status
+	transition(c, status)
 	c.Message = message
 	c.Reason = reason
 	subject.ReplaceConditions(allConds)
@@ -128,9 +141,52 @@ func SetTerminal(
 		}
 		allConds = append(allConds, c)
 	}
-	now := metav1.Now()
-	c.LastTransitionTime = &now
-	c.Status = status
+	transition(c, status)
+	c.Message = message
+	c.Reason = reason
+	subject.ReplaceConditions(allConds)
+}
+
+// SetRecoverable sets the resource's Condition of type
+// ConditionTypeRecoverable to the supplied status, optional message and
+// reason.
+func SetRecoverable(
+	subject acktypes.ConditionManager,
+	status corev1.ConditionStatus,
+	message *string,
+	reason *string,
+) {
+	allConds := subject.Conditions()
+	var c *ackv1alpha1.Condition
+	if c = Recoverable(subject); c == nil {
+		c = &ackv1alpha1.Condition{
+			Type: ackv1alpha1.ConditionTypeRecoverable,
+		}
+		allConds = append(allConds, c)
+	}
+	transition(c, status)
+	c.Message = message
+	c.Reason = reason
+	subject.ReplaceConditions(allConds)
+}
+
+// SetAdvisory sets the resource's Condition of type ConditionTypeAdvisory to
+// the supplied status, optional message and reason.
+func SetAdvisory(
+	subject acktypes.ConditionManager,
+	status corev1.ConditionStatus,
+	message *string,
+	reason *string,
+) {
+	allConds := subject.Conditions()
+	var c *ackv1alpha1.Condition
+	if c = Advisory(subject); c == nil {
+		c = &ackv1alpha1.Condition{
+			Type: ackv1alpha1.ConditionTypeAdvisory,
+		}
+		allConds = append(allConds, c)
+	}
+	transition(c, status)
 	c.Message = message
 	c.Reason = reason
 	subject.ReplaceConditions(allConds)
@@ -152,9 +208,7 @@ func SetLateInitialized(
 		}
 		allConds = append(allConds, c)
 	}
-	now := metav1.Now()
-	c.LastTransitionTime = &now
-	c.Status = status
+	transition(c, status)
 	c.Message = message
 	c.Reason = reason
 	subject.ReplaceConditions(allConds)
@@ -176,14 +230,25 @@ func SetReferencesResolved(
 		}
 		allConds = append(allConds, c)
 	}
-	now := metav1.Now()
-	c.LastTransitionTime = &now
-	c.Status = status
+	transition(c, status)
 	c.Message = message
 	c.Reason = reason
 	subject.ReplaceConditions(allConds)
 }
 
+// transition sets the status of the supplied Condition. The Condition's
+// LastTransitionTime is only updated when the status actually changes.
+func transition(
+	c *ackv1alpha1.Condition,
+	status corev1.ConditionStatus,
+) {
+	if c.Status != status || c.LastTransitionTime == nil {
+		now := metav1.Now()
+		c.LastTransitionTime = &now
+	}
+	c.Status = status
+}
+
 // RemoveReferencesResolved removes the condition of type ConditionTypeReferencesResolved
 // from the resource's conditions
 func RemoveReferencesResolved(
@@ -210,7 +275,7 @@ func WithReferencesResolvedCondition(
 	if err != nil {
 		errString := err.Error()
 		conditionStatus := corev1.ConditionUnknown
-		if strings.Contains(errString, ackerr.ResourceReferenceTerminal.Error()) {
+		if isTerminalReferenceError(errString) {
 			conditionStatus = corev1.ConditionFalse
 		}
 		SetReferencesResolved(resource, conditionStatus, &errString, nil)
@@ -220,6 +285,22 @@ func WithReferencesResolvedCondition(
 	return resource, err
 }
 
+// isTerminalReferenceError returns true if the supplied error string
+// describes a reference resolution failure that will not resolve itself
+// without the user modifying either the reference or the referenced resource
+func isTerminalReferenceError(errString string) bool {
+	for _, terminalErr := range []error{
+		ackerr.ResourceReferenceTerminal,
+		ackerr.ResourceReferenceNameAndARNNotSupported,
+		ackerr.ResourceReferenceNamespaceNotPermitted,
+	} {
+		if strings.Contains(errString, terminalErr.Error()) {
+			return true
+		}
+	}
+	return false
+}
+
 // LateInitializationInProgress return true if ConditionTypeLateInitialized has "False" status
 // False status means that resource has LateInitializationConfig but has not been completely
 // late initialized yet.
@@ -228,6 +309,54 @@ func LateInitializationInProgress(subject acktypes.ConditionManager) bool {
 	return c != nil && c.Status == corev1.ConditionFalse
 }
 
+// SetObservedGeneration sets the ObservedGeneration of all the resource's
+// Conditions to the supplied generation.
+func SetObservedGeneration(
+	subject acktypes.ConditionManager,
+	generation int64,
+) {
+	for _, c := range subject.Conditions() {
+		c.ObservedGeneration = generation
+	}
+}
+
+// SetLastSyncedTime sets the LastSyncedTime of the resource's Condition of
+// type ConditionTypeResourceSynced to the supplied time. Does nothing if the
+// resource has no such condition.
+func SetLastSyncedTime(
+	subject acktypes.ConditionManager,
+	syncedTime metav1.Time,
+) {
+	if c := Synced(subject); c != nil {
+		c.LastSyncedTime = &syncedTime
+	}
+}
+
+// PreserveTimestamps carries the timestamps of the supplied previous
+// Conditions over to the resource's Conditions of the same type whose status
+// did not change. This allows the Conditions to be recomputed from scratch on
+// every reconciliation loop while LastTransitionTime only changes on an actual
+// status transition.
+func PreserveTimestamps(
+	subject acktypes.ConditionManager,
+	previous []*ackv1alpha1.Condition,
+) {
+	for _, c := range subject.Conditions() {
+		for _, prev := range previous {
+			if prev.Type != c.Type {
+				continue
+			}
+			if prev.Status == c.Status && prev.LastTransitionTime != nil {
+				c.LastTransitionTime = prev.LastTransitionTime.DeepCopy()
+			}
+			if c.LastSyncedTime == nil && prev.LastSyncedTime != nil {
+				c.LastSyncedTime = prev.LastSyncedTime.DeepCopy()
+			}
+			break
+		}
+	}
+}
+
 // Clear resets the resource's collection of Conditions to an empty list.
 func Clear(
 	subject acktypes.ConditionManager,
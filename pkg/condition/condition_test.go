@@ -16,9 +16,11 @@ package condition_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
 	ackcond "github.com/aws-controllers-k8s/runtime/pkg/condition"
@@ -290,4 +292,110 @@ func TestConditionSetters(t *testing.T) {
 		}),
 	)
 	ackcond.WithReferencesResolvedCondition(r, terminalError)
+
+	// Ensure that SetAdvisory adds an advisory condition with its message...
+	advisoryMsg := "advisory message"
+	r = &ackmocks.AWSResource{}
+	r.On("Conditions").Return([]*ackv1alpha1.Condition{})
+	r.On(
+		"ReplaceConditions",
+		mock.MatchedBy(func(subject []*ackv1alpha1.Condition) bool {
+			if len(subject) != 1 {
+				return false
+			}
+			return (subject[0].Type == ackv1alpha1.ConditionTypeAdvisory &&
+				subject[0].Status == corev1.ConditionTrue &&
+				*subject[0].Message == advisoryMsg)
+		}),
+	)
+	ackcond.SetAdvisory(r, corev1.ConditionTrue, &advisoryMsg, nil)
+}
+
+func TestConditionTimestamps(t *testing.T) {
+	assert := assert.New(t)
+
+	earlier := metav1.NewTime(time.Now().Add(-time.Hour))
+
+	// Ensure that setting the same status does not update LastTransitionTime
+	syncedCond := &ackv1alpha1.Condition{
+		Type:               ackv1alpha1.ConditionTypeResourceSynced,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: &earlier,
+	}
+	r := &ackmocks.AWSResource{}
+	r.On("Conditions").Return([]*ackv1alpha1.Condition{syncedCond})
+	r.On("ReplaceConditions", mock.Anything)
+
+	ackcond.SetSynced(r, corev1.ConditionFalse, nil, nil)
+	assert.Equal(earlier, *syncedCond.LastTransitionTime)
+
+	// Ensure that a status transition updates LastTransitionTime
+	ackcond.SetSynced(r, corev1.ConditionTrue, nil, nil)
+	assert.True(syncedCond.LastTransitionTime.After(earlier.Time))
+
+	// Ensure that timestamps of conditions which did not transition are
+	// carried over from the previous conditions
+	previous := []*ackv1alpha1.Condition{
+		{
+			Type:               ackv1alpha1.ConditionTypeResourceSynced,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: &earlier,
+			LastSyncedTime:     &earlier,
+		},
+		{
+			Type:               ackv1alpha1.ConditionTypeTerminal,
+			Status:             corev1.ConditionFalse,
+			LastTransitionTime: &earlier,
+		},
+	}
+	now := metav1.Now()
+	synced := &ackv1alpha1.Condition{
+		Type:               ackv1alpha1.ConditionTypeResourceSynced,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: &now,
+	}
+	terminal := &ackv1alpha1.Condition{
+		Type:               ackv1alpha1.ConditionTypeTerminal,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: &now,
+	}
+	r = &ackmocks.AWSResource{}
+	r.On("Conditions").Return([]*ackv1alpha1.Condition{synced, terminal})
+
+	ackcond.PreserveTimestamps(r, previous)
+	assert.Equal(earlier, *synced.LastTransitionTime)
+	assert.Equal(earlier, *synced.LastSyncedTime)
+	assert.Equal(now, *terminal.LastTransitionTime)
+}
+
+func TestConditionObservedGenerationAndLastSyncedTime(t *testing.T) {
+	assert := assert.New(t)
+
+	synced := &ackv1alpha1.Condition{
+		Type:   ackv1alpha1.ConditionTypeResourceSynced,
+		Status: corev1.ConditionTrue,
+	}
+	terminal := &ackv1alpha1.Condition{
+		Type:   ackv1alpha1.ConditionTypeTerminal,
+		Status: corev1.ConditionFalse,
+	}
+	r := &ackmocks.AWSResource{}
+	r.On("Conditions").Return([]*ackv1alpha1.Condition{synced, terminal})
+
+	// Ensure that the observed generation is set on every condition
+	ackcond.SetObservedGeneration(r, 3)
+	assert.Equal(int64(3), synced.ObservedGeneration)
+	assert.Equal(int64(3), terminal.ObservedGeneration)
+
+	// Ensure that the last sync time is only set on the synced condition
+	now := metav1.Now()
+	ackcond.SetLastSyncedTime(r, now)
+	assert.Equal(now, *synced.LastSyncedTime)
+	assert.Nil(terminal.LastSyncedTime)
+
+	// Ensure that nothing is set without a synced condition
+	r = &ackmocks.AWSResource{}
+	r.On("Conditions").Return([]*ackv1alpha1.Condition{terminal})
+	ackcond.SetLastSyncedTime(r, now)
+	assert.Nil(terminal.LastSyncedTime)
 }
@@ -0,0 +1,123 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package condition
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+)
+
+// ResolvedReference is the outcome of resolving an AWSResourceReference. When
+// ARN is non-nil the reference was made by ARN and no Kubernetes resource needs
+// to be read. Otherwise, Key identifies the referenced Kubernetes resource.
+type ResolvedReference struct {
+	ARN *ackv1alpha1.AWSResourceName
+	Key types.NamespacedName
+}
+
+// ResolveReference determines what the supplied AWSResourceReference points
+// to. `resource` is the kind of the referenced resource and `fromNamespace` is
+// the namespace of the referencing resource; both are only used to compose
+// errors and check cross-namespace permissions.
+//
+// References by ARN short-circuit: the ARN is returned as-is and the
+// Kubernetes API is not called. References by name default to the namespace of
+// the referencing resource. When a reference targets another namespace, the
+// target namespace must allow it via the
+// `services.k8s.aws/allow-references-from` annotation, otherwise a
+// ResourceReferenceNamespaceNotPermitted error is returned.
+func ResolveReference(
+	ctx context.Context,
+	apiReader client.Reader,
+	ref *ackv1alpha1.AWSResourceReference,
+	resource string,
+	fromNamespace string,
+) (*ResolvedReference, error) {
+	if ref == nil {
+		return nil, ackerr.ResourceReferenceOrIDRequiredFor(resource)
+	}
+	if ref.ARN != nil {
+		fields := []string{}
+		if ref.Name != nil {
+			fields = append(fields, "name")
+		}
+		if ref.Namespace != nil {
+			fields = append(fields, "namespace")
+		}
+		if len(fields) > 0 {
+			return nil, ackerr.ResourceReferenceNameAndARNNotSupportedFor(
+				resource, append(fields, "arn")...,
+			)
+		}
+		return &ResolvedReference{ARN: ref.ARN}, nil
+	}
+	if ref.Name == nil || *ref.Name == "" {
+		return nil, ackerr.ResourceReferenceOrIDRequiredFor(resource)
+	}
+	namespace := fromNamespace
+	if ref.Namespace != nil && *ref.Namespace != "" {
+		namespace = *ref.Namespace
+	}
+	if namespace != fromNamespace {
+		permitted, err := referencesPermitted(
+			ctx, apiReader, namespace, fromNamespace,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if !permitted {
+			return nil, ackerr.ResourceReferenceNamespaceNotPermittedFor(
+				resource, namespace, *ref.Name, fromNamespace,
+			)
+		}
+	}
+	return &ResolvedReference{
+		Key: types.NamespacedName{
+			Namespace: namespace,
+			Name:      *ref.Name,
+		},
+	}, nil
+}
+
+// referencesPermitted returns true if the supplied target namespace allows
+// its resources to be referenced from the supplied source namespace
+func referencesPermitted(
+	ctx context.Context,
+	apiReader client.Reader,
+	namespace string,
+	fromNamespace string,
+) (bool, error) {
+	ns := &corev1.Namespace{}
+	if err := apiReader.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		return false, err
+	}
+	allowed, ok := ns.GetAnnotations()[ackv1alpha1.AnnotationAllowReferencesFrom]
+	if !ok {
+		return false, nil
+	}
+	for _, allowedNamespace := range strings.Split(allowed, ",") {
+		allowedNamespace = strings.TrimSpace(allowedNamespace)
+		if allowedNamespace == "*" || allowedNamespace == fromNamespace {
+			return true, nil
+		}
+	}
+	return false, nil
+}
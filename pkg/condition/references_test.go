@@ -0,0 +1,138 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package condition_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ctrlrtclientmock "github.com/aws-controllers-k8s/runtime/mocks/controller-runtime/pkg/client"
+	ackcond "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+// mockNamespaceReader returns a Reader returning a namespace with the
+// supplied allow-references-from annotation value
+func mockNamespaceReader(name string, allowed *string) *ctrlrtclientmock.Reader {
+	apiReader := &ctrlrtclientmock.Reader{}
+	apiReader.On(
+		"Get", mock.Anything, types.NamespacedName{Name: name},
+		mock.AnythingOfType("*v1.Namespace"),
+	).Run(func(args mock.Arguments) {
+		ns := args.Get(2).(*corev1.Namespace)
+		ns.Name = name
+		if allowed != nil {
+			ns.Annotations = map[string]string{
+				ackv1alpha1.AnnotationAllowReferencesFrom: *allowed,
+			}
+		}
+	}).Return(nil)
+	return apiReader
+}
+
+func TestResolveReference_SameNamespace(t *testing.T) {
+	require := require.New(t)
+
+	apiReader := &ctrlrtclientmock.Reader{}
+	ref := &ackv1alpha1.AWSResourceReference{Name: strPtr("my-vpc")}
+
+	resolved, err := ackcond.ResolveReference(
+		context.TODO(), apiReader, ref, "VPC", "app",
+	)
+	require.Nil(err)
+	require.Nil(resolved.ARN)
+	require.Equal(types.NamespacedName{Namespace: "app", Name: "my-vpc"}, resolved.Key)
+	apiReader.AssertNotCalled(t, "Get")
+}
+
+func TestResolveReference_ARN(t *testing.T) {
+	require := require.New(t)
+
+	apiReader := &ctrlrtclientmock.Reader{}
+	arn := ackv1alpha1.AWSResourceName("arn:aws:ec2:us-west-2:012345678912:vpc/vpc-1")
+	ref := &ackv1alpha1.AWSResourceReference{ARN: &arn}
+
+	resolved, err := ackcond.ResolveReference(
+		context.TODO(), apiReader, ref, "VPC", "app",
+	)
+	require.Nil(err)
+	require.Equal(&arn, resolved.ARN)
+	apiReader.AssertNotCalled(t, "Get")
+
+	// Name and ARN are mutually exclusive
+	ref.Name = strPtr("my-vpc")
+	_, err = ackcond.ResolveReference(
+		context.TODO(), apiReader, ref, "VPC", "app",
+	)
+	require.True(errors.Is(err, ackerr.ResourceReferenceNameAndARNNotSupported))
+	require.Contains(err.Error(), "resource:VPC, fields:name,arn")
+
+	// Only the fields actually set alongside the ARN are reported
+	ref.Name = nil
+	ref.Namespace = strPtr("platform")
+	_, err = ackcond.ResolveReference(
+		context.TODO(), apiReader, ref, "VPC", "app",
+	)
+	require.True(errors.Is(err, ackerr.ResourceReferenceNameAndARNNotSupported))
+	require.Contains(err.Error(), "resource:VPC, fields:namespace,arn")
+}
+
+func TestResolveReference_CrossNamespace(t *testing.T) {
+	require := require.New(t)
+
+	ref := &ackv1alpha1.AWSResourceReference{
+		Name:      strPtr("shared-vpc"),
+		Namespace: strPtr("platform"),
+	}
+
+	// Target namespace without annotation denies the reference
+	apiReader := mockNamespaceReader("platform", nil)
+	_, err := ackcond.ResolveReference(
+		context.TODO(), apiReader, ref, "VPC", "app",
+	)
+	require.True(errors.Is(err, ackerr.ResourceReferenceNamespaceNotPermitted))
+
+	// Target namespace allowing other namespaces denies the reference
+	apiReader = mockNamespaceReader("platform", strPtr("team-a, team-b"))
+	_, err = ackcond.ResolveReference(
+		context.TODO(), apiReader, ref, "VPC", "app",
+	)
+	require.True(errors.Is(err, ackerr.ResourceReferenceNamespaceNotPermitted))
+
+	// Target namespace listing the referencing namespace allows it
+	apiReader = mockNamespaceReader("platform", strPtr("team-a, app"))
+	resolved, err := ackcond.ResolveReference(
+		context.TODO(), apiReader, ref, "VPC", "app",
+	)
+	require.Nil(err)
+	require.Equal(types.NamespacedName{Namespace: "platform", Name: "shared-vpc"}, resolved.Key)
+
+	// Wildcard allows references from every namespace
+	apiReader = mockNamespaceReader("platform", strPtr("*"))
+	_, err = ackcond.ResolveReference(
+		context.TODO(), apiReader, ref, "VPC", "app",
+	)
+	require.Nil(err)
+}
@@ -25,20 +25,30 @@ import (
 	"go.uber.org/zap/zapcore"
 	ctrlrt "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	acktags "github.com/aws-controllers-k8s/runtime/pkg/tags"
 )
 
 const (
-	flagEnableLeaderElection = "enable-leader-election"
-	flagMetricAddr           = "metrics-addr"
-	flagEnableDevLogging     = "enable-development-logging"
-	flagAWSRegion            = "aws-region"
-	flagAWSEndpointURL       = "aws-endpoint-url"
-	flagLogLevel             = "log-level"
-	flagResourceTags         = "resource-tags"
-	flagWatchNamespace       = "watch-namespace"
-	flagEnableWebhookServer  = "enable-webhook-server"
-	flagWebhookServerAddr    = "webhook-server-addr"
-	envVarAWSRegion          = "AWS_REGION"
+	flagEnableLeaderElection      = "enable-leader-election"
+	flagMetricAddr                = "metrics-addr"
+	flagEnableDevLogging          = "enable-development-logging"
+	flagAWSRegion                 = "aws-region"
+	flagAWSEndpointURL            = "aws-endpoint-url"
+	flagLogLevel                  = "log-level"
+	flagResourceTags              = "resource-tags"
+	flagStructuredTags            = "structured-resource-tags"
+	flagWatchNamespace            = "watch-namespace"
+	flagEnableWebhookServer       = "enable-webhook-server"
+	flagWebhookServerAddr         = "webhook-server-addr"
+	flagWebhookCertSecret         = "webhook-cert-secret"
+	flagWebhookServiceName        = "webhook-service-name"
+	flagWebhookNamespace          = "webhook-namespace"
+	flagClusterName               = "cluster-name"
+	flagAllowCrossAccountAdoption = "allow-cross-account-adoption"
+	envVarAWSRegion               = "AWS_REGION"
+	envVarK8sNamespace            = "K8S_NAMESPACE"
+	defaultNamespace              = "ack-system"
 )
 
 // Config contains configuration otpions for ACK service controllers
@@ -51,9 +61,34 @@ type Config struct {
 	EndpointURL              string
 	LogLevel                 string
 	ResourceTags             []string
-	WatchNamespace           string
-	EnableWebhookServer      bool
-	WebhookServerAddr        string
+	// StructuredResourceTags is a YAML or JSON object of default resource tags
+	StructuredResourceTags string
+	WatchNamespace         string
+	EnableWebhookServer    bool
+	WebhookServerAddr      string
+	// WebhookCertSecret is the name of the Secret in which the service
+	// controller stores the self-signed serving certificate of the webhook
+	// server, which it provisions and rotates. When empty, the serving
+	// certificate is expected to be provided in the certificate directory of
+	// the webhook server.
+	WebhookCertSecret string
+	// WebhookServiceName is the name of the Service exposing the webhook
+	// server. It is required when WebhookCertSecret is set.
+	WebhookServiceName string
+	// WebhookNamespace is the namespace of the webhook certificate Secret and
+	// Service
+	WebhookNamespace string
+	// ClusterName is the name of the cluster, expanded in %CLUSTER_NAME% tags
+	ClusterName string
+	// AllowCrossAccountAdoption allows AdoptedResources to adopt AWS
+	// resources owned by AWS accounts other than the default AWS account of
+	// their namespace, and the resources created for them to keep being
+	// managed in those AWS accounts.
+	AllowCrossAccountAdoption bool
+	// ControllerVersion is the version of the service controller. It is not
+	// bound to a flag and is set by the service controller from its version
+	// information.
+	ControllerVersion string
 }
 
 // BindFlags defines CLI/runtime configuration options
@@ -73,6 +108,24 @@ func (cfg *Config) BindFlags() {
 		"0.0.0.0:9433",
 		"The address the webhook endpoint binds to.",
 	)
+	flag.StringVar(
+		&cfg.WebhookCertSecret, flagWebhookCertSecret,
+		"",
+		"The name of the Secret in which the service controller provisions and rotates the "+
+			"self-signed serving certificate of the webhook server. By default the serving "+
+			"certificate must be provided in the certificate directory of the webhook server",
+	)
+	flag.StringVar(
+		&cfg.WebhookServiceName, flagWebhookServiceName,
+		"",
+		"The name of the Service exposing the webhook server. Required with --"+flagWebhookCertSecret,
+	)
+	flag.StringVar(
+		&cfg.WebhookNamespace, flagWebhookNamespace,
+		envutil.WithDefault(envVarK8sNamespace, defaultNamespace),
+		"The namespace of the Secret of --"+flagWebhookCertSecret+
+			" and of the Service of --"+flagWebhookServiceName,
+	)
 	flag.BoolVar(
 		&cfg.EnableLeaderElection, flagEnableLeaderElection,
 		false,
@@ -105,7 +158,16 @@ func (cfg *Config) BindFlags() {
 	flag.StringSliceVar(
 		&cfg.ResourceTags, flagResourceTags,
 		[]string{},
-		"Configures the ACK service controller to always set key/value pairs tags on resources that it manages.",
+		"Configures the ACK service controller to always set key/value pairs tags on resources that it manages. "+
+			"Each tag is a key=value pair whose value may contain '='. Use --"+flagStructuredTags+
+			" for values containing commas",
+	)
+	flag.StringVar(
+		&cfg.StructuredResourceTags, flagStructuredTags,
+		"",
+		"A YAML or JSON object of tag keys to tag values that the ACK service controller always sets on "+
+			"resources that it manages, in addition to --"+flagResourceTags+". "+
+			"Its tags override the ones of --"+flagResourceTags+" with the same key",
 	)
 	flag.StringVar(
 		&cfg.WatchNamespace, flagWatchNamespace,
@@ -113,6 +175,18 @@ func (cfg *Config) BindFlags() {
 		"Specific namespace the service controller will watch for object creation from CRD. "+
 			" By default it will listen to all namespaces",
 	)
+	flag.StringVar(
+		&cfg.ClusterName, flagClusterName,
+		"",
+		"The name of the Kubernetes cluster the service controller runs in. "+
+			"It is used to expand the %CLUSTER_NAME% token of resource tags",
+	)
+	flag.BoolVar(
+		&cfg.AllowCrossAccountAdoption, flagAllowCrossAccountAdoption,
+		false,
+		"Allow AdoptedResources to adopt AWS resources owned by AWS accounts other than "+
+			"the default AWS account of their namespace",
+	)
 }
 
 // SetupLogger initializes the logger used in the service controller
@@ -171,5 +245,55 @@ func (cfg *Config) Validate() error {
 	if cfg.EnableWebhookServer && cfg.WebhookServerAddr == "" {
 		return errors.New("empty webhook server address")
 	}
+
+	if cfg.EnableWebhookServer && cfg.WebhookCertSecret != "" && cfg.WebhookServiceName == "" {
+		return fmt.Errorf("--%s is required with --%s", flagWebhookServiceName, flagWebhookCertSecret)
+	}
+
+	if err := cfg.validateResourceTags(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetResourceTags returns the tags that the ACK service controller sets on
+// the resources that it manages, keyed by tag key. The tag values are
+// templates that are expanded for each resource.
+func (cfg *Config) GetResourceTags() (map[string]string, error) {
+	resourceTags := map[string]string{}
+	for _, tagKeyVal := range cfg.ResourceTags {
+		key, val, err := acktags.ParseKeyValue(tagKeyVal)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --%s: %v", flagResourceTags, err)
+		}
+		resourceTags[key] = val
+	}
+	if cfg.StructuredResourceTags != "" {
+		structured, err := acktags.ParseStructured(cfg.StructuredResourceTags)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --%s: %v", flagStructuredTags, err)
+		}
+		for key, val := range structured {
+			resourceTags[key] = val
+		}
+	}
+	return resourceTags, nil
+}
+
+// validateResourceTags ensures that the resource tags can be parsed and that
+// their keys and value templates satisfy the AWS tag constraints
+func (cfg *Config) validateResourceTags() error {
+	resourceTags, err := cfg.GetResourceTags()
+	if err != nil {
+		return err
+	}
+	for key, val := range resourceTags {
+		if err := acktags.ValidateKey(key); err != nil {
+			return fmt.Errorf("invalid resource tag: %v", err)
+		}
+		if err := acktags.ValidateTemplate(val); err != nil {
+			return fmt.Errorf("invalid resource tag %q: %v", key, err)
+		}
+	}
 	return nil
 }
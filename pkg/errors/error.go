@@ -58,6 +58,23 @@ var (
 	// SecretNotFound is returned if specified kubernetes secret is not found.
 	SecretNotFound = fmt.Errorf(
 		"kubernetes secret not found")
+	// DependentResourcesExist is returned when a resource cannot be deleted
+	// because other resources still reference it
+	DependentResourcesExist = fmt.Errorf(
+		"resource is still referenced by other resources")
+	// InvalidAdoptionPolicy is returned when the adoption policy annotation of
+	// a resource is not one of the supported adoption policies
+	InvalidAdoptionPolicy = fmt.Errorf(
+		"invalid adoption policy")
+	// DeletionProtected is returned when a resource cannot be deleted because
+	// deletion protection is enabled for it
+	DeletionProtected = fmt.Errorf(
+		"resource is protected from deletion")
+	// OwnerAccountIDNotAllowed is returned when the owner account ID of a
+	// resource differs from the default AWS account of its namespace, and
+	// managing the resource in that AWS account is not allowed
+	OwnerAccountIDNotAllowed = fmt.Errorf(
+		"owner account ID not allowed")
 )
 
 // AWSError returns the type conversion for the supplied error to an aws-sdk-go
@@ -47,6 +47,20 @@ var (
 	ResourceReferenceMissingTargetField = fmt.Errorf(
 		"the referenced resource is missing the target field",
 	)
+	// ResourceReferenceNameAndARNNotSupported indicates that the user
+	// specified both the name and the ARN of the referenced resource inside
+	// an AWSResourceReference
+	ResourceReferenceNameAndARNNotSupported = fmt.Errorf(
+		"both name and ARN cannot be used together in a resource reference",
+	)
+	// ResourceReferenceNamespaceNotPermitted indicates that the resource
+	// referred from AWSResourceReferenceWrapper lives in a namespace that
+	// does not allow references from the namespace of the referencing
+	// resource
+	ResourceReferenceNamespaceNotPermitted = fmt.Errorf(
+		"the namespace of the referenced resource does not allow references" +
+			" from this namespace",
+	)
 )
 
 // ResourceReferenceOrIDRequiredFor returns a ResourceReferenceOrIDRequired error
@@ -90,3 +104,24 @@ func ResourceReferenceMissingTargetFieldFor(resource string, namespace string,
 		", targetField:%s", ResourceReferenceMissingTargetField,
 		resource, namespace, name, targetField)
 }
+
+// ResourceReferenceNameAndARNNotSupportedFor returns a
+// ResourceReferenceNameAndARNNotSupported error for supplied resource and the
+// conflicting fields
+func ResourceReferenceNameAndARNNotSupportedFor(resource string,
+	fields ...string,
+) error {
+	return fmt.Errorf("%w. resource:%s, fields:%s",
+		ResourceReferenceNameAndARNNotSupported, resource,
+		strings.Join(fields, ","))
+}
+
+// ResourceReferenceNamespaceNotPermittedFor returns a
+// ResourceReferenceNamespaceNotPermitted for supplied resource
+func ResourceReferenceNamespaceNotPermittedFor(resource string,
+	namespace string, name string, fromNamespace string,
+) error {
+	return fmt.Errorf("%w. resource:%s, namespace:%s, name:%s"+
+		", fromNamespace:%s", ResourceReferenceNamespaceNotPermitted,
+		resource, namespace, name, fromNamespace)
+}
@@ -0,0 +1,276 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package export renders existing AWS resources as CR manifests that ACK
+// service controllers adopt when they are applied.
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	"github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	ackutil "github.com/aws-controllers-k8s/runtime/pkg/util"
+)
+
+const (
+	// yamlDocumentSeparator separates the manifests of a YAML stream
+	yamlDocumentSeparator = "---\n"
+)
+
+// Exporter renders existing AWS resources as custom resource (CR) manifests
+// that can be checked into source control and applied to a Kubernetes cluster.
+//
+// The rendered manifests only contain the Spec of the AWS resources, without
+// the fields that the resource manager late-initializes, and are annotated so
+// that the ACK service controller adopts the existing AWS resources instead of
+// creating new ones.
+type Exporter struct {
+	scheme    *k8sruntime.Scheme
+	rmf       acktypes.AWSResourceManagerFactory
+	rm        acktypes.AWSResourceManager
+	namespace string
+}
+
+// WithNamespace sets the namespace of the rendered manifests. By default, the
+// manifests do not have a namespace.
+func (e *Exporter) WithNamespace(namespace string) *Exporter {
+	e.namespace = namespace
+	return e
+}
+
+// Export reads the AWS resources with the supplied identifiers and writes their
+// manifests to the supplied writer as a stream of YAML documents
+func (e *Exporter) Export(
+	ctx context.Context,
+	w io.Writer,
+	ids ...*ackv1alpha1.AWSIdentifiers,
+) error {
+	for i, id := range ids {
+		manifest, err := e.Manifest(ctx, id)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			if _, err := io.WriteString(w, yamlDocumentSeparator); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write(manifest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Manifest reads the AWS resource with the supplied identifiers and returns
+// its YAML manifest
+func (e *Exporter) Manifest(
+	ctx context.Context,
+	ids *ackv1alpha1.AWSIdentifiers,
+) ([]byte, error) {
+	if ids == nil || (ids.NameOrID == "" && ids.ARN == nil) {
+		return nil, ackerr.MissingNameIdentifier
+	}
+	name := ackutil.ToDNS1123Subdomain(ids.NameOrID)
+	if name == "" && ids.ARN != nil {
+		name = ackutil.ToDNS1123Subdomain(ackutil.NameFromARN(string(*ids.ARN)))
+	}
+	if name == "" {
+		return nil, fmt.Errorf(
+			"unable to derive a custom resource name from identifiers %v", *ids,
+		)
+	}
+
+	latest, err := e.readOne(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	gvk, err := apiutil.GVKForObject(latest.RuntimeObject(), e.scheme)
+	if err != nil {
+		return nil, err
+	}
+	content, err := k8sruntime.DefaultUnstructuredConverter.ToUnstructured(
+		latest.RuntimeObject(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	spec, _ := content["spec"].(map[string]interface{})
+	lateInitialized, err := e.lateInitializedSpec(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	removeFields(spec, lateInitialized)
+
+	metadata := map[string]interface{}{
+		"name": name,
+		"annotations": map[string]interface{}{
+			ackv1alpha1.AnnotationAdoptionPolicy: string(ackv1alpha1.AdoptionPolicyAdoptOnly),
+		},
+	}
+	if e.namespace != "" {
+		metadata["namespace"] = e.namespace
+	}
+	manifest := map[string]interface{}{
+		"apiVersion": gvk.GroupVersion().String(),
+		"kind":       gvk.Kind,
+		"metadata":   metadata,
+	}
+	if len(spec) > 0 {
+		manifest["spec"] = spec
+	}
+	return yaml.Marshal(manifest)
+}
+
+// readOne returns the AWS resource with the supplied identifiers, as read by
+// the resource manager
+func (e *Exporter) readOne(
+	ctx context.Context,
+	ids *ackv1alpha1.AWSIdentifiers,
+) (acktypes.AWSResource, error) {
+	res, err := e.newResource(ids)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := e.rm.ReadOne(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+	if ackcompare.IsNil(latest) {
+		return nil, ackerr.NotFound
+	}
+	return latest, nil
+}
+
+// lateInitializedSpec returns the Spec fields that the resource manager
+// late-initializes for the AWS resource with the supplied identifiers. The
+// fields are found by late-initializing a resource that only contains the
+// identifiers of the AWS resource.
+func (e *Exporter) lateInitializedSpec(
+	ctx context.Context,
+	ids *ackv1alpha1.AWSIdentifiers,
+) (map[string]interface{}, error) {
+	res, err := e.newResource(ids)
+	if err != nil {
+		return nil, err
+	}
+	before, err := k8sruntime.DefaultUnstructuredConverter.ToUnstructured(
+		res.RuntimeObject(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	lateInitialized, err := e.rm.LateInitialize(ctx, res)
+	if err != nil {
+		// An incomplete late initialization still returns the fields that
+		// were late-initialized so far
+		var requeueNeededAfter *requeue.RequeueNeededAfter
+		if !errors.As(err, &requeueNeededAfter) {
+			return nil, err
+		}
+	}
+	if ackcompare.IsNil(lateInitialized) {
+		return nil, nil
+	}
+	after, err := k8sruntime.DefaultUnstructuredConverter.ToUnstructured(
+		lateInitialized.RuntimeObject(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	beforeSpec, _ := before["spec"].(map[string]interface{})
+	afterSpec, _ := after["spec"].(map[string]interface{})
+	return addedFields(beforeSpec, afterSpec), nil
+}
+
+// newResource returns a new AWSResource of the kind managed by the resource
+// manager, with the supplied identifiers
+func (e *Exporter) newResource(
+	ids *ackv1alpha1.AWSIdentifiers,
+) (acktypes.AWSResource, error) {
+	rd := e.rmf.ResourceDescriptor()
+	res := rd.ResourceFromRuntimeObject(rd.EmptyRuntimeObject())
+	if err := res.SetIdentifiers(ids); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// addedFields returns the fields of the supplied `after` object that are not
+// present in the supplied `before` object. Objects are compared recursively.
+func addedFields(
+	before map[string]interface{},
+	after map[string]interface{},
+) map[string]interface{} {
+	added := map[string]interface{}{}
+	for k, v := range after {
+		beforeValue, ok := before[k]
+		if !ok {
+			added[k] = v
+			continue
+		}
+		beforeObj, beforeIsObj := beforeValue.(map[string]interface{})
+		afterObj, afterIsObj := v.(map[string]interface{})
+		if beforeIsObj && afterIsObj {
+			if nested := addedFields(beforeObj, afterObj); len(nested) > 0 {
+				added[k] = nested
+			}
+		}
+	}
+	return added
+}
+
+// removeFields recursively removes the supplied fields from the supplied
+// object. Objects that are left empty are removed as well.
+func removeFields(
+	obj map[string]interface{},
+	fields map[string]interface{},
+) {
+	for k, v := range fields {
+		fieldsObj, fieldsIsObj := v.(map[string]interface{})
+		objObj, objIsObj := obj[k].(map[string]interface{})
+		if fieldsIsObj && objIsObj {
+			removeFields(objObj, fieldsObj)
+			if len(objObj) > 0 {
+				continue
+			}
+		}
+		delete(obj, k)
+	}
+}
+
+// NewExporter returns a new Exporter for the AWS resources managed by the
+// supplied resource manager. The supplied scheme must contain the custom
+// resource type of the resource manager factory.
+func NewExporter(
+	scheme *k8sruntime.Scheme,
+	rmf acktypes.AWSResourceManagerFactory,
+	rm acktypes.AWSResourceManager,
+) *Exporter {
+	return &Exporter{
+		scheme: scheme,
+		rmf:    rmf,
+		rm:     rm,
+	}
+}
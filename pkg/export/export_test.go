@@ -0,0 +1,149 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package export_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	k8sobj "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackmocks "github.com/aws-controllers-k8s/runtime/mocks/pkg/types"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	"github.com/aws-controllers-k8s/runtime/pkg/export"
+	"github.com/aws-controllers-k8s/runtime/pkg/requeue"
+)
+
+// book returns an AWSResource backed by an unstructured Book object with the
+// supplied Spec
+func book(spec map[string]interface{}) *ackmocks.AWSResource {
+	obj := &k8sobj.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "bookstore.services.k8s.aws/v1alpha1",
+		"kind":       "Book",
+		"spec":       spec,
+	}}
+	res := &ackmocks.AWSResource{}
+	res.On("RuntimeObject").Return(obj)
+	return res
+}
+
+func mockExporter(
+	ids *ackv1alpha1.AWSIdentifiers,
+) (*export.Exporter, *ackmocks.AWSResourceManager, *ackmocks.AWSResource) {
+	empty := &k8sobj.Unstructured{}
+	desired := book(map[string]interface{}{"name": ids.NameOrID})
+	desired.On("SetIdentifiers", ids).Return(nil)
+
+	rd := &ackmocks.AWSResourceDescriptor{}
+	rd.On("EmptyRuntimeObject").Return(empty)
+	rd.On("ResourceFromRuntimeObject", empty).Return(desired)
+
+	rmf := &ackmocks.AWSResourceManagerFactory{}
+	rmf.On("ResourceDescriptor").Return(rd)
+
+	rm := &ackmocks.AWSResourceManager{}
+	return export.NewExporter(k8sruntime.NewScheme(), rmf, rm), rm, desired
+}
+
+func TestExporter_Manifest(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	ids := &ackv1alpha1.AWSIdentifiers{NameOrID: "My_Book"}
+	e, rm, desired := mockExporter(ids)
+
+	latest := book(map[string]interface{}{
+		"name":   "My_Book",
+		"author": "someone",
+		"encryption": map[string]interface{}{
+			"enabled": true,
+			"type":    "AES256",
+		},
+		"storageClass": "STANDARD",
+	})
+	lateInitialized := book(map[string]interface{}{
+		"name": "My_Book",
+		"encryption": map[string]interface{}{
+			"type": "AES256",
+		},
+		"storageClass": "STANDARD",
+	})
+	rm.On("ReadOne", ctx, desired).Return(latest, nil)
+	rm.On("LateInitialize", ctx, desired).Return(
+		lateInitialized, requeue.NeededAfter(nil, 0),
+	)
+
+	manifest, err := e.WithNamespace("books").Manifest(ctx, ids)
+	require.Nil(err)
+	require.Equal(`apiVersion: bookstore.services.k8s.aws/v1alpha1
+kind: Book
+metadata:
+  annotations:
+    services.k8s.aws/adoption-policy: adopt-only
+  name: my-book
+  namespace: books
+spec:
+  author: someone
+  encryption:
+    enabled: true
+  name: My_Book
+`, string(manifest))
+}
+
+func TestExporter_Export(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	ids := &ackv1alpha1.AWSIdentifiers{NameOrID: "my-book"}
+	e, rm, desired := mockExporter(ids)
+
+	latest := book(map[string]interface{}{"name": "my-book"})
+	rm.On("ReadOne", ctx, desired).Return(latest, nil)
+	rm.On("LateInitialize", ctx, desired).Return(desired, nil)
+
+	var out bytes.Buffer
+	require.Nil(e.Export(ctx, &out, ids, ids))
+	doc := `apiVersion: bookstore.services.k8s.aws/v1alpha1
+kind: Book
+metadata:
+  annotations:
+    services.k8s.aws/adoption-policy: adopt-only
+  name: my-book
+spec:
+  name: my-book
+`
+	require.Equal(doc+"---\n"+doc, out.String())
+}
+
+func TestExporter_Manifest_NotFound(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	ids := &ackv1alpha1.AWSIdentifiers{NameOrID: "my-book"}
+	e, rm, desired := mockExporter(ids)
+	rm.On("ReadOne", ctx, desired).Return(nil, ackerr.NotFound)
+
+	_, err := e.Manifest(ctx, ids)
+	require.Equal(ackerr.NotFound, err)
+	rm.AssertNotCalled(t, "LateInitialize", ctx, desired)
+}
+
+func TestExporter_Manifest_MissingIdentifiers(t *testing.T) {
+	require := require.New(t)
+	e, _, _ := mockExporter(&ackv1alpha1.AWSIdentifiers{})
+
+	_, err := e.Manifest(context.TODO(), &ackv1alpha1.AWSIdentifiers{})
+	require.Equal(ackerr.MissingNameIdentifier, err)
+}
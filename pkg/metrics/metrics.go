@@ -44,6 +44,18 @@ var (
 			"status_code",
 		},
 	)
+	conversionErrorTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ack_conversion_error_total",
+			Help: "Total number of custom resources the conversion webhook of the controller failed to convert between API versions.",
+		},
+		[]string{
+			"service",
+			"kind",
+			"from_version",
+			"to_version",
+		},
+	)
 )
 
 // Metrics contains the set of Prometheus metric objects used to store counter
@@ -58,6 +70,9 @@ type Metrics struct {
 	// requests made by the service controller that resulted in an HTTP 4XX or
 	// 5XX status code
 	obAPIRequestErrorTotal *prometheus.CounterVec
+	// conversionErrorTotal contains the total number of custom resources
+	// that the conversion webhook failed to convert between API versions
+	conversionErrorTotal *prometheus.CounterVec
 }
 
 // RecordAPICall increments appropriate metrics tracking the count and duration
@@ -89,6 +104,27 @@ func (m *Metrics) RecordAPICall(
 	}
 }
 
+// RecordConversionError increments the metric tracking the count of custom
+// resources that could not be converted between API versions
+func (m *Metrics) RecordConversionError(
+	// The kind of the custom resource, e.g. "Bucket"
+	kind string,
+	// The API version the custom resource was converted from, e.g.
+	// "s3.services.k8s.aws/v1alpha1"
+	fromVersion string,
+	// The API version the custom resource was converted to
+	toVersion string,
+) {
+	m.conversionErrorTotal.With(
+		prometheus.Labels{
+			"service":      m.serviceID,
+			"kind":         kind,
+			"from_version": fromVersion,
+			"to_version":   toVersion,
+		},
+	).Inc()
+}
+
 // Collectors simply provides an iterator over the `prometheus.Collector`
 // interface pointers of the underlying metrics. This allows a
 // `prometheus.Registerer` (like controller-runtime's metrics.Registry) to
@@ -97,6 +133,7 @@ func (m *Metrics) Collectors() []prometheus.Collector {
 	return []prometheus.Collector{
 		m.obAPIRequestTotal,
 		m.obAPIRequestErrorTotal,
+		m.conversionErrorTotal,
 	}
 }
 
@@ -107,5 +144,6 @@ func NewMetrics(serviceID string) *Metrics {
 		serviceID:              serviceID,
 		obAPIRequestTotal:      outboundAPIRequestsTotal,
 		obAPIRequestErrorTotal: outboundAPIRequestsErrorTotal,
+		conversionErrorTotal:   conversionErrorTotal,
 	}
 }
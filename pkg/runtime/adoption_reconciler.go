@@ -15,12 +15,15 @@ package runtime
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	ctrlrt "sigs.k8s.io/controller-runtime"
@@ -84,12 +87,7 @@ func (r *adoptionReconciler) reconcile(ctx context.Context, req ctrlrt.Request)
 	gk := r.getTargetResourceGroupKind(res)
 
 	// Check if the target API group matches with the controller
-	var controllerRMF acktypes.AWSResourceManagerFactory
-	for _, v := range r.sc.GetResourceManagerFactories() {
-		controllerRMF = v
-		break
-	}
-	if gk.Group != controllerRMF.ResourceDescriptor().GroupKind().Group {
+	if !r.isServiceGroup(gk.Group) {
 		ackrtlog.DebugAdoptedResource(r.log, res, "target resource API group is not of this service. no-op")
 		return nil
 	}
@@ -97,43 +95,35 @@ func (r *adoptionReconciler) reconcile(ctx context.Context, req ctrlrt.Request)
 	// Look up the rmf for the given target resource GVK
 	rmf, ok := (r.sc.GetResourceManagerFactories())[gk.String()]
 	if !ok {
-		// TODO(RedbackThomson): Is printing necessary? Some other controller
-		// might be able to reconcile - printing would be verbose
-		return ackerr.ResourceManagerFactoryNotFound
+		// The target API group belongs to this controller, so no other
+		// controller is able to reconcile an unknown kind
+		return r.onTerminal(ctx, res, fmt.Errorf(
+			"%w: unknown kind %s", ackerr.ResourceManagerFactoryNotFound, gk.String(),
+		))
 	}
 
 	if !rmf.IsAdoptable() {
-		// TODO(RedbackThomson): Place into terminal state + condition
-		return ackerr.NotAdoptable
+		return r.onTerminal(ctx, res, fmt.Errorf(
+			"%w: kind %s", ackerr.NotAdoptable, gk.String(),
+		))
 	}
 
 	targetDescriptor := rmf.ResourceDescriptor()
-	acctID := r.getOwnerAccountID(res)
-	region := r.getRegion(res)
-	roleARN := r.getRoleARN(acctID)
-	endpointURL := r.getEndpointURL(res)
-
-	sess, err := r.sc.NewSession(
-		region, &endpointURL, roleARN,
-		targetDescriptor.EmptyRuntimeObject().GetObjectKind().GroupVersionKind(),
-	)
-	if err != nil {
-		return err
-	}
 
 	ackrtlog.InfoAdoptedResource(r.log, res, "starting adoption reconciliation")
 
-	rm, err := rmf.ManagerFor(
-		r.cfg, r.log, r.metrics, r, sess, acctID, region,
-	)
-	if err != nil {
-		return err
-	}
-
 	if res.DeletionTimestamp != nil {
 		return r.cleanup(ctx, res)
 	}
 
+	rm, err := r.managerFor(rmf, res, res.Spec.AWS)
+	if err != nil {
+		if errors.Is(err, ackerr.OwnerAccountIDNotAllowed) {
+			return r.onTerminal(ctx, res, err)
+		}
+		return err
+	}
+
 	// Determine whether the reason is in a terminal state
 	if r.isAdopted(ctx, res) {
 		return nil
@@ -156,6 +146,11 @@ func (r *adoptionReconciler) Sync(
 
 	described, err := rm.ReadOne(ctx, readableResource)
 	if err != nil {
+		if err == ackerr.NotFound {
+			return r.onTerminal(ctx, desired, fmt.Errorf(
+				"%w: no AWS resource matches the supplied identifiers", err,
+			))
+		}
 		return r.onError(ctx, desired, err)
 	}
 
@@ -212,10 +207,45 @@ func (r *adoptionReconciler) Sync(
 		targetMeta.SetNamespace(desired.ObjectMeta.Namespace)
 	}
 
+	// Let the resource reconciler know whether it may apply the differences
+	// between the target Spec and the AWS resource right after the adoption.
+	// The annotations are copied so that the AdoptedResource Spec is left
+	// untouched.
+	annotations := map[string]string{}
+	for k, v := range targetMeta.GetAnnotations() {
+		annotations[k] = v
+	}
+	driftPolicy := getDriftPolicy(desired)
+	annotations[ackv1alpha1.AnnotationAdoptionDriftPolicy] = string(driftPolicy)
+	if driftPolicy == ackv1alpha1.AdoptionDriftPolicyObserve {
+		// Custom resources are created with a generation of 1, which is the
+		// generation of the target Spec as adopted
+		annotations[ackv1alpha1.AnnotationAdoptionObservedGeneration] = "1"
+	} else {
+		delete(annotations, ackv1alpha1.AnnotationAdoptionObservedGeneration)
+	}
+	// The target resource must keep being managed in the region and account
+	// that the AWS resource was adopted from.
+	if ids := desired.Spec.AWS; ids != nil {
+		if ids.Region != nil && *ids.Region != "" {
+			annotations[ackv1alpha1.AnnotationRegion] = string(*ids.Region)
+		}
+		if ids.OwnerAccountID != nil && *ids.OwnerAccountID != "" {
+			annotations[ackv1alpha1.AnnotationOwnerAccountID] = string(*ids.OwnerAccountID)
+		}
+	}
+	targetMeta.SetAnnotations(annotations)
+
 	described.SetObjectMeta(*targetMeta)
 	targetDescriptor.MarkManaged(described)
 	targetDescriptor.MarkAdopted(described)
 
+	if desired.Spec.Kubernetes != nil {
+		if err := applySpecOverride(described.RuntimeObject(), desired.Spec.Kubernetes.Spec); err != nil {
+			return r.onTerminal(ctx, desired, fmt.Errorf("invalid spec override: %w", err))
+		}
+	}
+
 	// Only create the described resource if it does not already exist
 	// in k8s cluster.
 	if err := r.apiReader.Get(ctx, types.NamespacedName{
@@ -248,9 +278,137 @@ func (r *adoptionReconciler) Sync(
 		return r.onError(ctx, desired, err)
 	}
 
+	target := &ackv1alpha1.AdoptedResourceTarget{
+		Namespace: described.MetaObject().GetNamespace(),
+		Name:      described.MetaObject().GetName(),
+		UID:       described.MetaObject().GetUID(),
+	}
+	if desired.Spec.Kubernetes != nil {
+		target.Group = desired.Spec.Kubernetes.Group
+		target.Kind = desired.Spec.Kubernetes.Kind
+	}
+	var arn *ackv1alpha1.AWSResourceName
+	if desired.Spec.AWS != nil {
+		arn = desired.Spec.AWS.ARN
+	}
+	if ids := described.Identifiers(); ids != nil && ids.ARN() != nil {
+		arn = ids.ARN()
+	}
+
 	// Don't attempt to patch conditions again, directly return result of
 	// 'r.onSuccess'
-	return r.onSuccess(ctx, desired)
+	return r.onSuccess(ctx, desired, target, arn)
+}
+
+// isServiceGroup returns true if the supplied API group is the API group of
+// the resources managed by the service controller
+func (r *adoptionReconciler) isServiceGroup(group string) bool {
+	for _, rmf := range r.sc.GetResourceManagerFactories() {
+		if rmf.ResourceDescriptor().GroupKind().Group == group {
+			return true
+		}
+	}
+	return false
+}
+
+// managerFor returns an AWSResourceManager produced by the supplied resource
+// manager factory for the AWS account and region that the supplied object's
+// target resources reside in. The region and owner account ID of the supplied
+// AWS identifiers, if any, take precedence over the ones of the object. An
+// error wrapping ackerr.OwnerAccountIDNotAllowed is returned if the owner
+// account ID of the identifiers is not allowed.
+func (r *adoptionReconciler) managerFor(
+	rmf acktypes.AWSResourceManagerFactory,
+	res metav1.Object,
+	ids *ackv1alpha1.AWSIdentifiers,
+) (acktypes.AWSResourceManager, error) {
+	acctID := r.getNamespaceOwnerAccountID(res.GetNamespace())
+	region := r.getRegion(res)
+	if ids != nil {
+		if ids.OwnerAccountID != nil && *ids.OwnerAccountID != "" {
+			if err := r.checkOwnerAccountID(res.GetNamespace(), *ids.OwnerAccountID); err != nil {
+				return nil, err
+			}
+			acctID = *ids.OwnerAccountID
+		}
+		if ids.Region != nil && *ids.Region != "" {
+			region = *ids.Region
+		}
+	}
+	roleARN := r.getRoleARN(acctID)
+	endpointURL := r.getEndpointURL(res)
+
+	sess, err := r.sc.NewSession(
+		region, &endpointURL, roleARN,
+		rmf.ResourceDescriptor().EmptyRuntimeObject().GetObjectKind().GroupVersionKind(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return rmf.ManagerFor(
+		r.cfg, r.log, r.metrics, r, sess, acctID, region,
+	)
+}
+
+// getDriftPolicy returns the drift policy of the supplied AdoptedResource,
+// defaulting to AdoptionDriftPolicyObserve
+func getDriftPolicy(
+	res *ackv1alpha1.AdoptedResource,
+) ackv1alpha1.AdoptionDriftPolicy {
+	if res.Spec.DriftPolicy != nil && *res.Spec.DriftPolicy != "" {
+		return *res.Spec.DriftPolicy
+	}
+	return ackv1alpha1.AdoptionDriftPolicyObserve
+}
+
+// applySpecOverride merges the supplied Spec override over the Spec of the
+// supplied object. Objects are merged recursively, any other value in the
+// override replaces the value in the object's Spec.
+func applySpecOverride(
+	obj client.Object,
+	override *k8sruntime.RawExtension,
+) error {
+	if override == nil || len(override.Raw) == 0 {
+		return nil
+	}
+	specOverride := map[string]interface{}{}
+	if err := json.Unmarshal(override.Raw, &specOverride); err != nil {
+		return err
+	}
+
+	content, err := k8sruntime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	spec, _ := content["spec"].(map[string]interface{})
+	content["spec"] = mergeObjects(spec, specOverride)
+
+	if u, ok := obj.(k8sruntime.Unstructured); ok {
+		u.SetUnstructuredContent(content)
+		return nil
+	}
+	return k8sruntime.DefaultUnstructuredConverter.FromUnstructured(content, obj)
+}
+
+// mergeObjects recursively merges the supplied override over the supplied
+// base object and returns the result
+func mergeObjects(
+	base map[string]interface{},
+	override map[string]interface{},
+) map[string]interface{} {
+	if base == nil {
+		base = map[string]interface{}{}
+	}
+	for k, v := range override {
+		baseObj, baseIsObj := base[k].(map[string]interface{})
+		overrideObj, overrideIsObj := v.(map[string]interface{})
+		if baseIsObj && overrideIsObj {
+			base[k] = mergeObjects(baseObj, overrideObj)
+			continue
+		}
+		base[k] = v
+	}
+	return base
 }
 
 // cleanup removes the finalizer from AdoptedResource so that k8s object can
@@ -294,55 +452,145 @@ func (r *adoptionReconciler) onError(
 	res *ackv1alpha1.AdoptedResource,
 	err error,
 ) error {
-	r.patchAdoptedCondition(ctx, res, err)
+	base := res.DeepCopy()
+	setAdoptedCondition(res, err)
+	_ = r.patchStatus(ctx, res, base)
 	return err
 }
 
-// onSuccess will patch the adopted resource with a adopted condition and
-// return any errors that occurred while patching
-func (r *adoptionReconciler) onSuccess(
+// onTerminal will patch the adopted resource with a terminal condition
+// describing the given error and return ackerr.Terminal, so that the adoption
+// is not retried until the adopted resource is modified
+func (r *adoptionReconciler) onTerminal(
 	ctx context.Context,
 	res *ackv1alpha1.AdoptedResource,
+	err error,
 ) error {
-	return r.patchAdoptedCondition(ctx, res, nil)
+	base := res.DeepCopy()
+	setAdoptedCondition(res, err)
+	setAdoptedResourceCondition(
+		res, ackv1alpha1.ConditionTypeTerminal, corev1.ConditionTrue, err,
+	)
+	if patchErr := r.patchStatus(ctx, res, base); patchErr != nil {
+		return patchErr
+	}
+	ackrtlog.InfoAdoptedResource(r.log, res, "adoption failed", "error", err)
+	return ackerr.Terminal
 }
 
-// patchAdoptedCondition updates the adopted condition status of the adopted resource
-// The resource passed in the parameter gets updated with the conditions
-func (r *adoptionReconciler) patchAdoptedCondition(
+// onSuccess will patch the adopted resource with a adopted condition and a
+// reference to the target resource, and return any errors that occurred while
+// patching
+func (r *adoptionReconciler) onSuccess(
 	ctx context.Context,
 	res *ackv1alpha1.AdoptedResource,
-	err error,
+	target *ackv1alpha1.AdoptedResourceTarget,
+	arn *ackv1alpha1.AWSResourceName,
 ) error {
 	base := res.DeepCopy()
+	setAdoptedCondition(res, nil)
+	removeAdoptedResourceCondition(res, ackv1alpha1.ConditionTypeTerminal)
+	res.Status.Target = target
+	res.Status.ARN = arn
+	return r.patchStatus(ctx, res, base)
+}
 
-	// Adopted condition
-	var adoptedCondition *ackv1alpha1.Condition = nil
-	for _, condition := range res.Status.Conditions {
-		if condition.Type == ackv1alpha1.ConditionTypeAdopted {
-			adoptedCondition = condition
+// setAdoptedCondition sets the adopted condition status of the adopted
+// resource depending on whether the supplied error is nil
+func setAdoptedCondition(
+	res *ackv1alpha1.AdoptedResource,
+	err error,
+) {
+	if err != nil {
+		setAdoptedResourceCondition(
+			res, ackv1alpha1.ConditionTypeAdopted, corev1.ConditionFalse, err,
+		)
+	} else {
+		setAdoptedResourceCondition(
+			res, ackv1alpha1.ConditionTypeAdopted, corev1.ConditionTrue, nil,
+		)
+	}
+}
+
+// setAdoptedResourceCondition sets the adopted resource's condition of the
+// supplied type to the supplied status, using the supplied error, if any, as
+// the condition's message.
+func setAdoptedResourceCondition(
+	res *ackv1alpha1.AdoptedResource,
+	condType ackv1alpha1.ConditionType,
+	status corev1.ConditionStatus,
+	err error,
+) {
+	var message *string
+	if err != nil {
+		errMessage := err.Error()
+		message = &errMessage
+	}
+	res.Status.Conditions = setAdoptionCondition(
+		res.Status.Conditions, res.Generation, condType, status, message,
+	)
+}
+
+// removeAdoptedResourceCondition removes the adopted resource's condition of
+// the supplied type, if any
+func removeAdoptedResourceCondition(
+	res *ackv1alpha1.AdoptedResource,
+	condType ackv1alpha1.ConditionType,
+) {
+	res.Status.Conditions = removeAdoptionCondition(
+		res.Status.Conditions, condType,
+	)
+}
+
+// setAdoptionCondition sets the condition of the supplied type in the supplied
+// collection of conditions to the supplied status and message, and returns
+// the resulting collection. The condition's LastTransitionTime is only updated
+// when the status actually changes.
+func setAdoptionCondition(
+	conditions []*ackv1alpha1.Condition,
+	generation int64,
+	condType ackv1alpha1.ConditionType,
+	status corev1.ConditionStatus,
+	message *string,
+) []*ackv1alpha1.Condition {
+	var cond *ackv1alpha1.Condition = nil
+	for _, condition := range conditions {
+		if condition.Type == condType {
+			cond = condition
 			break
 		}
 	}
 
-	if adoptedCondition == nil {
-		adoptedCondition = &ackv1alpha1.Condition{
-			Type: ackv1alpha1.ConditionTypeAdopted,
+	if cond == nil {
+		cond = &ackv1alpha1.Condition{
+			Type: condType,
 		}
-		res.Status.Conditions = append(res.Status.Conditions, adoptedCondition)
+		conditions = append(conditions, cond)
 	}
 
-	var errMessage string
-	if err != nil {
-		errMessage = err.Error()
-		adoptedCondition.Status = corev1.ConditionFalse
-		adoptedCondition.Message = &errMessage
-	} else {
-		adoptedCondition.Message = nil
-		adoptedCondition.Status = corev1.ConditionTrue
+	if cond.Status != status || cond.LastTransitionTime == nil {
+		now := metav1.Now()
+		cond.LastTransitionTime = &now
 	}
+	cond.Status = status
+	cond.Message = message
+	cond.ObservedGeneration = generation
+	return conditions
+}
 
-	return r.patchStatus(ctx, res, base)
+// removeAdoptionCondition returns the supplied collection of conditions
+// without the condition of the supplied type, if any
+func removeAdoptionCondition(
+	conditions []*ackv1alpha1.Condition,
+	condType ackv1alpha1.ConditionType,
+) []*ackv1alpha1.Condition {
+	res := []*ackv1alpha1.Condition{}
+	for _, condition := range conditions {
+		if condition.Type != condType {
+			res = append(res, condition)
+		}
+	}
+	return res
 }
 
 // isAdopted returns true if the AdoptedResource is in a terminal adoption state
@@ -423,31 +671,12 @@ func (r *adoptionReconciler) handleReconcileError(err error) (ctrlrt.Result, err
 	return ctrlrt.Result{}, err
 }
 
-// getOwnerAccountID returns the AWS account that owns the supplied resource.
-// The function looks to the common `Status.ACKResourceState` object, followed
-// by the default AWS account ID associated with the Kubernetes Namespace in
-// which the CR was created, followed by the AWS Account in which the IAM Role
-// that the service controller is in.
-func (r *adoptionReconciler) getOwnerAccountID(
-	res *ackv1alpha1.AdoptedResource,
-) ackv1alpha1.AWSAccountID {
-	// look for owner account id in the namespace annotations
-	namespace := res.GetNamespace()
-	accID, ok := r.cache.Namespaces.GetOwnerAccountID(namespace)
-	if ok {
-		return ackv1alpha1.AWSAccountID(accID)
-	}
-
-	// use controller configuration
-	return ackv1alpha1.AWSAccountID(r.cfg.AccountID)
-}
-
 // getEndpointURL returns the AWS account that owns the supplied resource.
 // We look for the namespace associated endpoint url, if that is set we use it.
 // Otherwise if none of these annotations are set we use the endpoint url specified
 // in the configuration
 func (r *adoptionReconciler) getEndpointURL(
-	res *ackv1alpha1.AdoptedResource,
+	res metav1.Object,
 ) string {
 	// look for endpoint url in the namespace annotations
 	namespace := res.GetNamespace()
@@ -475,7 +704,7 @@ func (r *adoptionReconciler) getRoleARN(
 // if none of these annotations are set we use the use the region specified in the
 // configuration is used
 func (r *adoptionReconciler) getRegion(
-	res *ackv1alpha1.AdoptedResource,
+	res metav1.Object,
 ) ackv1alpha1.AWSRegion {
 	// look for region in CR metadata annotations
 	resAnnotations := res.GetAnnotations()
@@ -21,17 +21,21 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sobj "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	ctrlrt "sigs.k8s.io/controller-runtime"
 	ctrlrtzap "sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
 	ctrlrtclientmock "github.com/aws-controllers-k8s/runtime/mocks/controller-runtime/pkg/client"
 	ackmocks "github.com/aws-controllers-k8s/runtime/mocks/pkg/types"
 	ackcfg "github.com/aws-controllers-k8s/runtime/pkg/config"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
 	ackmetrics "github.com/aws-controllers-k8s/runtime/pkg/metrics"
 	ackrt "github.com/aws-controllers-k8s/runtime/pkg/runtime"
 	ackrtcache "github.com/aws-controllers-k8s/runtime/pkg/runtime/cache"
@@ -115,6 +119,11 @@ func setupMockAwsResource(
 	rmo.On("GetGenerateName").Return("")
 	res.On("DeepCopy").Return(resDeepCopy)
 	res.On("SetStatus", resDeepCopy).Run(func(args mock.Arguments) {})
+
+	arn := ackv1alpha1.AWSResourceName("arn:aws:bookstore:us-west-2:012345678912:book/name")
+	ids := &ackmocks.AWSResourceIdentifiers{}
+	ids.On("ARN").Return(&arn)
+	res.On("Identifiers").Return(ids)
 }
 
 func setupMockManager(manager *ackmocks.AWSResourceManager, ctx context.Context, res *ackmocks.AWSResource) {
@@ -386,6 +395,210 @@ func TestSync_HappyCase(t *testing.T) {
 	assertAWSResourceCreation(true, t, ctx, kc, statusWriter, res, resDeepCopy)
 	assertManaged(true, t, ctx, kc, adoptedRes)
 	assertAdoptedCondition("True", require, t, ctx, kc, statusWriter, adoptedRes)
+	// Target resource and ARN are reported in the status
+	require.NotNil(adoptedRes.Status.Target)
+	require.Equal(Namespace, adoptedRes.Status.Target.Namespace)
+	require.Equal(Name, adoptedRes.Status.Target.Name)
+	require.NotNil(adoptedRes.Status.ARN)
+	require.Equal(
+		"arn:aws:bookstore:us-west-2:012345678912:book/name",
+		string(*adoptedRes.Status.ARN),
+	)
+}
+
+func TestSync_NoAWSIdentifiers(t *testing.T) {
+	// Setup
+	require := require.New(t)
+	// Mock resource creation
+	r, kc, apiReader := mockReconciler()
+	descriptor, res, resDeepCopy := mockDescriptorAndAWSResource()
+	manager := mockManager()
+	adoptedRes := adoptedResource(Namespace, Name)
+	adoptedRes.Spec.AWS = nil
+	ctx := context.TODO()
+	statusWriter := &ctrlrtclientmock.StatusWriter{}
+
+	//Mock behavior setup
+	setupMockAwsResource(res, resDeepCopy, adoptedRes)
+	setupMockClient(kc, statusWriter, ctx, adoptedRes)
+	setupMockManager(manager, ctx, res)
+	setupMockDescriptor(descriptor, res)
+	setupMockApiReader(apiReader, ctx, res)
+	kc.On("Create", ctx, res.RuntimeObject()).Return(nil)
+	statusWriter.On("Update", ctx, res.RuntimeObject()).Return(nil)
+
+	// Call
+	err := r.Sync(ctx, descriptor, manager, adoptedRes)
+
+	//Assertions
+	require.Nil(err)
+	assertAdoptedCondition("True", require, t, ctx, kc, statusWriter, adoptedRes)
+	// The ARN is taken from the identifiers of the described resource
+	require.NotNil(adoptedRes.Status.ARN)
+	require.Equal(
+		"arn:aws:bookstore:us-west-2:012345678912:book/name",
+		string(*adoptedRes.Status.ARN),
+	)
+}
+
+func TestSync_SpecOverride(t *testing.T) {
+	// Setup
+	require := require.New(t)
+	// Mock resource creation
+	r, kc, apiReader := mockReconciler()
+	descriptor, res, resDeepCopy := mockDescriptorAndAWSResource()
+	manager := mockManager()
+	adoptedRes := adoptedResource(Namespace, Name)
+	adoptedRes.Spec.Kubernetes = &ackv1alpha1.TargetKubernetesResource{
+		Group: "bookstore.services.k8s.aws",
+		Kind:  "Book",
+		Spec: &k8sruntime.RawExtension{
+			Raw: []byte(`{"encryption":{"enabled":true},"tags":["new"]}`),
+		},
+	}
+	enforce := ackv1alpha1.AdoptionDriftPolicyEnforce
+	adoptedRes.Spec.DriftPolicy = &enforce
+	ctx := context.TODO()
+	statusWriter := &ctrlrtclientmock.StatusWriter{}
+
+	// The described resource is backed by an unstructured object, so that
+	// its Spec can be inspected
+	described := &k8sobj.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"name": "name",
+			"encryption": map[string]interface{}{
+				"enabled": false,
+				"type":    "AES256",
+			},
+			"tags": []interface{}{"old"},
+		},
+	}}
+	res.On("SetIdentifiers", adoptedRes.Spec.AWS).Return(nil)
+	var targetMeta v1.ObjectMeta
+	res.On("SetObjectMeta", mock.AnythingOfType("ObjectMeta")).Run(func(args mock.Arguments) {
+		targetMeta = args.Get(0).(v1.ObjectMeta)
+	})
+	metaObj := &k8sobj.Unstructured{}
+	metaObj.SetNamespace(Namespace)
+	metaObj.SetName(Name)
+	res.On("MetaObject").Return(metaObj)
+	res.On("RuntimeObject").Return(described)
+	res.On("DeepCopy").Return(resDeepCopy)
+	res.On("SetStatus", resDeepCopy).Run(func(args mock.Arguments) {})
+	ids := &ackmocks.AWSResourceIdentifiers{}
+	ids.On("ARN").Return(nil)
+	res.On("Identifiers").Return(ids)
+
+	setupMockClient(kc, statusWriter, ctx, adoptedRes)
+	setupMockManager(manager, ctx, res)
+	setupMockDescriptor(descriptor, res)
+	setupMockApiReader(apiReader, ctx, res)
+	kc.On("Create", ctx, described).Return(nil)
+	statusWriter.On("Update", ctx, described).Return(nil)
+
+	// Call
+	err := r.Sync(ctx, descriptor, manager, adoptedRes)
+
+	// Assertions
+	require.Nil(err)
+	kc.AssertCalled(t, "Create", ctx, described)
+	// The override is merged over the observed Spec
+	spec := described.Object["spec"].(map[string]interface{})
+	require.Equal("name", spec["name"])
+	require.Equal(map[string]interface{}{
+		"enabled": true,
+		"type":    "AES256",
+	}, spec["encryption"])
+	require.Equal([]interface{}{"new"}, spec["tags"])
+	// The drift policy is propagated to the target resource
+	require.Equal(
+		string(ackv1alpha1.AdoptionDriftPolicyEnforce),
+		targetMeta.Annotations[ackv1alpha1.AnnotationAdoptionDriftPolicy],
+	)
+	require.NotContains(targetMeta.Annotations, ackv1alpha1.AnnotationAdoptionObservedGeneration)
+}
+
+func TestSync_RegionAndOwnerAccountID(t *testing.T) {
+	// Setup
+	require := require.New(t)
+	// Mock resource creation
+	r, kc, apiReader := mockReconciler()
+	descriptor, res, resDeepCopy := mockDescriptorAndAWSResource()
+	manager := mockManager()
+	adoptedRes := adoptedResource(Namespace, Name)
+	region := ackv1alpha1.AWSRegion("eu-west-1")
+	acctID := ackv1alpha1.AWSAccountID("210987654321")
+	adoptedRes.Spec.AWS.Region = &region
+	adoptedRes.Spec.AWS.OwnerAccountID = &acctID
+	ctx := context.TODO()
+	statusWriter := &ctrlrtclientmock.StatusWriter{}
+
+	//Mock behavior setup
+	var targetMeta v1.ObjectMeta
+	res.On("SetObjectMeta", mock.AnythingOfType("ObjectMeta")).Run(func(args mock.Arguments) {
+		targetMeta = args.Get(0).(v1.ObjectMeta)
+	})
+	setupMockAwsResource(res, resDeepCopy, adoptedRes)
+	setupMockClient(kc, statusWriter, ctx, adoptedRes)
+	setupMockManager(manager, ctx, res)
+	setupMockDescriptor(descriptor, res)
+	setupMockApiReader(apiReader, ctx, res)
+	kc.On("Create", ctx, res.RuntimeObject()).Return(nil)
+	statusWriter.On("Update", ctx, res.RuntimeObject()).Return(nil)
+
+	// Call
+	err := r.Sync(ctx, descriptor, manager, adoptedRes)
+
+	//Assertions
+	require.Nil(err)
+	assertAWSResourceCreation(true, t, ctx, kc, statusWriter, res, resDeepCopy)
+	// The region and owner account ID are propagated to the target resource
+	require.Equal("eu-west-1", targetMeta.Annotations[ackv1alpha1.AnnotationRegion])
+	require.Equal("210987654321", targetMeta.Annotations[ackv1alpha1.AnnotationOwnerAccountID])
+	// The differences are observed until the generation of the target
+	// resource is incremented
+	require.Equal(
+		string(ackv1alpha1.AdoptionDriftPolicyObserve),
+		targetMeta.Annotations[ackv1alpha1.AnnotationAdoptionDriftPolicy],
+	)
+	require.Equal("1", targetMeta.Annotations[ackv1alpha1.AnnotationAdoptionObservedGeneration])
+}
+
+func TestSync_AWSResourceNotFound(t *testing.T) {
+	// Setup
+	require := require.New(t)
+	// Mock resource creation
+	r, kc, apiReader := mockReconciler()
+	descriptor, res, resDeepCopy := mockDescriptorAndAWSResource()
+	manager := mockManager()
+	adoptedRes := adoptedResource(Namespace, Name)
+	ctx := context.TODO()
+	statusWriter := &ctrlrtclientmock.StatusWriter{}
+
+	//Mock behavior setup
+	setupMockAwsResource(res, resDeepCopy, adoptedRes)
+	manager.On("ReadOne", ctx, res).Return(nil, ackerr.NotFound)
+	setupMockClient(kc, statusWriter, ctx, adoptedRes)
+
+	// Call
+	err := r.Sync(ctx, descriptor, manager, adoptedRes)
+
+	// Assertions
+	// The adopted resource is placed in a terminal state
+	require.Equal(ackerr.Terminal, err)
+	apiReader.AssertNotCalled(t, "Get", ctx, types.NamespacedName{
+		Namespace: Namespace,
+		Name:      Name,
+	}, res.RuntimeObject())
+	assertAWSResourceCreation(false, t, ctx, kc, statusWriter, res, resDeepCopy)
+	assertManaged(false, t, ctx, kc, adoptedRes)
+	statusWriter.AssertCalled(t, "Patch", ctx, adoptedRes, mock.AnythingOfType("*client.mergeFromPatch"))
+	require.Equal(2, len(adoptedRes.Status.Conditions))
+	require.Equal(ackv1alpha1.ConditionTypeAdopted, adoptedRes.Status.Conditions[0].Type)
+	require.Equal(corev1.ConditionFalse, adoptedRes.Status.Conditions[0].Status)
+	require.Equal(ackv1alpha1.ConditionTypeTerminal, adoptedRes.Status.Conditions[1].Type)
+	require.Equal(corev1.ConditionTrue, adoptedRes.Status.Conditions[1].Status)
+	require.Nil(adoptedRes.Status.Target)
 }
 
 // Assertion Helpers
@@ -474,3 +687,122 @@ func assertAWSResourceRead(
 		Name:      Name,
 	}, res.RuntimeObject())
 }
+
+func TestReconcile_OwnerAccountID(t *testing.T) {
+	zapOptions := ctrlrtzap.Options{
+		Development: true,
+		Level:       zapcore.InfoLevel,
+	}
+	fakeLogger := ctrlrtzap.New(ctrlrtzap.UseFlagOptions(&zapOptions))
+
+	for _, tc := range []struct {
+		name           string
+		ownerAccountID ackv1alpha1.AWSAccountID
+		allowCrossAcct bool
+		notAllowed     bool
+	}{
+		{
+			name:           "namespace account",
+			ownerAccountID: "000000000000",
+		},
+		{
+			name:           "other account",
+			ownerAccountID: "222222222222",
+			notAllowed:     true,
+		},
+		{
+			name:           "other account with cross-account adoption",
+			ownerAccountID: "222222222222",
+			allowCrossAcct: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require := require.New(t)
+			ctx := context.TODO()
+
+			adoptedRes := adoptedResource(Namespace, Name)
+			adoptedRes.Spec.Kubernetes = &ackv1alpha1.TargetKubernetesResource{
+				Group: "bookstore.services.k8s.aws",
+				Kind:  "Book",
+			}
+			acctID := tc.ownerAccountID
+			adoptedRes.Spec.AWS.OwnerAccountID = &acctID
+
+			rd := &ackmocks.AWSResourceDescriptor{}
+			rd.On("GroupKind").Return(&v1.GroupKind{
+				Group: "bookstore.services.k8s.aws",
+				Kind:  "Book",
+			})
+			rd.On("EmptyRuntimeObject").Return(&k8sobj.Unstructured{})
+			rmf := &ackmocks.AWSResourceManagerFactory{}
+			rmf.On("ResourceDescriptor").Return(rd)
+			rmf.On("IsAdoptable").Return(true)
+			sc := &ackmocks.ServiceController{}
+			sc.On("GetResourceManagerFactories").Return(
+				map[string]acktypes.AWSResourceManagerFactory{
+					"Book.bookstore.services.k8s.aws": rmf,
+				},
+			)
+			// The reconciliation stops once the session is created
+			sc.On(
+				"NewSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			).Return(nil, errors.New("no session"))
+
+			kc := &ctrlrtclientmock.Client{}
+			statusWriter := &ctrlrtclientmock.StatusWriter{}
+			kc.On("Status").Return(statusWriter)
+			statusWriter.On(
+				"Patch", ctx, mock.AnythingOfType("*v1alpha1.AdoptedResource"),
+				mock.AnythingOfType("*client.mergeFromPatch"),
+			).Return(nil)
+			apiReader := &ctrlrtclientmock.Reader{}
+			apiReader.On(
+				"Get", ctx, types.NamespacedName{Namespace: Namespace, Name: Name},
+				mock.AnythingOfType("*v1alpha1.AdoptedResource"),
+			).Return(nil).Run(func(args mock.Arguments) {
+				adoptedRes.DeepCopyInto(args.Get(2).(*ackv1alpha1.AdoptedResource))
+			})
+
+			r := ackrt.NewAdoptionReconcilerWithClient(
+				sc,
+				fakeLogger,
+				ackcfg.Config{
+					AccountID:                 "000000000000",
+					AllowCrossAccountAdoption: tc.allowCrossAcct,
+				},
+				ackmetrics.NewMetrics("bookstore"),
+				ackrtcache.New(fakeLogger),
+				kc,
+				apiReader,
+			)
+			_, err := r.Reconcile(ctx, ctrlrt.Request{
+				NamespacedName: types.NamespacedName{Namespace: Namespace, Name: Name},
+			})
+
+			if !tc.notAllowed {
+				require.NotNil(err)
+				sc.AssertCalled(
+					t, "NewSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+				)
+				statusWriter.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			// No session is created for an AWS account that is not allowed,
+			// and the adopted resource is not retried until it is modified
+			require.Nil(err)
+			sc.AssertNotCalled(
+				t, "NewSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			)
+			patched := statusWriter.Calls[0].Arguments.Get(1).(*ackv1alpha1.AdoptedResource)
+			var terminal *ackv1alpha1.Condition
+			for _, cond := range patched.Status.Conditions {
+				if cond.Type == ackv1alpha1.ConditionTypeTerminal {
+					terminal = cond
+				}
+			}
+			require.NotNil(terminal)
+			require.Equal(corev1.ConditionTrue, terminal.Status)
+			require.Contains(*terminal.Message, ackerr.OwnerAccountIDNotAllowed.Error())
+		})
+	}
+}
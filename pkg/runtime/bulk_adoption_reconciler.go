@@ -0,0 +1,568 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcfg "github.com/aws-controllers-k8s/runtime/pkg/config"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackmetrics "github.com/aws-controllers-k8s/runtime/pkg/metrics"
+	ackrtcache "github.com/aws-controllers-k8s/runtime/pkg/runtime/cache"
+	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	ackutil "github.com/aws-controllers-k8s/runtime/pkg/util"
+)
+
+const (
+	// defaultBulkAdoptionNameTemplate is the template used to render the
+	// names of the resources created by a BulkAdoption that has no
+	// NameTemplate
+	defaultBulkAdoptionNameTemplate = "%NAME_OR_ID%"
+	// bulkAdoptionListingPeriod is the period during which the AWS resources
+	// listed for the tag filters of a BulkAdoption are reused instead of
+	// being listed again
+	bulkAdoptionListingPeriod = 5 * time.Minute
+)
+
+// adoptionProgressChangedPredicate only lets through the creation and deletion
+// of AdoptedResources and the updates that change whether an AdoptedResource
+// has been adopted or has failed, which are the only events that affect the
+// progress counts of a BulkAdoption
+var adoptionProgressChangedPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldRes, ok := e.ObjectOld.(*ackv1alpha1.AdoptedResource)
+		if !ok {
+			return true
+		}
+		newRes, ok := e.ObjectNew.(*ackv1alpha1.AdoptedResource)
+		if !ok {
+			return true
+		}
+		oldAdopted, oldFailed := getAdoptionProgress(oldRes)
+		newAdopted, newFailed := getAdoptionProgress(newRes)
+		return oldAdopted != newAdopted || oldFailed != newFailed
+	},
+}
+
+// bulkAdoptionListing is the list of AWS resources matched by the tag filters
+// of a given generation of a BulkAdoption
+type bulkAdoptionListing struct {
+	generation  int64
+	listedAt    time.Time
+	identifiers []*ackv1alpha1.AWSIdentifiers
+}
+
+// bulkAdoptionReconciler is responsible for reconciling BulkAdoptions that
+// target any of the Kubernetes custom resources (CRs) supported by a given AWS
+// service. For every AWS resource matched by a BulkAdoption, it creates an
+// AdoptedResource that is in turn reconciled by the adoptionReconciler.
+// It implements the upstream controller-runtime `Reconciler` interface.
+type bulkAdoptionReconciler struct {
+	adoptionReconciler
+	// listingsMu protects listings
+	listingsMu sync.Mutex
+	// listings stores the AWS resources last listed for every BulkAdoption
+	// using tag filters, so that the AWS service API is not called again
+	// every time the progress of the BulkAdoption changes
+	listings map[types.NamespacedName]*bulkAdoptionListing
+}
+
+// BindControllerManager sets up the bulkAdoptionReconciler with an instance
+// of an upstream controller-runtime.Manager
+func (r *bulkAdoptionReconciler) BindControllerManager(mgr ctrlrt.Manager) error {
+	r.kc = mgr.GetClient()
+	r.apiReader = mgr.GetAPIReader()
+	return ctrlrt.NewControllerManagedBy(
+		mgr,
+	).For(
+		&ackv1alpha1.BulkAdoption{},
+		builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+	).Owns(
+		// Any change to the adoption status of the created adopted resources
+		// updates the progress counts of the bulk adoption
+		&ackv1alpha1.AdoptedResource{},
+		builder.WithPredicates(adoptionProgressChangedPredicate),
+	).Complete(r)
+}
+
+// Reconcile implements `controller-runtime.Reconciler` and handles reconciling
+// a CR CRUD request
+func (r *bulkAdoptionReconciler) Reconcile(ctx context.Context, req ctrlrt.Request) (ctrlrt.Result, error) {
+	return r.handleReconcileError(r.reconcile(ctx, req))
+}
+
+func (r *bulkAdoptionReconciler) reconcile(ctx context.Context, req ctrlrt.Request) error {
+	res, err := r.getBulkAdoption(ctx, req)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// resource wasn't found. just ignore these.
+			r.forgetListing(req.NamespacedName)
+			return nil
+		}
+		return err
+	}
+
+	if res.DeletionTimestamp != nil {
+		// The created adopted resources are owned by the bulk adoption and
+		// are garbage collected by Kubernetes
+		r.forgetListing(req.NamespacedName)
+		return nil
+	}
+
+	gk := schema.GroupKind{
+		Group: res.Spec.Kubernetes.Group,
+		Kind:  res.Spec.Kubernetes.Kind,
+	}
+	if !r.isServiceGroup(gk.Group) {
+		ackrtlog.DebugBulkAdoption(r.log, res, "target resource API group is not of this service. no-op")
+		return nil
+	}
+
+	rmf, ok := (r.sc.GetResourceManagerFactories())[gk.String()]
+	if !ok {
+		return r.onTerminal(ctx, res, fmt.Errorf(
+			"%w: unknown kind %s", ackerr.ResourceManagerFactoryNotFound, gk.String(),
+		))
+	}
+
+	if !rmf.IsAdoptable() {
+		return r.onTerminal(ctx, res, fmt.Errorf(
+			"%w: kind %s", ackerr.NotAdoptable, gk.String(),
+		))
+	}
+
+	ackrtlog.InfoBulkAdoption(r.log, res, "starting bulk adoption reconciliation")
+
+	rm, err := r.managerFor(rmf, res, nil)
+	if err != nil {
+		return err
+	}
+
+	return r.Sync(ctx, rmf.ResourceDescriptor(), rm, res)
+}
+
+// Sync ensures that an AdoptedResource exists for every AWS resource matched by
+// the supplied BulkAdoption and updates the progress counts in the
+// BulkAdoption's Status
+func (r *bulkAdoptionReconciler) Sync(
+	ctx context.Context,
+	targetDescriptor acktypes.AWSResourceDescriptor,
+	rm acktypes.AWSResourceManager,
+	desired *ackv1alpha1.BulkAdoption,
+) error {
+	filter := desired.Spec.AWS
+	identifiers := filter.Identifiers
+	if len(filter.TagFilters) > 0 {
+		if len(filter.Identifiers) > 0 {
+			return r.onTerminal(ctx, desired, fmt.Errorf(
+				"identifiers and tag filters cannot be used together",
+			))
+		}
+		lister, ok := rm.(acktypes.AWSResourceLister)
+		if !ok {
+			return r.onTerminal(ctx, desired, fmt.Errorf(
+				"%w: listing resources of kind %s",
+				ackerr.NotImplemented, desired.Spec.Kubernetes.Kind,
+			))
+		}
+		listed, err := r.listIdentifiers(ctx, lister, desired)
+		if err != nil {
+			return r.onError(ctx, desired, err)
+		}
+		identifiers = listed
+	}
+
+	existing, err := r.getOwnedAdoptedResources(ctx, desired)
+	if err != nil {
+		return r.onError(ctx, desired, err)
+	}
+
+	matched := make([]*ackv1alpha1.AdoptedResource, 0, len(identifiers))
+	conflicts := 0
+	names := map[string]bool{}
+	for index, ids := range identifiers {
+		name := getBulkAdoptionTargetName(desired, ids, index)
+		if name == "" || names[name] {
+			// Two AWS resources cannot be adopted into custom resources of
+			// the same name
+			conflicts++
+			continue
+		}
+		names[name] = true
+
+		if adopted, ok := existing[name]; ok {
+			matched = append(matched, adopted)
+			continue
+		}
+		adopted := newBulkAdoptedResource(desired, name, ids)
+		if err := r.kc.Create(ctx, adopted); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				// An adopted resource of the same name that is not owned by
+				// this bulk adoption already exists
+				conflicts++
+				continue
+			}
+			return r.onError(ctx, desired, err)
+		}
+		matched = append(matched, adopted)
+	}
+
+	return r.onProgress(ctx, desired, len(identifiers), conflicts, matched)
+}
+
+// listIdentifiers returns the identifiers of the AWS resources matched by the
+// tag filters of the supplied BulkAdoption, sorted by ARN and then by name or
+// identifier so that their positions do not depend on the order in which the
+// AWS service API returns them. The AWS resources are only listed again once
+// the BulkAdoption is modified or bulkAdoptionListingPeriod has elapsed.
+func (r *bulkAdoptionReconciler) listIdentifiers(
+	ctx context.Context,
+	lister acktypes.AWSResourceLister,
+	res *ackv1alpha1.BulkAdoption,
+) ([]*ackv1alpha1.AWSIdentifiers, error) {
+	key := types.NamespacedName{Namespace: res.Namespace, Name: res.Name}
+	r.listingsMu.Lock()
+	listing, ok := r.listings[key]
+	r.listingsMu.Unlock()
+	if ok && listing.generation == res.Generation &&
+		time.Since(listing.listedAt) < bulkAdoptionListingPeriod {
+		return listing.identifiers, nil
+	}
+
+	identifiers, err := lister.ListIdentifiers(ctx, res.Spec.AWS.TagFilters)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(identifiers, func(i, j int) bool {
+		iARN, jARN := getARN(identifiers[i]), getARN(identifiers[j])
+		if iARN != jARN {
+			return iARN < jARN
+		}
+		return identifiers[i].NameOrID < identifiers[j].NameOrID
+	})
+
+	r.listingsMu.Lock()
+	defer r.listingsMu.Unlock()
+	if r.listings == nil {
+		r.listings = map[types.NamespacedName]*bulkAdoptionListing{}
+	}
+	r.listings[key] = &bulkAdoptionListing{
+		generation:  res.Generation,
+		listedAt:    time.Now(),
+		identifiers: identifiers,
+	}
+	return identifiers, nil
+}
+
+// forgetListing removes the AWS resources listed for the BulkAdoption with the
+// supplied namespaced name
+func (r *bulkAdoptionReconciler) forgetListing(key types.NamespacedName) {
+	r.listingsMu.Lock()
+	defer r.listingsMu.Unlock()
+	delete(r.listings, key)
+}
+
+// getBulkAdoption returns a BulkAdoption representing the requested
+// Kubernetes namespaced object
+func (r *bulkAdoptionReconciler) getBulkAdoption(
+	ctx context.Context,
+	req ctrlrt.Request,
+) (*ackv1alpha1.BulkAdoption, error) {
+	ro := &ackv1alpha1.BulkAdoption{}
+	if err := r.apiReader.Get(ctx, req.NamespacedName, ro); err != nil {
+		return nil, err
+	}
+	return ro, nil
+}
+
+// getOwnedAdoptedResources returns the AdoptedResources that were created by
+// the supplied BulkAdoption, keyed by name
+func (r *bulkAdoptionReconciler) getOwnedAdoptedResources(
+	ctx context.Context,
+	res *ackv1alpha1.BulkAdoption,
+) (map[string]*ackv1alpha1.AdoptedResource, error) {
+	list := &ackv1alpha1.AdoptedResourceList{}
+	if err := r.kc.List(ctx, list, client.InNamespace(res.Namespace)); err != nil {
+		return nil, err
+	}
+	owned := map[string]*ackv1alpha1.AdoptedResource{}
+	for i := range list.Items {
+		adopted := &list.Items[i]
+		if metav1.IsControlledBy(adopted, res) {
+			owned[adopted.Name] = adopted
+		}
+	}
+	return owned, nil
+}
+
+// onError will patch the bulk adoption with the given error and return the
+// same error back
+func (r *bulkAdoptionReconciler) onError(
+	ctx context.Context,
+	res *ackv1alpha1.BulkAdoption,
+	err error,
+) error {
+	base := res.DeepCopy()
+	setBulkAdoptionCondition(
+		res, ackv1alpha1.ConditionTypeAdopted, corev1.ConditionFalse, err.Error(),
+	)
+	_ = r.patchStatus(ctx, res, base)
+	return err
+}
+
+// onTerminal will patch the bulk adoption with a terminal condition describing
+// the given error and return ackerr.Terminal, so that the bulk adoption is not
+// retried until it is modified
+func (r *bulkAdoptionReconciler) onTerminal(
+	ctx context.Context,
+	res *ackv1alpha1.BulkAdoption,
+	err error,
+) error {
+	base := res.DeepCopy()
+	setBulkAdoptionCondition(
+		res, ackv1alpha1.ConditionTypeAdopted, corev1.ConditionFalse, err.Error(),
+	)
+	setBulkAdoptionCondition(
+		res, ackv1alpha1.ConditionTypeTerminal, corev1.ConditionTrue, err.Error(),
+	)
+	if patchErr := r.patchStatus(ctx, res, base); patchErr != nil {
+		return patchErr
+	}
+	ackrtlog.InfoBulkAdoption(r.log, res, "bulk adoption failed", "error", err)
+	return ackerr.Terminal
+}
+
+// onProgress will patch the bulk adoption with the progress counts computed
+// from the supplied adopted resources. The bulk adoption is only considered
+// adopted once every matched AWS resource has been adopted, and never when no
+// AWS resource is matched.
+func (r *bulkAdoptionReconciler) onProgress(
+	ctx context.Context,
+	res *ackv1alpha1.BulkAdoption,
+	matched int,
+	conflicts int,
+	adoptedResources []*ackv1alpha1.AdoptedResource,
+) error {
+	base := res.DeepCopy()
+	res.Status.Matched = int32(matched)
+	res.Status.Created = int32(len(adoptedResources))
+	res.Status.Adopted = 0
+	res.Status.Failed = int32(conflicts)
+	for _, adopted := range adoptedResources {
+		isAdopted, isFailed := getAdoptionProgress(adopted)
+		if isAdopted {
+			res.Status.Adopted++
+		}
+		if isFailed {
+			res.Status.Failed++
+		}
+	}
+
+	status := corev1.ConditionFalse
+	message := "no AWS resources matched"
+	if res.Status.Matched > 0 {
+		if res.Status.Adopted == res.Status.Matched {
+			status = corev1.ConditionTrue
+		}
+		message = fmt.Sprintf(
+			"%d of %d matched AWS resources adopted, %d failed",
+			res.Status.Adopted, res.Status.Matched, res.Status.Failed,
+		)
+	}
+	setBulkAdoptionCondition(
+		res, ackv1alpha1.ConditionTypeAdopted, status, message,
+	)
+	res.Status.Conditions = removeAdoptionCondition(
+		res.Status.Conditions, ackv1alpha1.ConditionTypeTerminal,
+	)
+	return r.patchStatus(ctx, res, base)
+}
+
+// patchStatus patches the Status for BulkAdoption into k8s. The bulk adoption
+// 'res' also gets updated with the content returned from apiserver.
+func (r *bulkAdoptionReconciler) patchStatus(
+	ctx context.Context,
+	res *ackv1alpha1.BulkAdoption,
+	base *ackv1alpha1.BulkAdoption,
+) error {
+	return r.kc.Status().Patch(
+		ctx,
+		res,
+		client.MergeFrom(base),
+	)
+}
+
+// getAdoptionProgress returns whether the supplied AdoptedResource has been
+// adopted and whether its adoption has failed
+func getAdoptionProgress(
+	res *ackv1alpha1.AdoptedResource,
+) (adopted bool, failed bool) {
+	for _, cond := range res.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case ackv1alpha1.ConditionTypeAdopted:
+			adopted = true
+		case ackv1alpha1.ConditionTypeTerminal:
+			failed = true
+		}
+	}
+	return adopted, failed
+}
+
+// setBulkAdoptionCondition sets the bulk adoption's condition of the supplied
+// type to the supplied status and message
+func setBulkAdoptionCondition(
+	res *ackv1alpha1.BulkAdoption,
+	condType ackv1alpha1.ConditionType,
+	status corev1.ConditionStatus,
+	message string,
+) {
+	res.Status.Conditions = setAdoptionCondition(
+		res.Status.Conditions, res.Generation, condType, status, &message,
+	)
+}
+
+// newBulkAdoptedResource returns an AdoptedResource, owned by the supplied
+// BulkAdoption, that adopts the AWS resource with the supplied identifiers into
+// a custom resource of the supplied name
+func newBulkAdoptedResource(
+	owner *ackv1alpha1.BulkAdoption,
+	name string,
+	ids *ackv1alpha1.AWSIdentifiers,
+) *ackv1alpha1.AdoptedResource {
+	target := owner.Spec.Kubernetes.DeepCopy()
+	if target.Metadata == nil {
+		target.Metadata = &ackv1alpha1.PartialObjectMeta{}
+	}
+	target.Metadata.Name = name
+	target.Metadata.GenerateName = ""
+
+	return &ackv1alpha1.AdoptedResource{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: owner.Namespace,
+			Name:      name,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(
+					owner, ackv1alpha1.GroupVersion.WithKind("BulkAdoption"),
+				),
+			},
+		},
+		Spec: ackv1alpha1.AdoptedResourceSpec{
+			Kubernetes:  target,
+			AWS:         ids.DeepCopy(),
+			DriftPolicy: owner.Spec.DriftPolicy,
+		},
+	}
+}
+
+// getBulkAdoptionTargetName renders the name template of the supplied
+// BulkAdoption for the AWS resource with the supplied identifiers and position
+// in the list of matched AWS resources. The returned name is a valid DNS-1123
+// subdomain, or an empty string if no valid name could be rendered.
+func getBulkAdoptionTargetName(
+	res *ackv1alpha1.BulkAdoption,
+	ids *ackv1alpha1.AWSIdentifiers,
+	index int,
+) string {
+	tmpl := defaultBulkAdoptionNameTemplate
+	if res.Spec.NameTemplate != nil && *res.Spec.NameTemplate != "" {
+		tmpl = *res.Spec.NameTemplate
+	}
+	name := strings.NewReplacer(
+		"%NAME_OR_ID%", getNameOrID(ids),
+		"%INDEX%", strconv.Itoa(index),
+		"%BULK_ADOPTION_NAME%", res.Name,
+	).Replace(tmpl)
+	return ackutil.ToDNS1123Subdomain(name)
+}
+
+// getARN returns the ARN of the AWS resource with the supplied identifiers, or
+// an empty string if its ARN is not known
+func getARN(ids *ackv1alpha1.AWSIdentifiers) string {
+	if ids.ARN == nil {
+		return ""
+	}
+	return string(*ids.ARN)
+}
+
+// getNameOrID returns the name or identifier of the AWS resource with the
+// supplied identifiers. If no name or identifier is known, the last segment of
+// the resource portion of its ARN is returned instead.
+func getNameOrID(ids *ackv1alpha1.AWSIdentifiers) string {
+	if ids.NameOrID != "" || ids.ARN == nil {
+		return ids.NameOrID
+	}
+	return ackutil.NameFromARN(string(*ids.ARN))
+}
+
+// NewBulkAdoptionReconciler returns a new bulkAdoptionReconciler object
+func NewBulkAdoptionReconciler(
+	sc acktypes.ServiceController,
+	log logr.Logger,
+	cfg ackcfg.Config,
+	metrics *ackmetrics.Metrics,
+	cache ackrtcache.Caches,
+) acktypes.Reconciler {
+	return NewBulkAdoptionReconcilerWithClient(sc, log, cfg, metrics, cache, nil, nil)
+}
+
+// NewBulkAdoptionReconcilerWithClient returns a new bulkAdoptionReconciler
+// object with specified k8s client and Reader. Currently this function is used
+// for testing purpose only because "bulkAdoptionReconciler" struct is not
+// available outside 'runtime' package for dependency injection.
+func NewBulkAdoptionReconcilerWithClient(
+	sc acktypes.ServiceController,
+	log logr.Logger,
+	cfg ackcfg.Config,
+	metrics *ackmetrics.Metrics,
+	cache ackrtcache.Caches,
+	kc client.Client,
+	apiReader client.Reader,
+) acktypes.BulkAdoptionReconciler {
+	return &bulkAdoptionReconciler{
+		adoptionReconciler: adoptionReconciler{
+			reconciler: reconciler{
+				sc:        sc,
+				log:       log.WithName("bulk-adoption-reconciler"),
+				cfg:       cfg,
+				metrics:   metrics,
+				cache:     cache,
+				kc:        kc,
+				apiReader: apiReader,
+			},
+		},
+	}
+}
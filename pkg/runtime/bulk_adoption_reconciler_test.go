@@ -0,0 +1,448 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	ctrlrtzap "sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ctrlrtclientmock "github.com/aws-controllers-k8s/runtime/mocks/controller-runtime/pkg/client"
+	ackmocks "github.com/aws-controllers-k8s/runtime/mocks/pkg/types"
+	ackcfg "github.com/aws-controllers-k8s/runtime/pkg/config"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackmetrics "github.com/aws-controllers-k8s/runtime/pkg/metrics"
+	ackrt "github.com/aws-controllers-k8s/runtime/pkg/runtime"
+	ackrtcache "github.com/aws-controllers-k8s/runtime/pkg/runtime/cache"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+)
+
+// listingManager is an AWSResourceManager that is able to list resources
+type listingManager struct {
+	*ackmocks.AWSResourceManager
+	*ackmocks.AWSResourceLister
+}
+
+func mockBulkAdoptionReconciler() (acktypes.BulkAdoptionReconciler, *ctrlrtclientmock.Client, *ctrlrtclientmock.StatusWriter) {
+	zapOptions := ctrlrtzap.Options{
+		Development: true,
+		Level:       zapcore.InfoLevel,
+	}
+	fakeLogger := ctrlrtzap.New(ctrlrtzap.UseFlagOptions(&zapOptions))
+	cfg := ackcfg.Config{}
+	metrics := ackmetrics.NewMetrics("bookstore")
+
+	sc := &ackmocks.ServiceController{}
+	kc := &ctrlrtclientmock.Client{}
+	statusWriter := &ctrlrtclientmock.StatusWriter{}
+	kc.On("Status").Return(statusWriter)
+	statusWriter.On(
+		"Patch", mock.Anything, mock.AnythingOfType("*v1alpha1.BulkAdoption"),
+		mock.AnythingOfType("*client.mergeFromPatch"),
+	).Return(nil)
+	return ackrt.NewBulkAdoptionReconcilerWithClient(
+		sc,
+		fakeLogger,
+		cfg,
+		metrics,
+		ackrtcache.Caches{},
+		kc,
+		&ctrlrtclientmock.Reader{},
+	), kc, statusWriter
+}
+
+func bulkAdoption(aws *ackv1alpha1.BulkAdoptionAWSFilter) *ackv1alpha1.BulkAdoption {
+	return &ackv1alpha1.BulkAdoption{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: Namespace,
+			Name:      "books",
+			UID:       "bulk-adoption-uid",
+		},
+		Spec: ackv1alpha1.BulkAdoptionSpec{
+			Kubernetes: &ackv1alpha1.TargetKubernetesResource{
+				Group: "bookstore.services.k8s.aws",
+				Kind:  "Book",
+				Metadata: &ackv1alpha1.PartialObjectMeta{
+					Labels: map[string]string{"team": "library"},
+				},
+			},
+			AWS: aws,
+		},
+	}
+}
+
+// ownedAdoptedResource returns an AdoptedResource owned by the supplied
+// BulkAdoption with a condition of the supplied type set to True
+func ownedAdoptedResource(
+	owner *ackv1alpha1.BulkAdoption,
+	name string,
+	condType ackv1alpha1.ConditionType,
+) ackv1alpha1.AdoptedResource {
+	return ackv1alpha1.AdoptedResource{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: Namespace,
+			Name:      name,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(
+					owner, ackv1alpha1.GroupVersion.WithKind("BulkAdoption"),
+				),
+			},
+		},
+		Status: ackv1alpha1.AdoptedResourceStatus{
+			Conditions: []*ackv1alpha1.Condition{
+				{
+					Type:   condType,
+					Status: corev1.ConditionTrue,
+				},
+			},
+		},
+	}
+}
+
+func setupMockList(kc *ctrlrtclientmock.Client, items ...ackv1alpha1.AdoptedResource) {
+	kc.On(
+		"List", mock.Anything, mock.AnythingOfType("*v1alpha1.AdoptedResourceList"),
+		mock.Anything,
+	).Run(func(args mock.Arguments) {
+		list := args.Get(1).(*ackv1alpha1.AdoptedResourceList)
+		list.Items = items
+	}).Return(nil)
+}
+
+// createdAdoptedResources returns the AdoptedResources passed to the mocked
+// k8s client's Create method
+func createdAdoptedResources(kc *ctrlrtclientmock.Client) []*ackv1alpha1.AdoptedResource {
+	created := []*ackv1alpha1.AdoptedResource{}
+	for _, call := range kc.Calls {
+		if call.Method == "Create" {
+			created = append(created, call.Arguments.Get(1).(*ackv1alpha1.AdoptedResource))
+		}
+	}
+	return created
+}
+
+func adoptedConditionOf(res *ackv1alpha1.BulkAdoption) *ackv1alpha1.Condition {
+	for _, cond := range res.Status.Conditions {
+		if cond.Type == ackv1alpha1.ConditionTypeAdopted {
+			return cond
+		}
+	}
+	return nil
+}
+
+func TestBulkAdoptionSync_Identifiers(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	r, kc, _ := mockBulkAdoptionReconciler()
+	arn := ackv1alpha1.AWSResourceName("arn:aws:bookstore:us-west-2:012345678912:book/Second_Book")
+	desired := bulkAdoption(&ackv1alpha1.BulkAdoptionAWSFilter{
+		Identifiers: []*ackv1alpha1.AWSIdentifiers{
+			{NameOrID: "First_Book"},
+			{ARN: &arn},
+		},
+	})
+	setupMockList(kc)
+	kc.On("Create", ctx, mock.AnythingOfType("*v1alpha1.AdoptedResource")).Return(nil)
+
+	err := r.Sync(ctx, &ackmocks.AWSResourceDescriptor{}, &ackmocks.AWSResourceManager{}, desired)
+	require.Nil(err)
+
+	created := createdAdoptedResources(kc)
+	require.Len(created, 2)
+	require.Equal("first-book", created[0].Name)
+	require.Equal("second-book", created[1].Name)
+	for _, adopted := range created {
+		require.Equal(Namespace, adopted.Namespace)
+		require.True(metav1.IsControlledBy(adopted, desired))
+		require.Equal("Book", adopted.Spec.Kubernetes.Kind)
+		require.Equal(adopted.Name, adopted.Spec.Kubernetes.Metadata.Name)
+		require.Equal("library", adopted.Spec.Kubernetes.Metadata.Labels["team"])
+	}
+	require.Equal(&arn, created[1].Spec.AWS.ARN)
+	// The template of the bulk adoption is left untouched
+	require.Equal("", desired.Spec.Kubernetes.Metadata.Name)
+
+	require.Equal(int32(2), desired.Status.Matched)
+	require.Equal(int32(2), desired.Status.Created)
+	require.Equal(int32(0), desired.Status.Adopted)
+	require.Equal(int32(0), desired.Status.Failed)
+	cond := adoptedConditionOf(desired)
+	require.NotNil(cond)
+	require.Equal(corev1.ConditionFalse, cond.Status)
+}
+
+func TestBulkAdoptionSync_ProgressCounts(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	r, kc, _ := mockBulkAdoptionReconciler()
+	desired := bulkAdoption(&ackv1alpha1.BulkAdoptionAWSFilter{
+		Identifiers: []*ackv1alpha1.AWSIdentifiers{
+			{NameOrID: "first"},
+			{NameOrID: "second"},
+		},
+	})
+	setupMockList(
+		kc,
+		ownedAdoptedResource(desired, "first", ackv1alpha1.ConditionTypeAdopted),
+		ownedAdoptedResource(desired, "second", ackv1alpha1.ConditionTypeTerminal),
+	)
+
+	err := r.Sync(ctx, &ackmocks.AWSResourceDescriptor{}, &ackmocks.AWSResourceManager{}, desired)
+	require.Nil(err)
+
+	kc.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	require.Equal(int32(2), desired.Status.Matched)
+	require.Equal(int32(2), desired.Status.Created)
+	require.Equal(int32(1), desired.Status.Adopted)
+	require.Equal(int32(1), desired.Status.Failed)
+}
+
+func TestBulkAdoptionSync_AllAdopted(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	r, kc, _ := mockBulkAdoptionReconciler()
+	desired := bulkAdoption(&ackv1alpha1.BulkAdoptionAWSFilter{
+		Identifiers: []*ackv1alpha1.AWSIdentifiers{
+			{NameOrID: "first"},
+		},
+	})
+	setupMockList(
+		kc,
+		ownedAdoptedResource(desired, "first", ackv1alpha1.ConditionTypeAdopted),
+	)
+
+	err := r.Sync(ctx, &ackmocks.AWSResourceDescriptor{}, &ackmocks.AWSResourceManager{}, desired)
+	require.Nil(err)
+
+	require.Equal(int32(1), desired.Status.Adopted)
+	cond := adoptedConditionOf(desired)
+	require.NotNil(cond)
+	require.Equal(corev1.ConditionTrue, cond.Status)
+}
+
+func TestBulkAdoptionSync_NameConflicts(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	r, kc, _ := mockBulkAdoptionReconciler()
+	desired := bulkAdoption(&ackv1alpha1.BulkAdoptionAWSFilter{
+		Identifiers: []*ackv1alpha1.AWSIdentifiers{
+			{NameOrID: "book"},
+			{NameOrID: "Book"},
+			{NameOrID: "taken"},
+		},
+	})
+	setupMockList(kc)
+	kc.On("Create", ctx, mock.MatchedBy(func(res *ackv1alpha1.AdoptedResource) bool {
+		return res.Name == "book"
+	})).Return(nil)
+	kc.On("Create", ctx, mock.MatchedBy(func(res *ackv1alpha1.AdoptedResource) bool {
+		return res.Name == "taken"
+	})).Return(k8serrors.NewAlreadyExists(schema.GroupResource{}, "taken"))
+
+	err := r.Sync(ctx, &ackmocks.AWSResourceDescriptor{}, &ackmocks.AWSResourceManager{}, desired)
+	require.Nil(err)
+
+	require.Equal(int32(3), desired.Status.Matched)
+	require.Equal(int32(1), desired.Status.Created)
+	require.Equal(int32(2), desired.Status.Failed)
+}
+
+func TestBulkAdoptionSync_TagFilters(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	r, kc, _ := mockBulkAdoptionReconciler()
+	tagFilters := []*ackv1alpha1.AWSTagFilter{
+		{Key: "aws:cloudformation:stack-name", Values: []string{"library"}},
+	}
+	desired := bulkAdoption(&ackv1alpha1.BulkAdoptionAWSFilter{
+		TagFilters: tagFilters,
+	})
+	nameTemplate := "%BULK_ADOPTION_NAME%-%INDEX%"
+	desired.Spec.NameTemplate = &nameTemplate
+	rm := listingManager{
+		AWSResourceManager: &ackmocks.AWSResourceManager{},
+		AWSResourceLister:  &ackmocks.AWSResourceLister{},
+	}
+	rm.AWSResourceLister.On("ListIdentifiers", ctx, tagFilters).Return(
+		[]*ackv1alpha1.AWSIdentifiers{
+			{NameOrID: "first"},
+			{NameOrID: "second"},
+		}, nil,
+	)
+	setupMockList(kc)
+	kc.On("Create", ctx, mock.AnythingOfType("*v1alpha1.AdoptedResource")).Return(nil)
+
+	err := r.Sync(ctx, &ackmocks.AWSResourceDescriptor{}, rm, desired)
+	require.Nil(err)
+
+	created := createdAdoptedResources(kc)
+	require.Len(created, 2)
+	require.Equal("books-0", created[0].Name)
+	require.Equal("first", created[0].Spec.AWS.NameOrID)
+	require.Equal("books-1", created[1].Name)
+	require.Equal("second", created[1].Spec.AWS.NameOrID)
+	require.Equal(int32(2), desired.Status.Matched)
+}
+
+func TestBulkAdoptionSync_TagFiltersNotSupported(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	r, kc, statusWriter := mockBulkAdoptionReconciler()
+	desired := bulkAdoption(&ackv1alpha1.BulkAdoptionAWSFilter{
+		TagFilters: []*ackv1alpha1.AWSTagFilter{{Key: "team"}},
+	})
+
+	err := r.Sync(ctx, &ackmocks.AWSResourceDescriptor{}, &ackmocks.AWSResourceManager{}, desired)
+	require.Equal(ackerr.Terminal, err)
+
+	kc.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	statusWriter.AssertCalled(t, "Patch", ctx, desired, mock.AnythingOfType("*client.mergeFromPatch"))
+	require.Len(desired.Status.Conditions, 2)
+	require.Equal(ackv1alpha1.ConditionTypeTerminal, desired.Status.Conditions[1].Type)
+	require.Equal(corev1.ConditionTrue, desired.Status.Conditions[1].Status)
+	require.Contains(*desired.Status.Conditions[1].Message, ackerr.NotImplemented.Error())
+}
+
+func TestBulkAdoptionSync_NoMatches(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	r, kc, _ := mockBulkAdoptionReconciler()
+	tagFilters := []*ackv1alpha1.AWSTagFilter{{Key: "team"}}
+	desired := bulkAdoption(&ackv1alpha1.BulkAdoptionAWSFilter{
+		TagFilters: tagFilters,
+	})
+	rm := listingManager{
+		AWSResourceManager: &ackmocks.AWSResourceManager{},
+		AWSResourceLister:  &ackmocks.AWSResourceLister{},
+	}
+	rm.AWSResourceLister.On("ListIdentifiers", ctx, tagFilters).Return(
+		[]*ackv1alpha1.AWSIdentifiers{}, nil,
+	)
+	setupMockList(kc)
+
+	err := r.Sync(ctx, &ackmocks.AWSResourceDescriptor{}, rm, desired)
+	require.Nil(err)
+
+	require.Equal(int32(0), desired.Status.Matched)
+	require.Equal(int32(0), desired.Status.Adopted)
+	// Nothing has been adopted when nothing is matched
+	cond := adoptedConditionOf(desired)
+	require.NotNil(cond)
+	require.Equal(corev1.ConditionFalse, cond.Status)
+	require.Equal("no AWS resources matched", *cond.Message)
+}
+
+func TestBulkAdoptionSync_TagFiltersSorted(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	r, kc, _ := mockBulkAdoptionReconciler()
+	tagFilters := []*ackv1alpha1.AWSTagFilter{{Key: "team"}}
+	desired := bulkAdoption(&ackv1alpha1.BulkAdoptionAWSFilter{
+		TagFilters: tagFilters,
+	})
+	nameTemplate := "%BULK_ADOPTION_NAME%-%INDEX%"
+	desired.Spec.NameTemplate = &nameTemplate
+	firstARN := ackv1alpha1.AWSResourceName("arn:aws:bookstore:us-west-2:012345678912:book/a")
+	secondARN := ackv1alpha1.AWSResourceName("arn:aws:bookstore:us-west-2:012345678912:book/b")
+	rm := listingManager{
+		AWSResourceManager: &ackmocks.AWSResourceManager{},
+		AWSResourceLister:  &ackmocks.AWSResourceLister{},
+	}
+	rm.AWSResourceLister.On("ListIdentifiers", ctx, tagFilters).Return(
+		[]*ackv1alpha1.AWSIdentifiers{
+			{ARN: &secondARN},
+			{NameOrID: "second"},
+			{ARN: &firstARN},
+			{NameOrID: "first"},
+		}, nil,
+	)
+	setupMockList(kc)
+	kc.On("Create", ctx, mock.AnythingOfType("*v1alpha1.AdoptedResource")).Return(nil)
+
+	err := r.Sync(ctx, &ackmocks.AWSResourceDescriptor{}, rm, desired)
+	require.Nil(err)
+
+	// The position of the listed AWS resources does not depend on the order
+	// in which they are listed
+	created := createdAdoptedResources(kc)
+	require.Len(created, 4)
+	require.Equal("books-0", created[0].Name)
+	require.Equal("first", created[0].Spec.AWS.NameOrID)
+	require.Equal("books-1", created[1].Name)
+	require.Equal("second", created[1].Spec.AWS.NameOrID)
+	require.Equal("books-2", created[2].Name)
+	require.Equal(&firstARN, created[2].Spec.AWS.ARN)
+	require.Equal("books-3", created[3].Name)
+	require.Equal(&secondARN, created[3].Spec.AWS.ARN)
+}
+
+func TestBulkAdoptionSync_TagFiltersListedOnce(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	r, kc, _ := mockBulkAdoptionReconciler()
+	tagFilters := []*ackv1alpha1.AWSTagFilter{{Key: "team"}}
+	desired := bulkAdoption(&ackv1alpha1.BulkAdoptionAWSFilter{
+		TagFilters: tagFilters,
+	})
+	desired.Generation = 1
+	rm := listingManager{
+		AWSResourceManager: &ackmocks.AWSResourceManager{},
+		AWSResourceLister:  &ackmocks.AWSResourceLister{},
+	}
+	rm.AWSResourceLister.On("ListIdentifiers", ctx, tagFilters).Return(
+		[]*ackv1alpha1.AWSIdentifiers{{NameOrID: "first"}}, nil,
+	)
+	setupMockList(kc, ownedAdoptedResource(desired, "first", ackv1alpha1.ConditionTypeAdopted))
+
+	// The listed AWS resources are reused while the bulk adoption is not
+	// modified
+	require.Nil(r.Sync(ctx, &ackmocks.AWSResourceDescriptor{}, rm, desired))
+	require.Nil(r.Sync(ctx, &ackmocks.AWSResourceDescriptor{}, rm, desired))
+	rm.AWSResourceLister.AssertNumberOfCalls(t, "ListIdentifiers", 1)
+	require.Equal(int32(1), desired.Status.Matched)
+
+	// The AWS resources are listed again once it is modified
+	desired.Generation = 2
+	require.Nil(r.Sync(ctx, &ackmocks.AWSResourceDescriptor{}, rm, desired))
+	rm.AWSResourceLister.AssertNumberOfCalls(t, "ListIdentifiers", 2)
+}
+
+func TestAdoptionProgressChangedPredicate(t *testing.T) {
+	require := require.New(t)
+	owner := bulkAdoption(nil)
+	pending := ownedAdoptedResource(owner, "first", ackv1alpha1.ConditionTypeTerminal)
+	pending.Status.Conditions[0].Status = corev1.ConditionFalse
+	relabeled := pending.DeepCopy()
+	relabeled.Labels = map[string]string{"team": "library"}
+	adopted := ownedAdoptedResource(owner, "first", ackv1alpha1.ConditionTypeAdopted)
+	failed := ownedAdoptedResource(owner, "first", ackv1alpha1.ConditionTypeTerminal)
+
+	p := ackrt.AdoptionProgressChangedPredicate
+	require.True(p.Create(event.CreateEvent{Object: &pending}))
+	require.True(p.Delete(event.DeleteEvent{Object: &pending}))
+	require.False(p.Update(event.UpdateEvent{ObjectOld: &pending, ObjectNew: relabeled}))
+	require.True(p.Update(event.UpdateEvent{ObjectOld: &pending, ObjectNew: &adopted}))
+	require.True(p.Update(event.UpdateEvent{ObjectOld: &pending, ObjectNew: &failed}))
+	require.False(p.Update(event.UpdateEvent{ObjectOld: &adopted, ObjectNew: adopted.DeepCopy()}))
+}
@@ -14,6 +14,7 @@
 package cache
 
 import (
+	"encoding/json"
 	"sync"
 
 	"github.com/go-logr/logr"
@@ -33,6 +34,10 @@ type namespaceInfo struct {
 	ownerAccountID string
 	// services.k8s.aws/endpoint-url Annotation
 	endpointURL string
+	// services.k8s.aws/default-deletion-protection Annotation
+	defaultDeletionProtection string
+	// services.k8s.aws/default-tags Annotation
+	defaultTags map[string]string
 }
 
 // getDefaultRegion returns the default region value
@@ -59,6 +64,23 @@ func (n *namespaceInfo) getEndpointURL() string {
 	return n.endpointURL
 }
 
+// getDefaultDeletionProtection returns the namespace default deletion
+// protection value
+func (n *namespaceInfo) getDefaultDeletionProtection() string {
+	if n == nil {
+		return ""
+	}
+	return n.defaultDeletionProtection
+}
+
+// getDefaultTags returns the namespace default tags
+func (n *namespaceInfo) getDefaultTags() map[string]string {
+	if n == nil {
+		return nil
+	}
+	return n.defaultTags
+}
+
 // NamespaceCache is responsible of keeping track of namespaces
 // annotations, and caching those related to the ACK controller.
 type NamespaceCache struct {
@@ -149,6 +171,34 @@ func (c *NamespaceCache) GetEndpointURL(namespace string) (string, bool) {
 	return "", false
 }
 
+// GetDefaultDeletionProtection returns the default deletion protection value
+// if it exists
+func (c *NamespaceCache) GetDefaultDeletionProtection(namespace string) (string, bool) {
+	info, ok := c.getNamespaceInfo(namespace)
+	if ok {
+		d := info.getDefaultDeletionProtection()
+		return d, d != ""
+	}
+	return "", false
+}
+
+// GetDefaultTags returns a copy of the default tags if they exist
+func (c *NamespaceCache) GetDefaultTags(namespace string) (map[string]string, bool) {
+	info, ok := c.getNamespaceInfo(namespace)
+	if ok {
+		tags := info.getDefaultTags()
+		if len(tags) == 0 {
+			return nil, false
+		}
+		copied := make(map[string]string, len(tags))
+		for k, v := range tags {
+			copied[k] = v
+		}
+		return copied, true
+	}
+	return nil, false
+}
+
 // getNamespaceInfo reads a namespace cached annotations and
 // return a given namespace default aws region, owner account id and endpoint url.
 // This function is thread safe.
@@ -176,6 +226,20 @@ func (c *NamespaceCache) setNamespaceInfoFromK8sObject(ns *corev1.Namespace) {
 	if ok {
 		nsInfo.endpointURL = EndpointURL
 	}
+	DefaultDeletionProtection, ok := nsa[ackv1alpha1.AnnotationDefaultDeletionProtection]
+	if ok {
+		nsInfo.defaultDeletionProtection = DefaultDeletionProtection
+	}
+	DefaultTags, ok := nsa[ackv1alpha1.AnnotationDefaultTags]
+	if ok {
+		if err := json.Unmarshal([]byte(DefaultTags), &nsInfo.defaultTags); err != nil {
+			c.log.Error(
+				err, "ignoring invalid default tags",
+				"name", ns.ObjectMeta.Name,
+			)
+			nsInfo.defaultTags = nil
+		}
+	}
 	c.Lock()
 	defer c.Unlock()
 	c.namespaceInfos[ns.ObjectMeta.Name] = nsInfo
@@ -61,9 +61,11 @@ func TestNamespaceCache(t *testing.T) {
 			ObjectMeta: metav1.ObjectMeta{
 				Name: "production",
 				Annotations: map[string]string{
-					ackv1alpha1.AnnotationDefaultRegion:  "us-west-2",
-					ackv1alpha1.AnnotationOwnerAccountID: "012345678912",
-					ackv1alpha1.AnnotationEndpointURL:    "https://amazon-service.region.amazonaws.com",
+					ackv1alpha1.AnnotationDefaultRegion:             "us-west-2",
+					ackv1alpha1.AnnotationOwnerAccountID:            "012345678912",
+					ackv1alpha1.AnnotationEndpointURL:               "https://amazon-service.region.amazonaws.com",
+					ackv1alpha1.AnnotationDefaultDeletionProtection: "true",
+					ackv1alpha1.AnnotationDefaultTags:               `{"cost-center":"1234"}`,
 				},
 			},
 		},
@@ -85,6 +87,14 @@ func TestNamespaceCache(t *testing.T) {
 	require.True(t, ok)
 	require.Equal(t, "https://amazon-service.region.amazonaws.com", endpointURL)
 
+	deletionProtection, ok := namespaceCache.GetDefaultDeletionProtection("production")
+	require.True(t, ok)
+	require.Equal(t, "true", deletionProtection)
+
+	defaultTags, ok := namespaceCache.GetDefaultTags("production")
+	require.True(t, ok)
+	require.Equal(t, map[string]string{"cost-center": "1234"}, defaultTags)
+
 	// Test update events
 	_, err = k8sClient.CoreV1().Namespaces().Update(
 		context.Background(),
@@ -95,6 +105,7 @@ func TestNamespaceCache(t *testing.T) {
 					ackv1alpha1.AnnotationDefaultRegion:  "us-est-1",
 					ackv1alpha1.AnnotationOwnerAccountID: "21987654321",
 					ackv1alpha1.AnnotationEndpointURL:    "https://amazon-other-service.region.amazonaws.com",
+					ackv1alpha1.AnnotationDefaultTags:    "not json",
 				},
 			},
 		},
@@ -116,6 +127,12 @@ func TestNamespaceCache(t *testing.T) {
 	require.True(t, ok)
 	require.Equal(t, "https://amazon-other-service.region.amazonaws.com", endpointURL)
 
+	_, ok = namespaceCache.GetDefaultDeletionProtection("production")
+	require.False(t, ok)
+
+	_, ok = namespaceCache.GetDefaultTags("production")
+	require.False(t, ok)
+
 	// Test delete events
 	err = k8sClient.CoreV1().Namespaces().Delete(
 		context.Background(),
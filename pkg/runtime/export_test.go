@@ -0,0 +1,46 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"context"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+)
+
+// DeleteResource exposes the deletion of the supplied resource by the
+// supplied reconciler to the tests
+func DeleteResource(
+	r acktypes.AWSResourceReconciler,
+	ctx context.Context,
+	rm acktypes.AWSResourceManager,
+	res acktypes.AWSResource,
+) (acktypes.AWSResource, error) {
+	return r.(*resourceReconciler).deleteResource(ctx, rm, res)
+}
+
+// AdoptionProgressChangedPredicate exposes the predicate filtering the events
+// of the AdoptedResources owned by BulkAdoptions to the tests
+var AdoptionProgressChangedPredicate = adoptionProgressChangedPredicate
+
+// GetOwnerAccountID exposes the AWS account that the supplied reconciler
+// manages the supplied resource in to the tests
+func GetOwnerAccountID(
+	r acktypes.AWSResourceReconciler,
+	ctx context.Context,
+	res acktypes.AWSResource,
+) (ackv1alpha1.AWSAccountID, error) {
+	return r.(*resourceReconciler).getOwnerAccountID(ctx, res)
+}
@@ -211,8 +211,11 @@ func expandAdoptedResourceFields(
 	ns := res.Namespace
 	resName := res.Name
 	generation := res.Generation
-	group := res.Spec.Kubernetes.Group
-	kind := res.Spec.Kubernetes.Kind
+	var group, kind string
+	if res.Spec.Kubernetes != nil {
+		group = res.Spec.Kubernetes.Group
+		kind = res.Spec.Kubernetes.Kind
+	}
 	vals := []interface{}{
 		"target_group", group,
 		"target_kind", kind,
@@ -225,3 +228,63 @@ func expandAdoptedResourceFields(
 	}
 	return vals
 }
+
+// AdaptBulkAdoption returns a logger with log values set for the bulk
+// adoption's target kind, namespace, name, etc
+func AdaptBulkAdoption(
+	log logr.Logger,
+	res *v1alpha1.BulkAdoption,
+	additionalValues ...interface{},
+) logr.Logger {
+	vals := expandBulkAdoptionFields(res, additionalValues...)
+	return log.WithValues(vals...)
+}
+
+// DebugBulkAdoption writes a supplied log message about a bulk adoption that
+// includes a set of standard log values for the target kind, namespace, name,
+// etc
+func DebugBulkAdoption(
+	log logr.Logger,
+	res *v1alpha1.BulkAdoption,
+	msg string,
+	additionalValues ...interface{},
+) {
+	AdaptBulkAdoption(log, res, additionalValues...).V(1).Info(msg)
+}
+
+// InfoBulkAdoption writes a supplied log message about a bulk adoption that
+// includes a set of standard log values for the target kind, namespace, name,
+// etc
+func InfoBulkAdoption(
+	log logr.Logger,
+	res *v1alpha1.BulkAdoption,
+	msg string,
+	additionalValues ...interface{},
+) {
+	AdaptBulkAdoption(log, res, additionalValues...).V(0).Info(msg)
+}
+
+// expandBulkAdoptionFields returns the key/value pairs for a bulk adoption
+// that should be used as structured data in log messages about the bulk
+// adoption
+func expandBulkAdoptionFields(
+	res *v1alpha1.BulkAdoption,
+	additionalValues ...interface{},
+) []interface{} {
+	var group, kind string
+	if res.Spec.Kubernetes != nil {
+		group = res.Spec.Kubernetes.Group
+		kind = res.Spec.Kubernetes.Kind
+	}
+	vals := []interface{}{
+		"target_group", group,
+		"target_kind", kind,
+		"namespace", res.Namespace,
+		"name", res.Name,
+		"generation", res.Generation,
+	}
+	if len(additionalValues) > 0 {
+		vals = append(vals, additionalValues...)
+	}
+	return vals
+}
@@ -15,12 +15,18 @@ package runtime
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrlrt "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -36,9 +42,31 @@ import (
 	"github.com/aws-controllers-k8s/runtime/pkg/requeue"
 	ackrtcache "github.com/aws-controllers-k8s/runtime/pkg/runtime/cache"
 	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	acktags "github.com/aws-controllers-k8s/runtime/pkg/tags"
 	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
 )
 
+const (
+	// dependentResourcesExistReason is the reason of the Recoverable
+	// condition set on resources whose deletion is held by dependents
+	dependentResourcesExistReason = "DependentResourcesExist"
+	// deletionProtectedReason is the reason of the Terminal condition set on
+	// resources whose deletion is refused because of deletion protection
+	deletionProtectedReason = "DeletionProtected"
+	// referencedResourcesField is the name of the field index of the CRs
+	// containing AWSResourceReferenceWrapper fields, on the CRs they
+	// reference
+	referencedResourcesField = ".ack.referencedResources"
+	// immutableFieldsModifiedReason is the reason of the Advisory and Terminal
+	// conditions set on resources whose immutable fields were modified
+	immutableFieldsModifiedReason = "ImmutableFieldsModified"
+	// specAnnotationMaxSize is the maximum size of each of the
+	// last-applied-spec and last-written-spec annotations. The annotations of
+	// a CR are limited to 256KiB in total, and a CR whose annotations exceed
+	// the limit cannot be patched anymore.
+	specAnnotationMaxSize = 64 * 1024
+)
+
 // reconciler describes a generic reconciler within ACK.
 type reconciler struct {
 	sc        acktypes.ServiceController
@@ -81,6 +109,26 @@ func (r *resourceReconciler) BindControllerManager(mgr ctrlrt.Manager) error {
 	r.kc = mgr.GetClient()
 	r.apiReader = mgr.GetAPIReader()
 	rd := r.rmf.ResourceDescriptor()
+	if refd, ok := rd.(acktypes.AWSResourceReferenceDescriptor); ok {
+		// Index the CRs on the CRs they reference, so that the dependents
+		// of a CR being deleted are found without listing all of them
+		err := mgr.GetFieldIndexer().IndexField(
+			context.Background(),
+			rd.EmptyRuntimeObject(),
+			referencedResourcesField,
+			func(obj client.Object) []string {
+				refs := refd.ReferencedResources(rd.ResourceFromRuntimeObject(obj))
+				keys := make([]string, 0, len(refs))
+				for _, ref := range refs {
+					keys = append(keys, referencedResourceKey(ref))
+				}
+				return keys
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
 	return ctrlrt.NewControllerManagedBy(
 		mgr,
 	).For(
@@ -140,7 +188,17 @@ func (r *resourceReconciler) Reconcile(ctx context.Context, req ctrlrt.Request)
 		return ctrlrt.Result{}, err
 	}
 
-	acctID := r.getOwnerAccountID(desired)
+	acctID, err := r.getOwnerAccountID(ctx, desired)
+	if err != nil {
+		if !errors.Is(err, ackerr.OwnerAccountIDNotAllowed) {
+			return ctrlrt.Result{}, err
+		}
+		// The CR cannot be reconciled until its owner account ID is changed
+		latest := desired.DeepCopy()
+		msg := err.Error()
+		ackcondition.SetTerminal(latest, corev1.ConditionTrue, &msg, nil)
+		return r.HandleReconcileError(ctx, desired, latest, ackerr.Terminal)
+	}
 	region := r.getRegion(desired)
 	roleARN := r.getRoleARN(acctID)
 	endpointURL := r.getEndpointURL(desired)
@@ -207,13 +265,28 @@ func (r *resourceReconciler) Sync(
 
 	var latest acktypes.AWSResource // the newly created or mutated resource
 
-	r.resetConditions(ctx, desired)
+	// Work on a copy of the supplied resource so that the caller keeps the
+	// status as it was read from the Kubernetes API, which is used as the base
+	// of the status patch in HandleReconcileError.
+	desired = desired.DeepCopy()
+	prevConditions := r.resetConditions(ctx, desired)
+	var syncedTime *metav1.Time // set once the AWS resource has been read
 	defer func() {
-		r.ensureConditions(ctx, latest, err)
+		r.ensureConditions(ctx, latest, prevConditions, err)
+		if syncedTime != nil && ackcompare.IsNotNil(latest) {
+			ackcondition.SetLastSyncedTime(latest, *syncedTime)
+		}
 	}()
 
-	isAdopted := IsAdopted(desired)
-	rlog.WithValues("is_adopted", isAdopted)
+	adoptionPolicy, err := GetAdoptionPolicy(desired)
+	if err != nil {
+		msg := err.Error()
+		ackcondition.SetTerminal(desired, corev1.ConditionTrue, &msg, nil)
+		latest = desired
+		err = ackerr.Terminal
+		return latest, err
+	}
+	rlog.WithValues("adoption_policy", adoptionPolicy)
 
 	rlog.Enter("rm.ResolveReferences")
 	resolvedRefDesired, err := rm.ResolveReferences(ctx, r.apiReader, desired)
@@ -222,21 +295,31 @@ func (r *resourceReconciler) Sync(
 		return resolvedRefDesired, err
 	}
 	desired = resolvedRefDesired
+	r.ensureTags(desired)
 
 	rlog.Enter("rm.ReadOne")
 	latest, err = rm.ReadOne(ctx, desired)
 	rlog.Exit("rm.ReadOne", err)
+	if err == nil || err == ackerr.NotFound {
+		now := metav1.Now()
+		syncedTime = &now
+	}
 	if err != nil {
 		if err != ackerr.NotFound {
 			return latest, err
 		}
-		if isAdopted {
+		if adoptionPolicy == ackv1alpha1.AdoptionPolicyAdoptOnly {
 			return nil, ackerr.AdoptedResourceNotFound
 		}
 		if latest, err = r.createResource(ctx, rm, desired); err != nil {
 			return latest, err
 		}
 	} else {
+		if !r.rd.IsManaged(latest) && adoptionPolicy != ackv1alpha1.AdoptionPolicyCreateOnly {
+			if err = r.adoptResource(ctx, desired, latest); err != nil {
+				return latest, err
+			}
+		}
 		if latest, err = r.updateResource(ctx, rm, desired, latest); err != nil {
 			return latest, err
 		}
@@ -258,29 +341,67 @@ func (r *resourceReconciler) Sync(
 	return r.handleRequeues(ctx, latest)
 }
 
+// ensureTags sets the default tags of the service controller and of the CR's
+// namespace on the supplied resource, if the resource is taggable. The tags in
+// the resource's Spec take precedence over the namespace default tags, which
+// take precedence over the controller default tags. The controller default
+// tags with a time-based value keep the value already set on the resource.
+func (r *resourceReconciler) ensureTags(
+	res acktypes.AWSResource,
+) {
+	taggable, ok := res.(acktypes.TaggableAWSResource)
+	if !ok {
+		return
+	}
+	obj := res.RuntimeObject()
+	namespaceTags, _ := r.cache.Namespaces.GetDefaultTags(obj.GetNamespace())
+	defaultTags := GetDefaultTags(&r.cfg, obj)
+	keepTimeBasedTags(&r.cfg, defaultTags, taggable.GetTags())
+	tags := acktags.Merge(
+		defaultTags,
+		expandTags(&r.cfg, namespaceTags, obj),
+		taggable.GetTags(),
+	)
+	if len(tags) == 0 {
+		return
+	}
+	taggable.SetTags(tags)
+}
+
 // resetConditions strips the supplied resource of all objects in its
 // Status.Conditions collection. We do this at the start of each reconciliation
 // loop in order to ensure that the objects in the Status.Conditions collection
 // represent the state transitions that occurred in the last reconciliation
 // loop. In other words, Status.Conditions should refer to the latest observed
 // state read.
+//
+// A copy of the stripped conditions is returned so that ensureConditions can
+// preserve the timestamps of the conditions that did not transition.
 func (r *resourceReconciler) resetConditions(
 	ctx context.Context,
 	res acktypes.AWSResource,
-) {
+) []*ackv1alpha1.Condition {
 	var err error
 	rlog := ackrtlog.FromContext(ctx)
 	exit := rlog.Trace("r.resetConditions")
 	defer exit(err)
 
+	prevConditions := []*ackv1alpha1.Condition{}
+	for _, c := range res.Conditions() {
+		prevConditions = append(prevConditions, c.DeepCopy())
+	}
 	ackcondition.Clear(res)
+	return prevConditions
 }
 
 // ensureConditions examines the supplied resource's collection of Condition
-// objects and ensures that an ACK.ResourceSynced condition is present.
+// objects and ensures that an ACK.ResourceSynced condition is present. The
+// timestamps of the conditions whose status did not change since the supplied
+// previous conditions are preserved.
 func (r *resourceReconciler) ensureConditions(
 	ctx context.Context,
 	res acktypes.AWSResource,
+	prevConditions []*ackv1alpha1.Condition,
 	reconcileErr error,
 ) {
 	if ackcompare.IsNil(res) {
@@ -311,6 +432,7 @@ func (r *resourceReconciler) ensureConditions(
 		}
 		ackcondition.SetSynced(res, condStatus, nil, nil)
 	}
+	ackcondition.PreserveTimestamps(res, prevConditions)
 }
 
 // createResource marks the CR as managed by ACK, calls one or more AWS APIs to
@@ -363,6 +485,10 @@ func (r *resourceReconciler) createResource(
 	// Take the status from the latest ReadOne
 	latest.SetStatus(observed)
 
+	if err := setLastAppliedSpec(desired, latest); err != nil {
+		rlog.Info("unable to record the last applied spec", "error", err)
+	}
+
 	// Ensure that we are patching any changes to the annotations/metadata and
 	// the Spec that may have been set by the resource manager's successful
 	// Create call above.
@@ -374,6 +500,35 @@ func (r *resourceReconciler) createResource(
 	return latest, nil
 }
 
+// adoptResource places the pre-existing backend AWS resource described by the
+// supplied latest resource under ACK management. The CR is marked as managed
+// and adopted, and its Metadata is patched back to the Kubernetes API. The
+// desired state of the CR is then applied to the backend AWS resource by
+// updateResource like for any other managed resource.
+func (r *resourceReconciler) adoptResource(
+	ctx context.Context,
+	desired acktypes.AWSResource,
+	latest acktypes.AWSResource,
+) error {
+	var err error
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("r.adoptResource")
+	defer exit(err)
+
+	orig := desired.DeepCopy().RuntimeObject()
+	r.rd.MarkManaged(desired)
+	r.rd.MarkAdopted(desired)
+	err = r.patchResourceMetadataAndSpec(ctx, r.rd.ResourceFromRuntimeObject(orig), desired)
+	if err != nil {
+		return err
+	}
+	// The latest observed state was read before the CR was marked as managed
+	r.rd.MarkManaged(latest)
+	r.rd.MarkAdopted(latest)
+	rlog.Info("adopted existing resource")
+	return nil
+}
+
 // updateResource calls one or more AWS APIs to modify the backend AWS resource
 // and patches the CR's Metadata and Spec back to the Kubernetes API.
 //
@@ -403,26 +558,60 @@ func (r *resourceReconciler) updateResource(
 
 	// Check to see if the latest observed state already matches the
 	// desired state and if not, update the resource
-	delta := r.rd.Delta(desired, latest)
-	if delta.DifferentAt("Spec") {
+	delta := r.driftDelta(ctx, desired, latest)
+	// The diff is rendered relative to the desired CR, so that the fields
+	// holding Secret references are redacted
+	diff := delta.UnifiedDiff(ackcompare.WithDesired(desired.RuntimeObject()))
+	if delta.DifferentAt("Spec") && isObservingAdoptionDrift(desired) {
 		rlog.Info(
-			"desired resource state has changed",
-			"diff", delta.Differences,
+			"not applying desired state of newly adopted resource",
+			"diff", diff,
 		)
-		rlog.Enter("rm.Update")
-		latest, err = rm.Update(ctx, desired, latest, delta)
-		rlog.Exit("rm.Update", err, "latest", latest)
-		if err != nil {
-			return latest, err
+		msg := "Spec differs from the adopted AWS resource. The differences " +
+			"will be applied once the Spec is modified, as the adoption " +
+			"drift policy is " + string(ackv1alpha1.AdoptionDriftPolicyObserve)
+		ackcondition.SetAdvisory(latest, corev1.ConditionTrue, &msg, nil)
+		// The AWS resource does not match the Spec
+		ackcondition.SetSynced(latest, corev1.ConditionFalse, nil, nil)
+	} else if modified, mutable := r.modifiedImmutableFields(ctx, desired, delta); len(modified) > 0 {
+		// Updating the immutable fields would only cause the AWS service API
+		// to reject the modification with a less helpful error
+		rlog.Info(
+			"not updating modified immutable fields",
+			"fields", modified,
+			"diff", diff,
+		)
+		msg := "Immutable fields cannot be modified once the resource is " +
+			"created: " + strings.Join(modified, ", ") + ". Restore their " +
+			"values, or recreate the resource, in order to apply the changes"
+		reason := immutableFieldsModifiedReason
+		if isTerminalImmutableFieldPolicy(desired) {
+			msg += ". No other change is applied until then"
+			ackcondition.SetAdvisory(latest, corev1.ConditionTrue, &msg, &reason)
+			ackcondition.SetTerminal(latest, corev1.ConditionTrue, &msg, &reason)
+			return latest, ackerr.Terminal
 		}
-		// Ensure that we are patching any changes to the annotations/metadata and
-		// the Spec that may have been set by the resource manager's successful
-		// Update call above.
-		err = r.patchResourceMetadataAndSpec(ctx, desired, latest)
-		if err != nil {
+		msg += ". The changes to the other fields are still applied"
+		if mutable.DifferentAt("Spec") {
+			// The AWS resource is updated, and the last applied Spec
+			// recorded, without the modified values of the immutable
+			// fields. They are left as the user set them in the CR's Spec.
+			reset := r.resetImmutableFields(desired, latest)
+			if latest, err = r.applyDelta(ctx, rm, reset, latest, mutable); err != nil {
+				return latest, err
+			}
+		}
+		ackcondition.SetAdvisory(latest, corev1.ConditionTrue, &msg, &reason)
+		// The AWS resource does not match the Spec
+		ackcondition.SetSynced(latest, corev1.ConditionFalse, nil, nil)
+	} else if mutable.DifferentAt("Spec") {
+		rlog.Info(
+			"desired resource state has changed",
+			"diff", diff,
+		)
+		if latest, err = r.applyDelta(ctx, rm, desired, latest, mutable); err != nil {
 			return latest, err
 		}
-		rlog.Info("updated resource")
 	} else {
 		// If there is no delta between desired state and latest state, and
 		// ACK.ResourceSynced condition is not already set, it is
@@ -434,6 +623,313 @@ func (r *resourceReconciler) updateResource(
 	return latest, nil
 }
 
+// applyDelta calls AWSResourceManager.Update() to apply the supplied Delta to
+// the backend AWS resource, records the applied Spec and patches the CR's
+// Metadata and Spec back to the Kubernetes API.
+func (r *resourceReconciler) applyDelta(
+	ctx context.Context,
+	rm acktypes.AWSResourceManager,
+	desired acktypes.AWSResource,
+	latest acktypes.AWSResource,
+	delta *ackcompare.Delta,
+) (acktypes.AWSResource, error) {
+	var err error
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("r.applyDelta")
+	defer exit(err)
+
+	rlog.Enter("rm.Update")
+	latest, err = rm.Update(ctx, desired, latest, delta)
+	rlog.Exit("rm.Update", err, "latest", latest)
+	if err != nil {
+		return latest, err
+	}
+	if err := setLastAppliedSpec(desired, latest); err != nil {
+		rlog.Info("unable to record the last applied spec", "error", err)
+	}
+	// Ensure that we are patching any changes to the annotations/metadata and
+	// the Spec that may have been set by the resource manager's successful
+	// Update call above.
+	err = r.patchResourceMetadataAndSpec(ctx, desired, latest)
+	if err != nil {
+		return latest, err
+	}
+	rlog.Info(
+		"updated resource",
+		"diff", delta.UnifiedDiff(ackcompare.WithDesired(desired.RuntimeObject())),
+	)
+	return latest, nil
+}
+
+// driftDelta returns the Delta between the supplied desired and latest
+// resources, without the differences between equivalent values of the fields
+// having a Comparator in the resource descriptor, and without the differences
+// at the paths listed in the desired resource's ignore-paths annotation.
+//
+// Unless the desired resource's drift policy is strict, only the differences
+// at the fields that were declared in the Spec the user last applied, or that
+// the user modified since the Spec was last written by the service
+// controller, are kept. The fields the service controller copied from the AWS
+// resource to the Spec, but the user never set, may thus be modified
+// out-of-band without being reverted.
+func (r *resourceReconciler) driftDelta(
+	ctx context.Context,
+	desired acktypes.AWSResource,
+	latest acktypes.AWSResource,
+) *ackcompare.Delta {
+	delta := r.rd.Delta(desired, latest)
+	if cd, ok := r.rd.(acktypes.AWSResourceComparatorDescriptor); ok {
+		delta = cd.Comparators().Filter(delta)
+	}
+	delta = delta.Without(getIgnoredPaths(desired)...)
+	if isStrictDriftPolicy(desired) {
+		return delta
+	}
+	if lastApplied, changes := r.getSpecChanges(ctx, desired); lastApplied != nil {
+		delta = delta.DeclaredOrChanged(lastApplied, changes)
+	}
+	return delta
+}
+
+// getSpecChanges returns an object holding the Spec the user declared when
+// the Spec of the supplied desired resource was last applied, and the
+// changes the user made to the Spec since it was last written by the service
+// controller. Both are nil if the Spec was never applied, or was too large to
+// be recorded.
+func (r *resourceReconciler) getSpecChanges(
+	ctx context.Context,
+	desired acktypes.AWSResource,
+) (client.Object, *ackcompare.Delta) {
+	lastApplied := r.getAnnotatedSpec(ctx, desired, ackv1alpha1.AnnotationLastAppliedSpec)
+	if lastApplied == nil {
+		return nil, nil
+	}
+	// The CRs whose Spec was last applied before the last written Spec was
+	// recorded only have the last applied Spec to tell the user's changes
+	lastWritten := r.getAnnotatedSpec(ctx, desired, ackv1alpha1.AnnotationLastWrittenSpec)
+	if lastWritten == nil {
+		lastWritten = lastApplied
+	}
+	return lastApplied, specChanges(desired.RuntimeObject(), lastWritten)
+}
+
+// specChanges returns the differences between the Specs of the supplied
+// objects
+func specChanges(obj client.Object, other client.Object) *ackcompare.Delta {
+	spec := ackcompare.NewPath("Spec")
+	return ackcompare.DeepDelta(obj, other).Filter(func(diff *ackcompare.Difference) bool {
+		return diff.Path.HasPrefix(spec)
+	})
+}
+
+// modifiedImmutableFields returns the paths of the immutable fields declared
+// by the resource descriptor that the user modified in the Spec of the
+// supplied desired resource, along with the supplied Delta stripped of the
+// differences at all the immutable fields, which cannot be applied.
+//
+// Only the differences at the immutable fields that the user declared in the
+// Spec last applied, or modified since the Spec was last written by the
+// service controller, are reported, whatever the drift policy. The other
+// ones, e.g. at the fields the service controller copied from an AWS resource
+// that was since modified out-of-band, are stripped without being reported.
+func (r *resourceReconciler) modifiedImmutableFields(
+	ctx context.Context,
+	desired acktypes.AWSResource,
+	delta *ackcompare.Delta,
+) ([]string, *ackcompare.Delta) {
+	ifd, ok := r.rd.(acktypes.AWSResourceImmutableFieldsDescriptor)
+	if !ok {
+		return nil, delta
+	}
+	fields := ifd.ImmutableFields()
+	userDelta := delta
+	if lastApplied, changes := r.getSpecChanges(ctx, desired); lastApplied != nil {
+		userDelta = delta.DeclaredOrChanged(lastApplied, changes)
+	}
+	modified := []string{}
+	for _, field := range fields {
+		path := ackcompare.NewPath(field)
+		for _, diff := range userDelta.Differences {
+			if diff.Path.HasPrefix(path) {
+				modified = append(modified, field)
+				break
+			}
+		}
+	}
+	return modified, delta.Without(fields...)
+}
+
+// resetImmutableFields returns a copy of the supplied desired resource whose
+// immutable fields have the values of the supplied latest resource
+func (r *resourceReconciler) resetImmutableFields(
+	desired acktypes.AWSResource,
+	latest acktypes.AWSResource,
+) acktypes.AWSResource {
+	reset := desired.DeepCopy()
+	if ifd, ok := r.rd.(acktypes.AWSResourceImmutableFieldsDescriptor); ok {
+		ackcompare.CopyPaths(
+			reset.RuntimeObject(), latest.DeepCopy().RuntimeObject(),
+			ifd.ImmutableFields()...,
+		)
+	}
+	return reset
+}
+
+// getAnnotatedSpec returns an object holding the Spec recorded in the
+// supplied annotation of the supplied resource, or nil if the annotation is
+// not set or cannot be decoded.
+func (r *resourceReconciler) getAnnotatedSpec(
+	ctx context.Context,
+	res acktypes.AWSResource,
+	annotation string,
+) client.Object {
+	spec, ok := res.MetaObject().GetAnnotations()[annotation]
+	if !ok {
+		return nil
+	}
+	obj := r.rd.EmptyRuntimeObject()
+	data, err := json.Marshal(map[string]json.RawMessage{
+		"spec": json.RawMessage(spec),
+	})
+	if err == nil {
+		err = json.Unmarshal(data, obj)
+	}
+	if err != nil {
+		rlog := ackrtlog.FromContext(ctx)
+		rlog.Info(
+			"unable to decode the spec recorded in annotation",
+			"annotation", annotation,
+			"error", err,
+		)
+		return nil
+	}
+	return obj
+}
+
+// setLastAppliedSpec records the Spec the user declared in the supplied
+// desired resource in the last-applied-spec annotation of the supplied latest
+// resource, after the desired Spec has been applied to the AWS resource. The
+// declared Spec is made of the fields of the desired Spec that were declared
+// in the Spec last applied, or that were modified since the Spec was last
+// written by the service controller. Without a Spec last applied and written,
+// e.g. when the resource is created, the whole desired Spec is declared.
+//
+// The annotations are removed if the declared Spec exceeds
+// specAnnotationMaxSize, in which case every difference is applied.
+func setLastAppliedSpec(
+	desired acktypes.AWSResource,
+	latest acktypes.AWSResource,
+) error {
+	spec, err := getSpecJSON(desired)
+	if err != nil || spec == nil {
+		return err
+	}
+	annotations := desired.MetaObject().GetAnnotations()
+	lastApplied, appliedOK := annotations[ackv1alpha1.AnnotationLastAppliedSpec]
+	lastWritten, writtenOK := annotations[ackv1alpha1.AnnotationLastWrittenSpec]
+	if appliedOK && writtenOK {
+		var desiredFields, appliedFields, writtenFields map[string]interface{}
+		if err = json.Unmarshal(spec, &desiredFields); err != nil {
+			return err
+		}
+		// The whole desired Spec is declared if the recorded Specs are
+		// invalid
+		if json.Unmarshal([]byte(lastApplied), &appliedFields) == nil &&
+			json.Unmarshal([]byte(lastWritten), &writtenFields) == nil {
+			declared := declaredFields(desiredFields, appliedFields, writtenFields)
+			if spec, err = json.Marshal(declared); err != nil {
+				return err
+			}
+		}
+	}
+	return setSpecAnnotation(latest, ackv1alpha1.AnnotationLastAppliedSpec, spec)
+}
+
+// setLastWrittenSpec records the Spec of the supplied resource in its
+// last-written-spec annotation, before the Spec is written to the Kubernetes
+// API by the service controller. It is a no-op for the resources without a
+// last-applied-spec annotation.
+func setLastWrittenSpec(res acktypes.AWSResource) error {
+	if _, ok := res.MetaObject().GetAnnotations()[ackv1alpha1.AnnotationLastAppliedSpec]; !ok {
+		return nil
+	}
+	spec, err := getSpecJSON(res)
+	if err != nil || spec == nil {
+		return err
+	}
+	return setSpecAnnotation(res, ackv1alpha1.AnnotationLastWrittenSpec, spec)
+}
+
+// getSpecJSON returns the JSON representation of the Spec of the supplied
+// resource, or nil if it has no Spec
+func getSpecJSON(res acktypes.AWSResource) ([]byte, error) {
+	data, err := json.Marshal(res.RuntimeObject())
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields["spec"], nil
+}
+
+// setSpecAnnotation sets the supplied annotation of the supplied resource to
+// the supplied Spec. If the Spec exceeds specAnnotationMaxSize, the
+// last-applied-spec and last-written-spec annotations are removed instead and
+// an error is returned.
+func setSpecAnnotation(
+	res acktypes.AWSResource,
+	annotation string,
+	spec []byte,
+) error {
+	mo := res.MetaObject()
+	annotations := mo.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if len(spec) > specAnnotationMaxSize {
+		delete(annotations, ackv1alpha1.AnnotationLastAppliedSpec)
+		delete(annotations, ackv1alpha1.AnnotationLastWrittenSpec)
+		mo.SetAnnotations(annotations)
+		return fmt.Errorf(
+			"spec of %d bytes exceeds the %d bytes limit of annotation %s",
+			len(spec), specAnnotationMaxSize, annotation,
+		)
+	}
+	annotations[annotation] = string(spec)
+	mo.SetAnnotations(annotations)
+	return nil
+}
+
+// declaredFields returns the members of the supplied desired JSON object that
+// are declared in the supplied last applied JSON object, or that differ from
+// the supplied last written JSON object. Objects are walked recursively, any
+// other value is compared as a whole.
+func declaredFields(
+	desired map[string]interface{},
+	lastApplied map[string]interface{},
+	lastWritten map[string]interface{},
+) map[string]interface{} {
+	declared := map[string]interface{}{}
+	for key, value := range desired {
+		applied, isApplied := lastApplied[key]
+		written := lastWritten[key]
+		object, isObject := value.(map[string]interface{})
+		appliedObject, appliedIsObject := applied.(map[string]interface{})
+		writtenObject, writtenIsObject := written.(map[string]interface{})
+		if isObject && (!isApplied || appliedIsObject) && (written == nil || writtenIsObject) {
+			fields := declaredFields(object, appliedObject, writtenObject)
+			if isApplied || len(fields) > 0 {
+				declared[key] = fields
+			}
+		} else if isApplied || !reflect.DeepEqual(value, written) {
+			declared[key] = value
+		}
+	}
+	return declared
+}
+
 // lateInitializeResource calls AWSResourceManager.LateInitialize() method and
 // returns the AWSResource with late initialized fields.
 //
@@ -462,7 +958,13 @@ func (r *resourceReconciler) lateInitializeResource(
 	// This patching does not hurt because if there is no diff then 'patchResourceMetadataAndSpec'
 	// acts as a no-op.
 	if ackcompare.IsNotNil(lateInitializedLatest) {
+		observing := isObservingAdoptionDrift(latest)
 		patchErr := r.patchResourceMetadataAndSpec(ctx, latest, lateInitializedLatest)
+		if patchErr == nil && observing {
+			// The late initialized fields are not a modification of the Spec
+			// by the Kubernetes user
+			patchErr = r.recordAdoptionObservedGeneration(ctx, lateInitializedLatest)
+		}
 		// Throw the patching error if reconciler is unable to patch the resource with late initializations
 		if patchErr != nil {
 			err = patchErr
@@ -471,6 +973,29 @@ func (r *resourceReconciler) lateInitializeResource(
 	return lateInitializedLatest, err
 }
 
+// recordAdoptionObservedGeneration records the current generation of the
+// supplied AWSResource as the generation of its Spec as adopted, so that the
+// differences between its Spec and the adopted AWS resource keep being
+// observed rather than applied.
+func (r *resourceReconciler) recordAdoptionObservedGeneration(
+	ctx context.Context,
+	res acktypes.AWSResource,
+) error {
+	mo := res.MetaObject()
+	generation := strconv.FormatInt(mo.GetGeneration(), 10)
+	annotations := mo.GetAnnotations()
+	if annotations[ackv1alpha1.AnnotationAdoptionObservedGeneration] == generation {
+		return nil
+	}
+	orig := res.DeepCopy().RuntimeObject()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[ackv1alpha1.AnnotationAdoptionObservedGeneration] = generation
+	mo.SetAnnotations(annotations)
+	return r.patchResourceMetadataAndSpec(ctx, r.rd.ResourceFromRuntimeObject(orig), res)
+}
+
 // patchResourceMetadataAndSpec patches the custom resource in the Kubernetes API to match the
 // supplied latest resource's metadata and spec.
 func (r *resourceReconciler) patchResourceMetadataAndSpec(
@@ -483,6 +1008,12 @@ func (r *resourceReconciler) patchResourceMetadataAndSpec(
 	exit := rlog.Trace("r.patchResourceMetadataAndSpec")
 	defer exit(err)
 
+	// Record the Spec as written by the service controller, so that the
+	// fields the user modifies afterwards can be told apart
+	if err := setLastWrittenSpec(latest); err != nil {
+		rlog.Info("unable to record the last written spec", "error", err)
+	}
+
 	equalMetadata, err := ackcompare.MetaV1ObjectEqual(desired.MetaObject(), latest.MetaObject())
 	if err != nil {
 		return err
@@ -524,11 +1055,27 @@ func (r *resourceReconciler) patchResourceStatus(
 	exit := rlog.Trace("r.patchResourceStatus")
 	defer exit(err)
 
+	// Record the generation of the Spec the conditions were computed for on
+	// every status write, so that clients can tell whether the conditions are
+	// up to date
+	ackcondition.SetObservedGeneration(latest, latest.MetaObject().GetGeneration())
+
+	patch := client.MergeFrom(desired.DeepCopy().RuntimeObject())
+	// Avoid a round trip to the Kubernetes API server when the status did not
+	// change since it was read. The last sync time alone does not warrant a
+	// patch, otherwise the status of every resource would be patched at every
+	// reconciliation loop.
+	unchanged := withLastSyncedTimeOf(latest, desired)
+	if data, err := patch.Data(unchanged.RuntimeObject()); err == nil && string(data) == "{}" {
+		rlog.Debug("no changes to resource status, skipping patch")
+		return nil
+	}
+
 	rlog.Enter("kc.Patch (status)")
 	err = r.kc.Status().Patch(
 		ctx,
 		latest.RuntimeObject(),
-		client.MergeFrom(desired.DeepCopy().RuntimeObject()),
+		patch,
 	)
 	if err == nil {
 		rlog.Debug("patched resource status")
@@ -551,14 +1098,43 @@ func (r *resourceReconciler) deleteResource(
 	rm acktypes.AWSResourceManager,
 	current acktypes.AWSResource,
 ) (acktypes.AWSResource, error) {
-	// TODO(jaypipes): Handle all dependent resources. The AWSResource
-	// interface needs to get some methods that return schema relationships,
-	// first though
 	var err error
 	rlog := ackrtlog.FromContext(ctx)
 	exit := rlog.Trace("r.deleteResource")
 	defer exit(err)
 
+	if r.isDeletionProtected(current) {
+		rlog.Info("deletion protection enabled, not deleting resource")
+		latest := current.DeepCopy()
+		msg := "Resource is protected from deletion. Remove the " +
+			ackv1alpha1.AnnotationDeletionProtection + " annotation or set " +
+			"it to false in order to delete the resource"
+		reason := deletionProtectedReason
+		ackcondition.SetTerminal(latest, corev1.ConditionTrue, &msg, &reason)
+		// Changes to annotations do not trigger reconciliation, so we keep
+		// checking whether the protection was removed
+		return latest, requeue.NeededAfter(
+			ackerr.DeletionProtected,
+			requeue.DefaultRequeueAfterDuration,
+		)
+	}
+
+	// Deleting a resource that other resources still depend on would only
+	// cause the AWS service API to reject the deletion, so we hold it until
+	// the dependents are gone.
+	dependents, err := r.getDependents(ctx, current)
+	if err != nil {
+		return current, err
+	}
+	if len(dependents) > 0 {
+		return r.handleDependents(ctx, current, dependents)
+	}
+	if cond := ackcondition.Recoverable(current); cond != nil &&
+		cond.Reason != nil && *cond.Reason == dependentResourcesExistReason {
+		current = current.DeepCopy()
+		ackcondition.SetRecoverable(current, corev1.ConditionFalse, nil, nil)
+	}
+
 	rlog.Enter("rm.ReadOne")
 	observed, err := rm.ReadOne(ctx, current)
 	rlog.Exit("rm.ReadOne", err)
@@ -604,6 +1180,118 @@ func (r *resourceReconciler) deleteResource(
 	return latest, err
 }
 
+// getDependents returns references to all the CRs handled by the service
+// controller that reference the supplied resource through one of their
+// AWSResourceReferenceWrapper fields. The CRs are looked up in all the
+// namespaces, since references may cross namespaces, with the field index set
+// up in BindControllerManager. The indexed key includes the namespace of the
+// referenced resource.
+//
+// NOTE: Only the resource descriptors implementing
+// AWSResourceReferenceDescriptor are inspected. References from CRs handled by
+// other service controllers are not detected.
+func (r *resourceReconciler) getDependents(
+	ctx context.Context,
+	res acktypes.AWSResource,
+) ([]dependentResource, error) {
+	target := acktypes.ResourceReference{
+		GroupKind: *r.rd.GroupKind(),
+		Namespace: res.MetaObject().GetNamespace(),
+		Name:      res.MetaObject().GetName(),
+	}
+	dependents := []dependentResource{}
+	for _, rmf := range r.sc.GetResourceManagerFactories() {
+		rd := rmf.ResourceDescriptor()
+		refd, ok := rd.(acktypes.AWSResourceReferenceDescriptor)
+		if !ok {
+			continue
+		}
+		list := refd.EmptyRuntimeObjectList()
+		err := r.kc.List(
+			ctx, list,
+			client.MatchingFields{
+				referencedResourcesField: referencedResourceKey(target),
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			obj, ok := item.(client.Object)
+			if !ok {
+				continue
+			}
+			dependent := rd.ResourceFromRuntimeObject(obj)
+			for _, ref := range refd.ReferencedResources(dependent) {
+				if ref == target {
+					dependents = append(dependents, dependentResource{
+						ref: acktypes.ResourceReference{
+							GroupKind: *rd.GroupKind(),
+							Namespace: obj.GetNamespace(),
+							Name:      obj.GetName(),
+						},
+						obj: obj,
+					})
+					break
+				}
+			}
+		}
+	}
+	return dependents, nil
+}
+
+// referencedResourceKey returns the value identifying the supplied referenced
+// CR in the field index of the CRs referencing it
+func referencedResourceKey(ref acktypes.ResourceReference) string {
+	return ref.GroupKind.String() + "/" + ref.Namespace + "/" + ref.Name
+}
+
+// dependentResource is a CR that references another CR
+type dependentResource struct {
+	ref acktypes.ResourceReference
+	obj client.Object
+}
+
+// handleDependents holds the deletion of the supplied resource while the
+// supplied dependents exist, setting a Recoverable condition listing the
+// blocking dependents. If the resource is annotated for cascading deletion,
+// the dependents are deleted first.
+func (r *resourceReconciler) handleDependents(
+	ctx context.Context,
+	current acktypes.AWSResource,
+	dependents []dependentResource,
+) (acktypes.AWSResource, error) {
+	rlog := ackrtlog.FromContext(ctx)
+	cascade := IsCascadeDeletion(current)
+	names := make([]string, 0, len(dependents))
+	for _, dep := range dependents {
+		names = append(names, fmt.Sprintf(
+			"%s/%s/%s", dep.ref.GroupKind.String(), dep.ref.Namespace, dep.ref.Name,
+		))
+		if !cascade || dep.obj.GetDeletionTimestamp() != nil {
+			continue
+		}
+		if err := r.kc.Delete(ctx, dep.obj); err != nil && !apierrors.IsNotFound(err) {
+			return current, err
+		}
+	}
+	rlog.Info("deletion held by dependent resources", "dependents", names)
+
+	latest := current.DeepCopy()
+	msg := "Deletion is waiting for dependent resources to be deleted: " +
+		strings.Join(names, ", ")
+	reason := dependentResourcesExistReason
+	ackcondition.SetRecoverable(latest, corev1.ConditionTrue, &msg, &reason)
+	return latest, requeue.NeededAfter(
+		ackerr.DependentResourcesExist,
+		requeue.DefaultRequeueAfterDuration,
+	)
+}
+
 // setResourceManaged marks the underlying CR in the supplied AWSResource with
 // a finalizer that indicates the object is under ACK management and will not
 // be deleted until that finalizer is removed (in setResourceUnmanaged())
@@ -784,21 +1472,121 @@ func (r *resourceReconciler) HandleReconcileError(
 	return ctrlrt.Result{}, err
 }
 
+// isDeletionProtected returns true if the supplied resource must not be
+// deleted. The resource's deletion protection annotation takes precedence
+// over the default deletion protection of its namespace.
+func (r *resourceReconciler) isDeletionProtected(
+	res acktypes.AWSResource,
+) bool {
+	if v, ok := res.MetaObject().GetAnnotations()[ackv1alpha1.AnnotationDeletionProtection]; ok {
+		return strings.ToLower(v) == "true"
+	}
+	if v, ok := r.cache.Namespaces.GetDefaultDeletionProtection(
+		res.MetaObject().GetNamespace(),
+	); ok {
+		return strings.ToLower(v) == "true"
+	}
+	return false
+}
+
 // getOwnerAccountID returns the AWS account that owns the supplied resource.
 // The function looks to the common `Status.ACKResourceState` object, followed
-// by the default AWS account ID associated with the Kubernetes Namespace in
-// which the CR was created, followed by the AWS Account in which the IAM Role
-// that the service controller is in.
+// by the owner account ID annotation of the CR, followed by the default AWS
+// account ID associated with the Kubernetes Namespace in which the CR was
+// created, followed by the AWS Account in which the IAM Role that the service
+// controller is in.
+//
+// An owner account ID annotation differing from the default AWS account of the
+// CR's namespace is only honoured when an AdoptedResource adopted the CR from
+// that account, and cross-account adoption is allowed. Otherwise an error
+// wrapping ackerr.OwnerAccountIDNotAllowed is returned.
 func (r *resourceReconciler) getOwnerAccountID(
+	ctx context.Context,
 	res acktypes.AWSResource,
-) ackv1alpha1.AWSAccountID {
+) (ackv1alpha1.AWSAccountID, error) {
 	acctID := res.Identifiers().OwnerAccountID()
 	if acctID != nil {
-		return *acctID
+		return *acctID, nil
 	}
 
-	// look for owner account id in the namespace annotations
 	namespace := res.MetaObject().GetNamespace()
+	defaultAcctID := r.getNamespaceOwnerAccountID(namespace)
+
+	// look for owner account id in CR metadata annotations
+	resAnnotations := res.MetaObject().GetAnnotations()
+	accID, ok := resAnnotations[ackv1alpha1.AnnotationOwnerAccountID]
+	if !ok || accID == "" {
+		return defaultAcctID, nil
+	}
+	if ackv1alpha1.AWSAccountID(accID) != defaultAcctID {
+		adopted, err := r.isAdoptedFrom(ctx, res, ackv1alpha1.AWSAccountID(accID))
+		if err != nil {
+			return "", err
+		}
+		if !adopted {
+			return "", fmt.Errorf(
+				"%w: %s is not the AWS account of namespace %s and no "+
+					"AdoptedResource adopted the resource from it",
+				ackerr.OwnerAccountIDNotAllowed, accID, namespace,
+			)
+		}
+	}
+	if err := r.checkOwnerAccountID(namespace, ackv1alpha1.AWSAccountID(accID)); err != nil {
+		return "", err
+	}
+	return ackv1alpha1.AWSAccountID(accID), nil
+}
+
+// isAdoptedFrom returns true if an AdoptedResource in the namespace of the
+// supplied resource targets it and adopted it from the supplied AWS account.
+// The adopted annotation of the CR cannot be relied upon, since any user able
+// to edit the CR can set it.
+func (r *resourceReconciler) isAdoptedFrom(
+	ctx context.Context,
+	res acktypes.AWSResource,
+	acctID ackv1alpha1.AWSAccountID,
+) (bool, error) {
+	mo := res.MetaObject()
+	list := &ackv1alpha1.AdoptedResourceList{}
+	if err := r.kc.List(ctx, list, client.InNamespace(mo.GetNamespace())); err != nil {
+		return false, err
+	}
+	gk := r.rd.GroupKind()
+	for _, adopted := range list.Items {
+		target := adopted.Spec.Kubernetes
+		ids := adopted.Spec.AWS
+		if target == nil || ids == nil || ids.OwnerAccountID == nil ||
+			*ids.OwnerAccountID != acctID {
+			continue
+		}
+		if target.Group != gk.Group || target.Kind != gk.Kind {
+			continue
+		}
+		// The target CR is named after the AdoptedResource unless its
+		// metadata says otherwise
+		name, namespace := adopted.Name, adopted.Namespace
+		if target.Metadata != nil {
+			if target.Metadata.Name != "" {
+				name = target.Metadata.Name
+			}
+			if target.Metadata.Namespace != "" {
+				namespace = target.Metadata.Namespace
+			}
+		}
+		if name == mo.GetName() && namespace == mo.GetNamespace() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// getNamespaceOwnerAccountID returns the default AWS account ID associated
+// with the supplied Kubernetes Namespace, or the AWS Account in which the IAM
+// Role that the service controller is in if the namespace has none.
+func (r *reconciler) getNamespaceOwnerAccountID(
+	namespace string,
+) ackv1alpha1.AWSAccountID {
+	// look for owner account id in the namespace annotations
 	accID, ok := r.cache.Namespaces.GetOwnerAccountID(namespace)
 	if ok {
 		return ackv1alpha1.AWSAccountID(accID)
@@ -808,6 +1596,25 @@ func (r *resourceReconciler) getOwnerAccountID(
 	return ackv1alpha1.AWSAccountID(r.cfg.AccountID)
 }
 
+// checkOwnerAccountID returns an error wrapping ackerr.OwnerAccountIDNotAllowed
+// if the supplied AWS account ID, requested for an adopted AWS resource,
+// differs from the default AWS account of the supplied Kubernetes Namespace
+// while cross-account adoption is not allowed. Users able to create resources
+// in a namespace must not otherwise be able to manage AWS resources in the
+// AWS accounts of other namespaces.
+func (r *reconciler) checkOwnerAccountID(
+	namespace string,
+	acctID ackv1alpha1.AWSAccountID,
+) error {
+	if r.cfg.AllowCrossAccountAdoption || acctID == r.getNamespaceOwnerAccountID(namespace) {
+		return nil
+	}
+	return fmt.Errorf(
+		"%w: %s is not the AWS account of namespace %s and cross-account "+
+			"adoption is not allowed", ackerr.OwnerAccountIDNotAllowed, acctID, namespace,
+	)
+}
+
 // getRoleARN return the Role ARN that should be assumed in order to manage
 // the resources.
 func (r *resourceReconciler) getRoleARN(
@@ -15,7 +15,9 @@ package runtime_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -26,7 +28,10 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sobj "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	k8srtschema "k8s.io/apimachinery/pkg/runtime/schema"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
 	ctrlrtzap "sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
@@ -150,6 +155,7 @@ func TestReconcilerUpdate(t *testing.T) {
 
 	desired, _, _ := resourceMocks()
 	desired.On("ReplaceConditions", []*ackv1alpha1.Condition{}).Return()
+	desired.On("Conditions").Return([]*ackv1alpha1.Condition{})
 
 	ids := &ackmocks.AWSResourceIdentifiers{}
 	ids.On("ARN").Return(&arn)
@@ -229,6 +235,7 @@ func TestReconcilerUpdate_PatchMetadataAndSpec_DiffInMetadata(t *testing.T) {
 
 	desired, _, _ := resourceMocks()
 	desired.On("ReplaceConditions", []*ackv1alpha1.Condition{}).Return()
+	desired.On("Conditions").Return([]*ackv1alpha1.Condition{})
 
 	ids := &ackmocks.AWSResourceIdentifiers{}
 	ids.On("ARN").Return(&arn)
@@ -293,6 +300,7 @@ func TestReconcilerUpdate_PatchMetadataAndSpec_DiffInSpec(t *testing.T) {
 
 	desired, _, _ := resourceMocks()
 	desired.On("ReplaceConditions", []*ackv1alpha1.Condition{}).Return()
+	desired.On("Conditions").Return([]*ackv1alpha1.Condition{})
 
 	ids := &ackmocks.AWSResourceIdentifiers{}
 	ids.On("ARN").Return(&arn)
@@ -365,6 +373,7 @@ func TestReconcilerHandleReconcilerError_PatchStatus_Latest(t *testing.T) {
 
 	desired, _, _ := resourceMocks()
 	desired.On("ReplaceConditions", []*ackv1alpha1.Condition{}).Return()
+	desired.On("Conditions").Return([]*ackv1alpha1.Condition{})
 
 	ids := &ackmocks.AWSResourceIdentifiers{}
 	ids.On("ARN").Return(&arn)
@@ -403,6 +412,7 @@ func TestReconcilerHandleReconcilerError_NoPatchStatus_NoLatest(t *testing.T) {
 
 	desired, _, _ := resourceMocks()
 	desired.On("ReplaceConditions", []*ackv1alpha1.Condition{}).Return()
+	desired.On("Conditions").Return([]*ackv1alpha1.Condition{})
 
 	rmf, _ := managedResourceManagerFactoryMocks(desired, nil)
 	r, kc := reconcilerMocks(rmf)
@@ -432,6 +442,7 @@ func TestReconcilerUpdate_ErrorInLateInitialization(t *testing.T) {
 
 	desired, _, _ := resourceMocks()
 	desired.On("ReplaceConditions", []*ackv1alpha1.Condition{}).Return()
+	desired.On("Conditions").Return([]*ackv1alpha1.Condition{})
 
 	ids := &ackmocks.AWSResourceIdentifiers{}
 	ids.On("ARN").Return(&arn)
@@ -511,6 +522,7 @@ func TestReconcilerUpdate_ResourceNotManaged(t *testing.T) {
 
 	desired, _, _ := resourceMocks()
 	desired.On("ReplaceConditions", []*ackv1alpha1.Condition{}).Return()
+	desired.On("Conditions").Return([]*ackv1alpha1.Condition{})
 
 	ids := &ackmocks.AWSResourceIdentifiers{}
 	ids.On("ARN").Return(&arn)
@@ -580,6 +592,7 @@ func TestReconcilerUpdate_ResolveReferencesError(t *testing.T) {
 
 	desired, _, _ := resourceMocks()
 	desired.On("ReplaceConditions", []*ackv1alpha1.Condition{}).Return()
+	desired.On("Conditions").Return([]*ackv1alpha1.Condition{})
 
 	ids := &ackmocks.AWSResourceIdentifiers{}
 	ids.On("ARN").Return(&arn)
@@ -645,3 +658,1749 @@ func TestReconcilerUpdate_ResolveReferencesError(t *testing.T) {
 	kc.AssertNotCalled(t, "Status")
 	rm.AssertNotCalled(t, "LateInitialize", ctx, latest)
 }
+
+func TestReconcilerSync_AdoptOrCreate_AdoptsUnmanagedResource(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.TODO()
+	arn := ackv1alpha1.AWSResourceName("mybook-arn")
+
+	delta := ackcompare.NewDelta()
+	delta.Add("Spec.A", "val1", "val2")
+
+	desired, desiredRTObj, desiredMetaObj := resourceMocks()
+	desiredMetaObj.SetAnnotations(map[string]string{
+		ackv1alpha1.AnnotationAdoptionPolicy: string(ackv1alpha1.AdoptionPolicyAdoptOrCreate),
+	})
+	desired.On("ReplaceConditions", []*ackv1alpha1.Condition{}).Return()
+	desired.On("Conditions").Return([]*ackv1alpha1.Condition{})
+
+	ids := &ackmocks.AWSResourceIdentifiers{}
+	ids.On("ARN").Return(&arn)
+
+	latest, latestRTObj, _ := resourceMocks()
+	latest.On("Identifiers").Return(ids)
+	latest.On("Conditions").Return([]*ackv1alpha1.Condition{})
+	latest.On("ReplaceConditions", mock.AnythingOfType("[]*v1alpha1.Condition")).Return()
+
+	rm := &ackmocks.AWSResourceManager{}
+	rm.On("ResolveReferences", ctx, nil, desired).Return(desired, nil)
+	rm.On("ReadOne", ctx, desired).Return(latest, nil)
+	rm.On("Update", ctx, desired, latest, delta).Return(latest, nil)
+	rm.On("LateInitialize", ctx, latest).Return(latest, nil)
+
+	// The pre-existing resource is not managed until it has been adopted
+	latestManaged := false
+	rd := &ackmocks.AWSResourceDescriptor{}
+	rd.On("GroupKind").Return(
+		&metav1.GroupKind{
+			Group: "bookstore.services.k8s.aws",
+			Kind:  "fakeBook",
+		},
+	)
+	rd.On("EmptyRuntimeObject").Return(&fakeBook{})
+	rd.On("IsManaged", latest).Return(func(acktypes.AWSResource) bool {
+		return latestManaged
+	})
+	rd.On("MarkManaged", desired).Return()
+	rd.On("MarkAdopted", desired).Return()
+	rd.On("MarkManaged", latest).Return().Run(func(mock.Arguments) {
+		latestManaged = true
+	})
+	rd.On("MarkAdopted", latest).Return()
+	rd.On("ResourceFromRuntimeObject", desiredRTObj).Return(desired)
+	rd.On("Delta", desired, desired).Return(ackcompare.NewDelta())
+	rd.On("Delta", desired, latest).Return(delta).Once()
+	rd.On("Delta", desired, latest).Return(ackcompare.NewDelta())
+	rd.On("Delta", latest, latest).Return(ackcompare.NewDelta())
+
+	rmf := &ackmocks.AWSResourceManagerFactory{}
+	rmf.On("ResourceDescriptor").Return(rd)
+
+	r, kc := reconcilerMocks(rmf)
+	kc.On("Patch", ctx, latestRTObj, mock.AnythingOfType("*client.mergeFromPatch")).Return(nil)
+
+	_, err := r.Sync(ctx, rm, desired)
+	require.Nil(err)
+	rd.AssertCalled(t, "MarkManaged", desired)
+	rd.AssertCalled(t, "MarkAdopted", desired)
+	rd.AssertCalled(t, "MarkManaged", latest)
+	rd.AssertCalled(t, "MarkAdopted", latest)
+	rm.AssertNotCalled(t, "Create", ctx, desired)
+	rm.AssertCalled(t, "Update", ctx, desired, latest, delta)
+}
+
+func TestReconcilerSync_AdoptOnly_ResourceNotFound(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.TODO()
+
+	desired, _, desiredMetaObj := resourceMocks()
+	desiredMetaObj.SetAnnotations(map[string]string{
+		ackv1alpha1.AnnotationAdoptionPolicy: string(ackv1alpha1.AdoptionPolicyAdoptOnly),
+	})
+	desired.On("ReplaceConditions", []*ackv1alpha1.Condition{}).Return()
+	desired.On("Conditions").Return([]*ackv1alpha1.Condition{})
+
+	rm := &ackmocks.AWSResourceManager{}
+	rm.On("ResolveReferences", ctx, nil, desired).Return(desired, nil)
+	rm.On("ReadOne", ctx, desired).Return(nil, ackerr.NotFound)
+
+	rmf, _ := managedResourceManagerFactoryMocks(desired, nil)
+	r, _ := reconcilerMocks(rmf)
+
+	_, err := r.Sync(ctx, rm, desired)
+	require.Equal(ackerr.AdoptedResourceNotFound, err)
+	rm.AssertNotCalled(t, "Create", ctx, desired)
+}
+
+func TestReconcilerSync_InvalidAdoptionPolicy(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.TODO()
+
+	desired, _, desiredMetaObj := resourceMocks()
+	desiredMetaObj.SetAnnotations(map[string]string{
+		ackv1alpha1.AnnotationAdoptionPolicy: "adopt-if-you-like",
+	})
+	desired.On("ReplaceConditions", []*ackv1alpha1.Condition{}).Return()
+	desired.On("Conditions").Return([]*ackv1alpha1.Condition{}).Once()
+	desired.On("ReplaceConditions", mock.AnythingOfType("[]*v1alpha1.Condition")).Return()
+	desired.On("Conditions").Return([]*ackv1alpha1.Condition{})
+
+	rm := &ackmocks.AWSResourceManager{}
+
+	rmf, _ := managedResourceManagerFactoryMocks(desired, nil)
+	r, _ := reconcilerMocks(rmf)
+
+	latest, err := r.Sync(ctx, rm, desired)
+	require.Equal(ackerr.Terminal, err)
+	require.Equal(desired, latest)
+	rm.AssertNotCalled(t, "ReadOne", ctx, desired)
+	var terminal *ackv1alpha1.Condition
+	for _, call := range desired.Calls {
+		if call.Method != "ReplaceConditions" {
+			continue
+		}
+		for _, cond := range call.Arguments.Get(0).([]*ackv1alpha1.Condition) {
+			if cond.Type == ackv1alpha1.ConditionTypeTerminal {
+				terminal = cond
+			}
+		}
+	}
+	require.NotNil(terminal)
+	require.Contains(*terminal.Message, ackerr.InvalidAdoptionPolicy.Error())
+}
+
+func TestReconcilerUpdate_ObserveAdoptionDrift(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.TODO()
+	arn := ackv1alpha1.AWSResourceName("mybook-arn")
+
+	delta := ackcompare.NewDelta()
+	delta.Add("Spec.A", "val1", "val2")
+
+	desired, _, desiredMetaObj := resourceMocks()
+	desiredMetaObj.SetAnnotations(map[string]string{
+		ackv1alpha1.AnnotationAdoptionDriftPolicy:        string(ackv1alpha1.AdoptionDriftPolicyObserve),
+		ackv1alpha1.AnnotationAdoptionObservedGeneration: "1",
+	})
+	desired.On("ReplaceConditions", []*ackv1alpha1.Condition{}).Return()
+	desired.On("Conditions").Return([]*ackv1alpha1.Condition{})
+
+	ids := &ackmocks.AWSResourceIdentifiers{}
+	ids.On("ARN").Return(&arn)
+
+	latest, latestRTObj, latestMetaObj := resourceMocks()
+	latestMetaObj.SetAnnotations(desiredMetaObj.GetAnnotations())
+	latest.On("Identifiers").Return(ids)
+	latest.On("Conditions").Return([]*ackv1alpha1.Condition{})
+	var conditions []*ackv1alpha1.Condition
+	latest.On(
+		"ReplaceConditions",
+		mock.AnythingOfType("[]*v1alpha1.Condition"),
+	).Return().Run(func(args mock.Arguments) {
+		conditions = append(conditions, args.Get(0).([]*ackv1alpha1.Condition)...)
+	})
+
+	rm := &ackmocks.AWSResourceManager{}
+	rm.On("ResolveReferences", ctx, nil, desired).Return(desired, nil)
+	rm.On("ReadOne", ctx, desired).Return(latest, nil)
+	rm.On("LateInitialize", ctx, latest).Return(latest, nil)
+
+	rmf, rd := managedResourceManagerFactoryMocks(desired, latest)
+	rd.On("Delta", desired, latest).Return(delta)
+	rd.On("Delta", latest, latest).Return(ackcompare.NewDelta())
+
+	r, kc := reconcilerMocks(rmf)
+	kc.On("Patch", ctx, latestRTObj, mock.AnythingOfType("*client.mergeFromPatch")).Return(nil)
+
+	_, err := r.Sync(ctx, rm, desired)
+	require.Nil(err)
+	// The newly adopted resource's Spec has not been modified yet, so the
+	// differences are only reported
+	rm.AssertNotCalled(t, "Update", ctx, desired, latest, delta)
+	var advisory, synced *ackv1alpha1.Condition
+	for _, cond := range conditions {
+		switch cond.Type {
+		case ackv1alpha1.ConditionTypeAdvisory:
+			advisory = cond
+		case ackv1alpha1.ConditionTypeResourceSynced:
+			if synced == nil {
+				synced = cond
+			}
+		}
+	}
+	require.NotNil(advisory)
+	require.Equal(corev1.ConditionTrue, advisory.Status)
+	// The AWS resource does not match the Spec
+	require.NotNil(synced)
+	require.Equal(corev1.ConditionFalse, synced.Status)
+
+	// Once the Spec has been modified, the differences are applied
+	desiredMetaObj.SetGeneration(2)
+	rm.On("Update", ctx, desired, latest, delta).Return(latest, nil)
+
+	_, err = r.Sync(ctx, rm, desired)
+	require.Nil(err)
+	rm.AssertCalled(t, "Update", ctx, desired, latest, delta)
+
+	// The differences are applied to resources whose generation was not
+	// recorded at adoption, whatever their generation
+	rm.Calls = nil
+	desiredMetaObj.SetGeneration(1)
+	desiredMetaObj.SetAnnotations(map[string]string{
+		ackv1alpha1.AnnotationAdoptionDriftPolicy: string(ackv1alpha1.AdoptionDriftPolicyObserve),
+	})
+	latestMetaObj.SetAnnotations(desiredMetaObj.GetAnnotations())
+
+	_, err = r.Sync(ctx, rm, desired)
+	require.Nil(err)
+	rm.AssertCalled(t, "Update", ctx, desired, latest, delta)
+}
+
+func TestReconcilerSync_ObserveAdoptionDriftLateInitialize(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.TODO()
+	arn := ackv1alpha1.AWSResourceName("mybook-arn")
+	title := "Dune"
+	publisher := "Chilton"
+
+	delta := ackcompare.NewDelta()
+	delta.Add("Spec.Title", title, "Dune Messiah")
+
+	desiredRes, _, _ := resourceMocks()
+	desiredRes.On("ReplaceConditions", []*ackv1alpha1.Condition{}).Return()
+	desiredRes.On("Conditions").Return([]*ackv1alpha1.Condition{})
+	desired := &specBookResource{
+		AWSResource: desiredRes,
+		obj: &specBook{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "mybook",
+				Namespace:  "default",
+				Generation: 1,
+				Annotations: map[string]string{
+					ackv1alpha1.AnnotationAdoptionDriftPolicy:        string(ackv1alpha1.AdoptionDriftPolicyObserve),
+					ackv1alpha1.AnnotationAdoptionObservedGeneration: "1",
+				},
+			},
+			Spec: specBookSpec{Title: &title},
+		},
+	}
+
+	ids := &ackmocks.AWSResourceIdentifiers{}
+	ids.On("ARN").Return(&arn)
+	latestRes, _, _ := resourceMocks()
+	latestRes.On("Identifiers").Return(ids)
+	latest := &specBookResource{
+		AWSResource: latestRes,
+		obj:         desired.obj.DeepCopyObject().(*specBook),
+	}
+	// The late initialization of the publisher increments the generation of
+	// the CR
+	lateInitialized := latest.DeepCopy().(*specBookResource)
+	lateInitialized.obj.Spec.Publisher = &publisher
+	lateInitialized.obj.Generation = 2
+	lateInitDelta := ackcompare.NewDelta()
+	lateInitDelta.Add("Spec.Publisher", nil, publisher)
+
+	rm := &ackmocks.AWSResourceManager{}
+	rm.On("ResolveReferences", ctx, nil, mock.Anything).Return(desired, nil)
+	rm.On("ReadOne", ctx, desired).Return(latest, nil)
+	rm.On("LateInitialize", ctx, latest).Return(lateInitialized, nil).Run(
+		func(mock.Arguments) {
+			lateInitialized.obj.Status = latest.obj.Status
+		},
+	)
+
+	rmf, rd := managedResourceManagerFactoryMocks(desired, latest)
+	rd.On("Delta", desired, latest).Return(delta)
+	rd.On("Delta", latest, lateInitialized).Return(lateInitDelta)
+	rd.On("Delta", mock.Anything, lateInitialized).Return(ackcompare.NewDelta())
+	rd.On("ResourceFromRuntimeObject", mock.Anything).Return(
+		func(obj rtclient.Object) acktypes.AWSResource {
+			return &specBookResource{AWSResource: latestRes, obj: obj.(*specBook)}
+		},
+	)
+
+	r, kc := reconcilerMocks(rmf)
+	kc.On("Patch", ctx, lateInitialized.obj, mock.AnythingOfType("*client.mergeFromPatch")).Return(nil)
+
+	// The resource is requeued until the differences are applied
+	_, err := r.Sync(ctx, rm, desired)
+	require.True(errors.Is(err, ackerr.TemporaryOutOfSync))
+	rm.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	// The late initialized fields are not a modification of the Spec by the
+	// user, so the differences keep being observed
+	kc.AssertNumberOfCalls(t, "Patch", 2)
+	require.Equal(
+		"2",
+		lateInitialized.obj.GetAnnotations()[ackv1alpha1.AnnotationAdoptionObservedGeneration],
+	)
+}
+
+// comparatorDescriptor is an AWSResourceDescriptor declaring Comparators
+type comparatorDescriptor struct {
+	*ackmocks.AWSResourceDescriptor
+	comparators *ackcompare.ComparatorRegistry
+}
+
+func (d *comparatorDescriptor) Comparators() *ackcompare.ComparatorRegistry {
+	return d.comparators
+}
+
+func TestReconcilerUpdate_IgnoredDrift(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.TODO()
+	arn := ackv1alpha1.AWSResourceName("mybook-arn")
+
+	delta := ackcompare.NewDelta()
+	delta.Add("Spec.A", "val1", "val2")
+	delta.Add("Spec.Policy", `{"Version": "2012-10-17"}`, `{"Version":"2012-10-17"}`)
+
+	desired, _, desiredMetaObj := resourceMocks()
+	desiredMetaObj.SetAnnotations(map[string]string{
+		ackv1alpha1.AnnotationIgnorePaths: "spec.b, spec.a",
+	})
+	desired.On("ReplaceConditions", []*ackv1alpha1.Condition{}).Return()
+	desired.On("Conditions").Return([]*ackv1alpha1.Condition{})
+
+	ids := &ackmocks.AWSResourceIdentifiers{}
+	ids.On("ARN").Return(&arn)
+
+	latest, latestRTObj, latestMetaObj := resourceMocks()
+	latestMetaObj.SetAnnotations(desiredMetaObj.GetAnnotations())
+	latest.On("Identifiers").Return(ids)
+	latest.On("Conditions").Return([]*ackv1alpha1.Condition{})
+	latest.On(
+		"ReplaceConditions",
+		mock.AnythingOfType("[]*v1alpha1.Condition"),
+	).Return()
+
+	rm := &ackmocks.AWSResourceManager{}
+	rm.On("ResolveReferences", ctx, nil, desired).Return(desired, nil)
+	rm.On("ReadOne", ctx, desired).Return(latest, nil)
+	rm.On("LateInitialize", ctx, latest).Return(latest, nil)
+
+	_, rd := managedResourceManagerFactoryMocks(desired, latest)
+	rd.On("Delta", desired, latest).Return(delta)
+	rd.On("Delta", latest, latest).Return(ackcompare.NewDelta())
+	cd := &comparatorDescriptor{
+		AWSResourceDescriptor: rd,
+		comparators: ackcompare.NewComparatorRegistry().
+			Register("Spec.Policy", ackcompare.JSONEqual),
+	}
+	rmf := &ackmocks.AWSResourceManagerFactory{}
+	rmf.On("ResourceDescriptor").Return(cd)
+
+	r, kc := reconcilerMocks(rmf)
+	kc.On("Patch", ctx, latestRTObj, mock.AnythingOfType("*client.mergeFromPatch")).Return(nil)
+
+	_, err := r.Sync(ctx, rm, desired)
+	require.Nil(err)
+	// Spec.A is ignored and both policies hold the same JSON document, so
+	// there is nothing to update
+	rm.AssertNotCalled(t, "Update", ctx, desired, latest, mock.Anything)
+
+	// The differences that are neither ignored nor equivalent are applied
+	delta.Add("Spec.C", "val1", "val2")
+	rm.On("Update", ctx, desired, latest, mock.Anything).Return(latest, nil)
+
+	_, err = r.Sync(ctx, rm, desired)
+	require.Nil(err)
+	rm.AssertCalled(t, "Update", ctx, desired, latest, mock.Anything)
+	var updated *ackcompare.Delta
+	for _, call := range rm.Calls {
+		if call.Method == "Update" {
+			updated = call.Arguments.Get(3).(*ackcompare.Delta)
+		}
+	}
+	require.NotNil(updated)
+	require.Len(updated.Differences, 1)
+	require.Equal("Spec.C", updated.Differences[0].Path.String())
+}
+
+// immutableFieldsDescriptor is an AWSResourceDescriptor declaring immutable
+// fields
+type immutableFieldsDescriptor struct {
+	*ackmocks.AWSResourceDescriptor
+	fields []string
+}
+
+func (d *immutableFieldsDescriptor) ImmutableFields() []string {
+	return d.fields
+}
+
+func TestReconcilerUpdate_ImmutableFieldsModified(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.TODO()
+	arn := ackv1alpha1.AWSResourceName("mybook-arn")
+
+	delta := ackcompare.NewDelta()
+	delta.Add("Spec.Title", "new", "old")
+	delta.Add("Spec.Chapters[2].Name", "new", "old")
+	delta.Add("Spec.Price", "10", "12")
+
+	desired, _, desiredMetaObj := resourceMocks()
+	desired.On("ReplaceConditions", []*ackv1alpha1.Condition{}).Return()
+	desired.On("Conditions").Return([]*ackv1alpha1.Condition{})
+
+	ids := &ackmocks.AWSResourceIdentifiers{}
+	ids.On("ARN").Return(&arn)
+
+	latest, latestRTObj, _ := resourceMocks()
+	latest.On("Identifiers").Return(ids)
+	latest.On("Conditions").Return([]*ackv1alpha1.Condition{})
+	var conditions []*ackv1alpha1.Condition
+	latest.On(
+		"ReplaceConditions",
+		mock.AnythingOfType("[]*v1alpha1.Condition"),
+	).Return().Run(func(args mock.Arguments) {
+		conditions = append(conditions, args.Get(0).([]*ackv1alpha1.Condition)...)
+	})
+
+	rm := &ackmocks.AWSResourceManager{}
+	rm.On("ResolveReferences", ctx, nil, desired).Return(desired, nil)
+	rm.On("ReadOne", ctx, desired).Return(latest, nil)
+	rm.On("LateInitialize", ctx, latest).Return(latest, nil)
+	rm.On("Update", ctx, desired, latest, mock.Anything).Return(latest, nil)
+
+	_, rd := managedResourceManagerFactoryMocks(desired, latest)
+	rd.On("Delta", desired, latest).Return(delta)
+	rd.On("Delta", latest, latest).Return(ackcompare.NewDelta())
+	ifd := &immutableFieldsDescriptor{
+		AWSResourceDescriptor: rd,
+		fields:                []string{"Spec.Title", "Spec.Author", "Spec.Chapters[*].Name"},
+	}
+	rmf := &ackmocks.AWSResourceManagerFactory{}
+	rmf.On("ResourceDescriptor").Return(ifd)
+
+	r, kc := reconcilerMocks(rmf)
+	kc.On("Patch", ctx, latestRTObj, mock.AnythingOfType("*client.mergeFromPatch")).Return(nil)
+
+	findCondition := func(condType ackv1alpha1.ConditionType) *ackv1alpha1.Condition {
+		var found *ackv1alpha1.Condition
+		for _, cond := range conditions {
+			if cond.Type == condType {
+				found = cond
+			}
+		}
+		return found
+	}
+
+	_, err := r.Sync(ctx, rm, desired)
+	require.Nil(err)
+	// The modified immutable fields are reported, and only the other
+	// modifications are applied
+	rm.AssertNumberOfCalls(t, "Update", 1)
+	for _, call := range rm.Calls {
+		if call.Method == "Update" {
+			updated := call.Arguments.Get(3).(*ackcompare.Delta)
+			require.Len(updated.Differences, 1)
+			require.True(updated.DifferentAt("Spec.Price"))
+		}
+	}
+	advisory := findCondition(ackv1alpha1.ConditionTypeAdvisory)
+	require.NotNil(advisory)
+	require.Equal(corev1.ConditionTrue, advisory.Status)
+	require.Contains(*advisory.Message, "Spec.Title, Spec.Chapters[*].Name")
+	require.Contains(*advisory.Message, "other fields are still applied")
+	require.NotContains(*advisory.Message, "Spec.Author")
+	require.Nil(findCondition(ackv1alpha1.ConditionTypeTerminal))
+
+	// With the terminal policy, the resource is also marked as terminal and
+	// nothing is updated
+	desiredMetaObj.SetAnnotations(map[string]string{
+		ackv1alpha1.AnnotationImmutableFieldPolicy: "terminal",
+	})
+	conditions = nil
+	_, err = r.Sync(ctx, rm, desired)
+	require.Equal(ackerr.Terminal, err)
+	rm.AssertNumberOfCalls(t, "Update", 1)
+	require.NotNil(findCondition(ackv1alpha1.ConditionTypeAdvisory))
+	terminal := findCondition(ackv1alpha1.ConditionTypeTerminal)
+	require.NotNil(terminal)
+	require.Equal(corev1.ConditionTrue, terminal.Status)
+}
+
+func TestReconcilerUpdate_ImmutableFieldsNotSent(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.TODO()
+	arn := ackv1alpha1.AWSResourceName("mybook-arn")
+	title := "Dune"
+	edition := "2"
+	publisher := "ace"
+
+	// The user modified the immutable publisher along with the edition
+	delta := ackcompare.NewDelta()
+	delta.Add("Spec.Edition", "2", "1")
+	delta.Add("Spec.Publisher", "ace", "chilton")
+
+	desiredRes, _, _ := resourceMocks()
+	desired := &specBookResource{
+		AWSResource: desiredRes,
+		obj: &specBook{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "mybook",
+				Namespace: "default",
+			},
+			Spec: specBookSpec{
+				Title:     &title,
+				Edition:   &edition,
+				Publisher: &publisher,
+			},
+		},
+	}
+
+	ids := &ackmocks.AWSResourceIdentifiers{}
+	ids.On("ARN").Return(&arn)
+
+	latestRes, _, _ := resourceMocks()
+	latestRes.On("Identifiers").Return(ids)
+	latest := &specBookResource{
+		AWSResource: latestRes,
+		obj:         desired.obj.DeepCopyObject().(*specBook),
+	}
+	latestEdition := "1"
+	latestPublisher := "chilton"
+	latest.obj.Spec.Edition = &latestEdition
+	latest.obj.Spec.Publisher = &latestPublisher
+
+	rm := &ackmocks.AWSResourceManager{}
+	rm.On("ResolveReferences", ctx, nil, mock.Anything).Return(desired, nil)
+	rm.On("ReadOne", ctx, desired).Return(latest, nil)
+	rm.On("LateInitialize", ctx, latest).Return(latest, nil)
+	// The AWS service API rejects any modification of the publisher
+	rm.On("Update", ctx, mock.Anything, latest, mock.Anything).Return(
+		func(
+			_ context.Context, _ acktypes.AWSResource,
+			latest acktypes.AWSResource, _ *ackcompare.Delta,
+		) acktypes.AWSResource {
+			return latest
+		},
+		func(
+			_ context.Context, desired acktypes.AWSResource,
+			_ acktypes.AWSResource, _ *ackcompare.Delta,
+		) error {
+			spec := desired.(*specBookResource).obj.Spec
+			if *spec.Publisher != latestPublisher {
+				return errors.New("publisher cannot be modified")
+			}
+			return nil
+		},
+	)
+
+	rd := &ackmocks.AWSResourceDescriptor{}
+	rd.On("GroupKind").Return(
+		&metav1.GroupKind{
+			Group: "bookstore.services.k8s.aws",
+			Kind:  "fakeBook",
+		},
+	)
+	rd.On("EmptyRuntimeObject").Return(func() rtclient.Object { return &specBook{} })
+	rd.On("IsManaged", latest).Return(true)
+	rd.On("Delta", desired, latest).Return(delta)
+	rd.On("Delta", mock.Anything, latest).Return(ackcompare.NewDelta())
+	ifd := &immutableFieldsDescriptor{
+		AWSResourceDescriptor: rd,
+		fields:                []string{"Spec.Publisher"},
+	}
+	rmf := &ackmocks.AWSResourceManagerFactory{}
+	rmf.On("ResourceDescriptor").Return(ifd)
+
+	r, kc := reconcilerMocks(rmf)
+	kc.On("Patch", ctx, latest.obj, mock.AnythingOfType("*client.mergeFromPatch")).Return(nil)
+
+	_, err := r.Sync(ctx, rm, desired)
+	// The resource is requeued as it does not match its Spec
+	var requeueNeededAfter *requeue.RequeueNeededAfter
+	require.True(errors.As(err, &requeueNeededAfter))
+	// The edition is updated without the modified publisher, which is not
+	// recorded as applied either
+	rm.AssertNumberOfCalls(t, "Update", 1)
+	require.Equal(
+		`{"title":"Dune","edition":"2","publisher":"chilton"}`,
+		latest.obj.GetAnnotations()[ackv1alpha1.AnnotationLastAppliedSpec],
+	)
+	// The user's Spec is left untouched
+	require.Equal("ace", *desired.obj.Spec.Publisher)
+	advisory := condition.Advisory(latest)
+	require.NotNil(advisory)
+	require.Contains(*advisory.Message, "Spec.Publisher")
+}
+
+// specBook is a CR with a typed Spec and Status
+type specBook struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              specBookSpec   `json:"spec"`
+	Status            specBookStatus `json:"status,omitempty"`
+}
+
+type specBookSpec struct {
+	Title     *string `json:"title,omitempty"`
+	Edition   *string `json:"edition,omitempty"`
+	Publisher *string `json:"publisher,omitempty"`
+}
+
+type specBookStatus struct {
+	Conditions []*ackv1alpha1.Condition `json:"conditions"`
+}
+
+func (b *specBook) DeepCopyObject() k8sruntime.Object {
+	data, err := json.Marshal(b)
+	if err != nil {
+		panic(err)
+	}
+	c := &specBook{}
+	if err = json.Unmarshal(data, c); err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// specBookResource is an AWSResource whose CR is a specBook
+type specBookResource struct {
+	*ackmocks.AWSResource
+	obj *specBook
+}
+
+func (r *specBookResource) RuntimeObject() rtclient.Object {
+	return r.obj
+}
+
+func (r *specBookResource) MetaObject() metav1.Object {
+	return r.obj
+}
+
+func (r *specBookResource) DeepCopy() acktypes.AWSResource {
+	return &specBookResource{
+		AWSResource: r.AWSResource,
+		obj:         r.obj.DeepCopyObject().(*specBook),
+	}
+}
+
+func (r *specBookResource) SetStatus(acktypes.AWSResource) {}
+
+func (r *specBookResource) Conditions() []*ackv1alpha1.Condition {
+	return r.obj.Status.Conditions
+}
+
+func (r *specBookResource) ReplaceConditions(conditions []*ackv1alpha1.Condition) {
+	r.obj.Status.Conditions = conditions
+}
+
+func TestReconcilerUpdate_DeclaredDrift(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.TODO()
+	arn := ackv1alpha1.AWSResourceName("mybook-arn")
+	title := "Dune"
+
+	// The edition was removed from the Spec since it was last applied, and
+	// the publisher was never set but modified out-of-band
+	delta := ackcompare.NewDelta()
+	delta.Add("Spec.Edition", nil, "1")
+	delta.Add("Spec.Publisher", nil, "console")
+
+	desiredRes, _, _ := resourceMocks()
+	desiredRes.On("ReplaceConditions", []*ackv1alpha1.Condition{}).Return()
+	desiredRes.On("Conditions").Return([]*ackv1alpha1.Condition{})
+	desired := &specBookResource{
+		AWSResource: desiredRes,
+		obj: &specBook{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "mybook",
+				Namespace: "default",
+				Annotations: map[string]string{
+					ackv1alpha1.AnnotationLastAppliedSpec: `{"title":"Dune","edition":"1"}`,
+				},
+			},
+			Spec: specBookSpec{Title: &title},
+		},
+	}
+
+	ids := &ackmocks.AWSResourceIdentifiers{}
+	ids.On("ARN").Return(&arn)
+
+	latestRes, _, _ := resourceMocks()
+	latestRes.On("Identifiers").Return(ids)
+	latestRes.On("Conditions").Return([]*ackv1alpha1.Condition{})
+	latestRes.On(
+		"ReplaceConditions",
+		mock.AnythingOfType("[]*v1alpha1.Condition"),
+	).Return()
+	latest := &specBookResource{
+		AWSResource: latestRes,
+		obj:         desired.obj.DeepCopyObject().(*specBook),
+	}
+
+	rm := &ackmocks.AWSResourceManager{}
+	// Sync works on a copy of the supplied resource
+	rm.On("ResolveReferences", ctx, nil, mock.Anything).Return(desired, nil)
+	rm.On("ReadOne", ctx, desired).Return(latest, nil)
+	rm.On("LateInitialize", ctx, latest).Return(latest, nil)
+	rm.On("Update", ctx, desired, latest, mock.Anything).Return(latest, nil)
+
+	rd := &ackmocks.AWSResourceDescriptor{}
+	rd.On("GroupKind").Return(
+		&metav1.GroupKind{
+			Group: "bookstore.services.k8s.aws",
+			Kind:  "fakeBook",
+		},
+	)
+	rd.On("EmptyRuntimeObject").Return(func() rtclient.Object { return &specBook{} })
+	rd.On("IsManaged", latest).Return(true)
+	rd.On("Delta", desired, latest).Return(delta)
+	rd.On("Delta", latest, latest).Return(ackcompare.NewDelta())
+	rmf := &ackmocks.AWSResourceManagerFactory{}
+	rmf.On("ResourceDescriptor").Return(rd)
+
+	r, kc := reconcilerMocks(rmf)
+	kc.On("Patch", ctx, latest.obj, mock.AnythingOfType("*client.mergeFromPatch")).Return(nil)
+
+	updatedPaths := func() []string {
+		paths := []string{}
+		for _, call := range rm.Calls {
+			if call.Method == "Update" {
+				paths = []string{}
+				for _, diff := range call.Arguments.Get(3).(*ackcompare.Delta).Differences {
+					paths = append(paths, diff.Path.String())
+				}
+			}
+		}
+		return paths
+	}
+
+	_, err := r.Sync(ctx, rm, desired)
+	require.Nil(err)
+	// Only the removal of the edition is applied
+	require.Equal([]string{"Spec.Edition"}, updatedPaths())
+	// The applied Spec is recorded on the CR
+	require.Equal(
+		`{"title":"Dune"}`,
+		latest.obj.GetAnnotations()[ackv1alpha1.AnnotationLastAppliedSpec],
+	)
+
+	// With the strict drift policy, every difference is applied
+	desired.obj.Annotations[ackv1alpha1.AnnotationDriftPolicy] = "strict"
+	latest.obj.Status.Conditions = nil
+	_, err = r.Sync(ctx, rm, desired)
+	require.Nil(err)
+	require.Equal([]string{"Spec.Edition", "Spec.Publisher"}, updatedPaths())
+}
+
+func TestReconcilerUpdate_DeclaredDriftWrittenFields(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.TODO()
+	arn := ackv1alpha1.AWSResourceName("mybook-arn")
+	title := "Dune"
+	edition := "2"
+	publisher := "aws"
+
+	// The publisher was copied from the AWS resource to the Spec by the
+	// controller and modified out-of-band, while the edition was set by the
+	// user since the Spec was last written
+	delta := ackcompare.NewDelta()
+	delta.Add("Spec.Edition", "2", "1")
+	delta.Add("Spec.Publisher", "aws", "console")
+
+	desiredRes, _, _ := resourceMocks()
+	desiredRes.On("ReplaceConditions", []*ackv1alpha1.Condition{}).Return()
+	desiredRes.On("Conditions").Return([]*ackv1alpha1.Condition{})
+	desired := &specBookResource{
+		AWSResource: desiredRes,
+		obj: &specBook{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "mybook",
+				Namespace: "default",
+				Annotations: map[string]string{
+					ackv1alpha1.AnnotationLastAppliedSpec: `{"title":"Dune"}`,
+					ackv1alpha1.AnnotationLastWrittenSpec: `{"title":"Dune","publisher":"aws"}`,
+				},
+			},
+			Spec: specBookSpec{Title: &title, Edition: &edition, Publisher: &publisher},
+		},
+	}
+
+	ids := &ackmocks.AWSResourceIdentifiers{}
+	ids.On("ARN").Return(&arn)
+
+	latestRes, _, _ := resourceMocks()
+	latestRes.On("Identifiers").Return(ids)
+	latestRes.On("Conditions").Return([]*ackv1alpha1.Condition{})
+	latestRes.On(
+		"ReplaceConditions",
+		mock.AnythingOfType("[]*v1alpha1.Condition"),
+	).Return()
+	latest := &specBookResource{
+		AWSResource: latestRes,
+		obj:         desired.obj.DeepCopyObject().(*specBook),
+	}
+
+	rm := &ackmocks.AWSResourceManager{}
+	rm.On("ResolveReferences", ctx, nil, mock.Anything).Return(desired, nil)
+	rm.On("ReadOne", ctx, desired).Return(latest, nil)
+	rm.On("LateInitialize", ctx, latest).Return(latest, nil)
+	rm.On("Update", ctx, desired, latest, mock.Anything).Return(latest, nil)
+
+	rd := &ackmocks.AWSResourceDescriptor{}
+	rd.On("GroupKind").Return(
+		&metav1.GroupKind{
+			Group: "bookstore.services.k8s.aws",
+			Kind:  "fakeBook",
+		},
+	)
+	rd.On("EmptyRuntimeObject").Return(func() rtclient.Object { return &specBook{} })
+	rd.On("IsManaged", latest).Return(true)
+	rd.On("Delta", desired, latest).Return(delta)
+	rd.On("Delta", latest, latest).Return(ackcompare.NewDelta())
+	rmf := &ackmocks.AWSResourceManagerFactory{}
+	rmf.On("ResourceDescriptor").Return(rd)
+
+	r, kc := reconcilerMocks(rmf)
+	kc.On("Patch", ctx, mock.Anything, mock.AnythingOfType("*client.mergeFromPatch")).Return(nil)
+
+	_, err := r.Sync(ctx, rm, desired)
+	require.Nil(err)
+	// Only the edition set by the user is applied
+	updates := 0
+	for _, call := range rm.Calls {
+		if call.Method == "Update" {
+			updates++
+			delta := call.Arguments.Get(3).(*ackcompare.Delta)
+			require.Len(delta.Differences, 1)
+			require.Equal("Spec.Edition", delta.Differences[0].Path.String())
+		}
+	}
+	require.Equal(1, updates)
+	// The edition is now declared by the user, unlike the publisher, and the
+	// Spec written by the controller is recorded
+	annotations := latest.obj.GetAnnotations()
+	require.Equal(
+		`{"edition":"2","title":"Dune"}`,
+		annotations[ackv1alpha1.AnnotationLastAppliedSpec],
+	)
+	require.Equal(
+		`{"title":"Dune","edition":"2","publisher":"aws"}`,
+		annotations[ackv1alpha1.AnnotationLastWrittenSpec],
+	)
+
+	// Specs too large to be recorded remove the annotations, so that every
+	// difference is applied afterwards
+	longTitle := strings.Repeat("a", 64*1024)
+	desired.obj.Spec.Title = &longTitle
+	latest.obj = desired.obj.DeepCopyObject().(*specBook)
+	_, err = r.Sync(ctx, rm, desired)
+	require.Nil(err)
+	require.NotContains(latest.obj.GetAnnotations(), ackv1alpha1.AnnotationLastAppliedSpec)
+	require.NotContains(latest.obj.GetAnnotations(), ackv1alpha1.AnnotationLastWrittenSpec)
+}
+
+func TestReconcilerUpdate_ImmutableFieldsDrift(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.TODO()
+	arn := ackv1alpha1.AWSResourceName("mybook-arn")
+	title := "Dune"
+	edition := "2"
+	publisher := "aws"
+
+	// The immutable publisher was copied from the AWS resource to the Spec by
+	// the controller and differs from the AWS resource, while the edition
+	// was set by the user
+	delta := ackcompare.NewDelta()
+	delta.Add("Spec.Edition", "2", "1")
+	delta.Add("Spec.Publisher", "aws", "console")
+
+	desiredRes, _, _ := resourceMocks()
+	desiredRes.On("ReplaceConditions", []*ackv1alpha1.Condition{}).Return()
+	desiredRes.On("Conditions").Return([]*ackv1alpha1.Condition{})
+	desired := &specBookResource{
+		AWSResource: desiredRes,
+		obj: &specBook{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "mybook",
+				Namespace: "default",
+				Annotations: map[string]string{
+					ackv1alpha1.AnnotationDriftPolicy:     "strict",
+					ackv1alpha1.AnnotationLastAppliedSpec: `{"title":"Dune"}`,
+					ackv1alpha1.AnnotationLastWrittenSpec: `{"title":"Dune","publisher":"aws"}`,
+				},
+			},
+			Spec: specBookSpec{Title: &title, Edition: &edition, Publisher: &publisher},
+		},
+	}
+
+	ids := &ackmocks.AWSResourceIdentifiers{}
+	ids.On("ARN").Return(&arn)
+
+	latestRes, _, _ := resourceMocks()
+	latestRes.On("Identifiers").Return(ids)
+	latestRes.On("Conditions").Return([]*ackv1alpha1.Condition{})
+	latestRes.On(
+		"ReplaceConditions",
+		mock.AnythingOfType("[]*v1alpha1.Condition"),
+	).Return()
+	latest := &specBookResource{
+		AWSResource: latestRes,
+		obj:         desired.obj.DeepCopyObject().(*specBook),
+	}
+
+	rm := &ackmocks.AWSResourceManager{}
+	rm.On("ResolveReferences", ctx, nil, mock.Anything).Return(desired, nil)
+	rm.On("ReadOne", ctx, desired).Return(latest, nil)
+	rm.On("LateInitialize", ctx, latest).Return(latest, nil)
+	rm.On("Update", ctx, desired, latest, mock.Anything).Return(latest, nil)
+
+	rd := &ackmocks.AWSResourceDescriptor{}
+	rd.On("GroupKind").Return(
+		&metav1.GroupKind{
+			Group: "bookstore.services.k8s.aws",
+			Kind:  "fakeBook",
+		},
+	)
+	rd.On("EmptyRuntimeObject").Return(func() rtclient.Object { return &specBook{} })
+	rd.On("IsManaged", latest).Return(true)
+	rd.On("Delta", desired, latest).Return(delta)
+	rd.On("Delta", latest, latest).Return(ackcompare.NewDelta())
+	ifd := &immutableFieldsDescriptor{
+		AWSResourceDescriptor: rd,
+		fields:                []string{"Spec.Publisher"},
+	}
+	rmf := &ackmocks.AWSResourceManagerFactory{}
+	rmf.On("ResourceDescriptor").Return(ifd)
+
+	r, kc := reconcilerMocks(rmf)
+	kc.On("Patch", ctx, mock.Anything, mock.AnythingOfType("*client.mergeFromPatch")).Return(nil)
+
+	_, err := r.Sync(ctx, rm, desired)
+	require.Nil(err)
+	// The immutable field the user did not modify is not reported, and is
+	// not part of the applied differences
+	rm.AssertNumberOfCalls(t, "Update", 1)
+	for _, call := range rm.Calls {
+		if call.Method == "Update" {
+			updated := call.Arguments.Get(3).(*ackcompare.Delta)
+			require.Len(updated.Differences, 1)
+			require.True(updated.DifferentAt("Spec.Edition"))
+		}
+	}
+	for _, cond := range latest.Conditions() {
+		require.NotEqual(ackv1alpha1.ConditionTypeAdvisory, cond.Type)
+	}
+}
+
+// taggableResource is an AWSResource whose Spec contains tags
+type taggableResource struct {
+	*ackmocks.AWSResource
+	obj  *k8sobj.Unstructured
+	tags map[string]string
+}
+
+func (r *taggableResource) RuntimeObject() rtclient.Object {
+	return r.obj
+}
+
+func (r *taggableResource) DeepCopy() acktypes.AWSResource {
+	return r
+}
+
+func (r *taggableResource) GetTags() map[string]string {
+	return r.tags
+}
+
+func (r *taggableResource) SetTags(tags map[string]string) {
+	r.tags = tags
+}
+
+func TestReconcilerSync_DefaultTags(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.TODO()
+	res, _, metaObj := resourceMocks()
+	res.On("ReplaceConditions", []*ackv1alpha1.Condition{}).Return()
+	res.On("Conditions").Return([]*ackv1alpha1.Condition{})
+	obj := &k8sobj.Unstructured{}
+	obj.SetNamespace(metaObj.GetNamespace())
+	obj.SetName(metaObj.GetName())
+	obj.SetLabels(map[string]string{"team": "checkout"})
+	desired := &taggableResource{
+		AWSResource: res,
+		obj:         obj,
+		tags: map[string]string{
+			"app":                         "bookstore",
+			"services.k8s.aws/controller": "mine",
+		},
+	}
+
+	rm := &ackmocks.AWSResourceManager{}
+	rm.On("ResolveReferences", ctx, nil, desired).Return(desired, nil)
+	readErr := errors.New("read failed")
+	rm.On("ReadOne", ctx, desired).Return(nil, readErr)
+
+	rmf, _ := managedResourceManagerFactoryMocks(desired, nil)
+
+	zapOptions := ctrlrtzap.Options{
+		Development: true,
+		Level:       zapcore.InfoLevel,
+	}
+	fakeLogger := ctrlrtzap.New(ctrlrtzap.UseFlagOptions(&zapOptions))
+	cfg := ackcfg.Config{
+		ResourceTags: []string{
+			"services.k8s.aws/controller=ACK",
+			"cost-center=0000",
+			"environment=prod",
+		},
+	}
+	caches := ackrtcache.New(fakeLogger)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	caches.Namespaces.Run(k8sfake.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: metaObj.GetNamespace(),
+			Annotations: map[string]string{
+				ackv1alpha1.AnnotationDefaultTags: `{"cost-center":"1234","team":"%K8S_LABEL:team%"}`,
+			},
+		},
+	}), stopCh)
+	require.Eventually(func() bool {
+		_, ok := caches.Namespaces.GetDefaultTags(metaObj.GetNamespace())
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	r := ackrt.NewReconcilerWithClient(
+		&ackmocks.ServiceController{}, &ctrlrtclientmock.Client{}, rmf,
+		fakeLogger, cfg, ackmetrics.NewMetrics("bookstore"), caches,
+	)
+	_, err := r.Sync(ctx, rm, desired)
+	require.Equal(readErr, err)
+	// CR tags override namespace tags, which override controller tags, except
+	// for the keys reserved to the controller
+	require.Equal(map[string]string{
+		"services.k8s.aws/controller": "ACK",
+		"cost-center":                 "1234",
+		"environment":                 "prod",
+		"team":                        "checkout",
+		"app":                         "bookstore",
+	}, desired.tags)
+}
+
+func TestReconcilerSync_TimeBasedDefaultTags(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.TODO()
+	res, _, metaObj := resourceMocks()
+	res.On("ReplaceConditions", []*ackv1alpha1.Condition{}).Return()
+	res.On("Conditions").Return([]*ackv1alpha1.Condition{})
+	obj := &k8sobj.Unstructured{}
+	obj.SetNamespace(metaObj.GetNamespace())
+	obj.SetName(metaObj.GetName())
+	desired := &taggableResource{
+		AWSResource: res,
+		obj:         obj,
+	}
+
+	rm := &ackmocks.AWSResourceManager{}
+	rm.On("ResolveReferences", ctx, nil, desired).Return(desired, nil)
+	readErr := errors.New("read failed")
+	rm.On("ReadOne", ctx, desired).Return(nil, readErr)
+
+	rmf, _ := managedResourceManagerFactoryMocks(desired, nil)
+
+	zapOptions := ctrlrtzap.Options{
+		Development: true,
+		Level:       zapcore.InfoLevel,
+	}
+	fakeLogger := ctrlrtzap.New(ctrlrtzap.UseFlagOptions(&zapOptions))
+	cfg := ackcfg.Config{
+		ResourceTags: []string{
+			"services.k8s.aws/created=%UTCNOW%",
+			"services.k8s.aws/name=%KUBERNETES_NAME%",
+		},
+	}
+	r := ackrt.NewReconcilerWithClient(
+		&ackmocks.ServiceController{}, &ctrlrtclientmock.Client{}, rmf,
+		fakeLogger, cfg, ackmetrics.NewMetrics("bookstore"),
+		ackrtcache.New(fakeLogger),
+	)
+
+	// The time-based tag is expanded when the resource has none yet
+	_, err := r.Sync(ctx, rm, desired)
+	require.Equal(readErr, err)
+	created := desired.tags["services.k8s.aws/created"]
+	_, err = time.Parse(time.RFC3339, created)
+	require.Nil(err)
+
+	// The time-based tag then keeps the value set on the resource, while the
+	// other default tags are still expanded at every loop
+	desired.tags = map[string]string{
+		"services.k8s.aws/created": "2020-01-01T00:00:00Z",
+		"services.k8s.aws/name":    "oldname",
+	}
+	_, err = r.Sync(ctx, rm, desired)
+	require.Equal(readErr, err)
+	require.Equal(map[string]string{
+		"services.k8s.aws/created": "2020-01-01T00:00:00Z",
+		"services.k8s.aws/name":    metaObj.GetName(),
+	}, desired.tags)
+}
+
+// referencingDescriptor is an AWSResourceDescriptor of CRs referencing the
+// fakeBook CR named in their "book" label, in the namespace named in their
+// "book-namespace" label if any, or else in their own namespace
+type referencingDescriptor struct {
+	*ackmocks.AWSResourceDescriptor
+}
+
+func (d *referencingDescriptor) EmptyRuntimeObjectList() rtclient.ObjectList {
+	return &k8sobj.UnstructuredList{}
+}
+
+func (d *referencingDescriptor) ReferencedResources(
+	res acktypes.AWSResource,
+) []acktypes.ResourceReference {
+	mo := res.MetaObject()
+	namespace := mo.GetNamespace()
+	if bookNamespace, ok := mo.GetLabels()["book-namespace"]; ok {
+		namespace = bookNamespace
+	}
+	return []acktypes.ResourceReference{{
+		GroupKind: metav1.GroupKind{
+			Group: "bookstore.services.k8s.aws",
+			Kind:  "fakeBook",
+		},
+		Namespace: namespace,
+		Name:      mo.GetLabels()["book"],
+	}}
+}
+
+func (d *referencingDescriptor) ResourceFromRuntimeObject(
+	obj rtclient.Object,
+) acktypes.AWSResource {
+	res := &ackmocks.AWSResource{}
+	res.On("MetaObject").Return(obj)
+	return res
+}
+
+// deletionMocks contains the mocks of the deletion of a fakeBook CR
+type deletionMocks struct {
+	r          acktypes.AWSResourceReconciler
+	res        *ackmocks.AWSResource
+	metaObj    *k8sobj.Unstructured
+	rm         *ackmocks.AWSResourceManager
+	rd         *ackmocks.AWSResourceDescriptor
+	kc         *ctrlrtclientmock.Client
+	caches     ackrtcache.Caches
+	conditions []*ackv1alpha1.Condition
+}
+
+// condition returns the condition of the supplied type of the deleted CR
+func (m *deletionMocks) condition(
+	condType ackv1alpha1.ConditionType,
+) *ackv1alpha1.Condition {
+	for _, cond := range m.conditions {
+		if cond.Type == condType {
+			return cond
+		}
+	}
+	return nil
+}
+
+// newDeletionMocks returns the mocks of the deletion of a fakeBook CR, which
+// is referenced by the supplied dependent CRs. The backend AWS resource is
+// deleted successfully.
+func newDeletionMocks(
+	ctx context.Context,
+	dependents ...*k8sobj.Unstructured,
+) *deletionMocks {
+	m := &deletionMocks{}
+	res, rtObj, metaObj := resourceMocks()
+	res.On("Conditions").Return(func() []*ackv1alpha1.Condition {
+		return m.conditions
+	})
+	res.On(
+		"ReplaceConditions",
+		mock.AnythingOfType("[]*v1alpha1.Condition"),
+	).Return().Run(func(args mock.Arguments) {
+		m.conditions = args.Get(0).([]*ackv1alpha1.Condition)
+	})
+	m.res = res
+	m.metaObj = metaObj
+
+	rmf, rd := managedResourceManagerFactoryMocks(res, res)
+	rd.On("IsManaged", res).Return(true)
+	rd.On("MarkUnmanaged", res).Return()
+	rd.On("ResourceFromRuntimeObject", rtObj).Return(res)
+	rd.On("Delta", res, res).Return(ackcompare.NewDelta())
+	m.rd = rd
+
+	depRD := &ackmocks.AWSResourceDescriptor{}
+	depRD.On("GroupKind").Return(
+		&metav1.GroupKind{
+			Group: "bookstore.services.k8s.aws",
+			Kind:  "fakeReview",
+		},
+	)
+	depRMF := &ackmocks.AWSResourceManagerFactory{}
+	depRMF.On("ResourceDescriptor").Return(
+		&referencingDescriptor{AWSResourceDescriptor: depRD},
+	)
+	sc := &ackmocks.ServiceController{}
+	sc.On("GetResourceManagerFactories").Return(
+		map[string]acktypes.AWSResourceManagerFactory{
+			"fakeBook.bookstore.services.k8s.aws":   rmf,
+			"fakeReview.bookstore.services.k8s.aws": depRMF,
+		},
+	)
+
+	kc := &ctrlrtclientmock.Client{}
+	kc.On(
+		"List", ctx, mock.AnythingOfType("*unstructured.UnstructuredList"),
+		rtclient.MatchingFields{
+			".ack.referencedResources": "fakeBook.bookstore.services.k8s.aws/default/mybook",
+		},
+	).Return(nil).Run(func(args mock.Arguments) {
+		list := args.Get(1).(*k8sobj.UnstructuredList)
+		for _, dep := range dependents {
+			list.Items = append(list.Items, *dep)
+		}
+	})
+	kc.On("Delete", ctx, mock.AnythingOfType("*unstructured.Unstructured")).Return(nil)
+	m.kc = kc
+
+	rm := &ackmocks.AWSResourceManager{}
+	rm.On("ReadOne", ctx, res).Return(res, nil)
+	rm.On("Delete", ctx, res).Return(nil, nil)
+	m.rm = rm
+
+	zapOptions := ctrlrtzap.Options{
+		Development: true,
+		Level:       zapcore.InfoLevel,
+	}
+	fakeLogger := ctrlrtzap.New(ctrlrtzap.UseFlagOptions(&zapOptions))
+	m.caches = ackrtcache.New(fakeLogger)
+	m.r = ackrt.NewReconcilerWithClient(
+		sc, kc, rmf, fakeLogger, ackcfg.Config{},
+		ackmetrics.NewMetrics("bookstore"), m.caches,
+	)
+	return m
+}
+
+// dependentMock returns a fakeReview CR referencing the named fakeBook CR
+func dependentMock(name string, book string) *k8sobj.Unstructured {
+	obj := &k8sobj.Unstructured{}
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	obj.SetLabels(map[string]string{"book": book})
+	return obj
+}
+
+func TestReconcilerDelete_NoDependents(t *testing.T) {
+	for _, cascade := range []bool{false, true} {
+		require := require.New(t)
+
+		ctx := context.TODO()
+		m := newDeletionMocks(ctx, dependentMock("review", "otherbook"))
+		if cascade {
+			m.metaObj.SetAnnotations(map[string]string{
+				ackv1alpha1.AnnotationCascadeDeletion: "true",
+			})
+		}
+
+		_, err := ackrt.DeleteResource(m.r, ctx, m.rm, m.res)
+		require.Nil(err)
+		// The CR referencing another book does not hold the deletion
+		m.rm.AssertCalled(t, "Delete", ctx, m.res)
+		m.rd.AssertCalled(t, "MarkUnmanaged", m.res)
+		m.kc.AssertNotCalled(t, "Delete", ctx, mock.Anything)
+		require.Nil(m.condition(ackv1alpha1.ConditionTypeRecoverable))
+	}
+}
+
+func TestReconcilerDelete_DependentsBlock(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.TODO()
+	m := newDeletionMocks(
+		ctx,
+		dependentMock("review-1", "mybook"),
+		dependentMock("review-2", "otherbook"),
+	)
+
+	_, err := ackrt.DeleteResource(m.r, ctx, m.rm, m.res)
+	require.NotNil(err)
+	require.True(errors.Is(err, ackerr.DependentResourcesExist))
+	var requeueNeededAfter *requeue.RequeueNeededAfter
+	require.True(errors.As(err, &requeueNeededAfter))
+
+	// The AWS resource is not deleted, the finalizer is kept and the
+	// dependents are left alone
+	m.rm.AssertNotCalled(t, "ReadOne", ctx, m.res)
+	m.rm.AssertNotCalled(t, "Delete", ctx, m.res)
+	m.rd.AssertNotCalled(t, "MarkUnmanaged", m.res)
+	m.kc.AssertNotCalled(t, "Delete", ctx, mock.Anything)
+
+	recoverable := m.condition(ackv1alpha1.ConditionTypeRecoverable)
+	require.NotNil(recoverable)
+	require.Equal(corev1.ConditionTrue, recoverable.Status)
+	require.Equal("DependentResourcesExist", *recoverable.Reason)
+	require.Contains(*recoverable.Message, "fakeReview.bookstore.services.k8s.aws/default/review-1")
+	require.NotContains(*recoverable.Message, "review-2")
+}
+
+func TestReconcilerDelete_CrossNamespaceDependentsBlock(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.TODO()
+	dependent := dependentMock("review-1", "mybook")
+	dependent.SetNamespace("reviews")
+	dependent.SetLabels(map[string]string{
+		"book":           "mybook",
+		"book-namespace": "default",
+	})
+	m := newDeletionMocks(ctx, dependent)
+
+	_, err := ackrt.DeleteResource(m.r, ctx, m.rm, m.res)
+	require.True(errors.Is(err, ackerr.DependentResourcesExist))
+
+	// A CR in another namespace referencing the book holds its deletion
+	m.rm.AssertNotCalled(t, "Delete", ctx, m.res)
+	recoverable := m.condition(ackv1alpha1.ConditionTypeRecoverable)
+	require.NotNil(recoverable)
+	require.Contains(*recoverable.Message, "fakeReview.bookstore.services.k8s.aws/reviews/review-1")
+}
+
+func TestReconcilerDelete_DependentsCascade(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.TODO()
+	dependent := dependentMock("review-1", "mybook")
+	deleting := dependentMock("review-2", "mybook")
+	now := metav1.Now()
+	deleting.SetDeletionTimestamp(&now)
+	m := newDeletionMocks(ctx, dependent, deleting)
+	m.metaObj.SetAnnotations(map[string]string{
+		ackv1alpha1.AnnotationCascadeDeletion: "true",
+	})
+
+	_, err := ackrt.DeleteResource(m.r, ctx, m.rm, m.res)
+	require.True(errors.Is(err, ackerr.DependentResourcesExist))
+
+	// The dependents that are not being deleted yet are deleted, and the
+	// deletion is held until they are gone
+	m.kc.AssertNumberOfCalls(t, "Delete", 1)
+	deleted := m.kc.Calls[len(m.kc.Calls)-1].Arguments.Get(1).(*k8sobj.Unstructured)
+	require.Equal("review-1", deleted.GetName())
+	m.rm.AssertNotCalled(t, "Delete", ctx, m.res)
+	m.rd.AssertNotCalled(t, "MarkUnmanaged", m.res)
+	require.NotNil(m.condition(ackv1alpha1.ConditionTypeRecoverable))
+}
+
+func TestReconcilerDelete_DeletionProtected(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.TODO()
+	m := newDeletionMocks(ctx)
+	m.metaObj.SetAnnotations(map[string]string{
+		ackv1alpha1.AnnotationDeletionProtection: "true",
+	})
+
+	_, err := ackrt.DeleteResource(m.r, ctx, m.rm, m.res)
+	require.True(errors.Is(err, ackerr.DeletionProtected))
+	var requeueNeededAfter *requeue.RequeueNeededAfter
+	require.True(errors.As(err, &requeueNeededAfter))
+
+	// The AWS resource is not deleted and the finalizer is kept
+	m.rm.AssertNotCalled(t, "ReadOne", ctx, m.res)
+	m.rm.AssertNotCalled(t, "Delete", ctx, m.res)
+	m.rd.AssertNotCalled(t, "MarkUnmanaged", m.res)
+
+	terminal := m.condition(ackv1alpha1.ConditionTypeTerminal)
+	require.NotNil(terminal)
+	require.Equal(corev1.ConditionTrue, terminal.Status)
+	require.Equal("DeletionProtected", *terminal.Reason)
+	require.Contains(*terminal.Message, ackv1alpha1.AnnotationDeletionProtection)
+}
+
+func TestReconcilerDelete_NamespaceDeletionProtection(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.TODO()
+	m := newDeletionMocks(ctx)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	m.caches.Namespaces.Run(k8sfake.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: m.metaObj.GetNamespace(),
+			Annotations: map[string]string{
+				ackv1alpha1.AnnotationDefaultDeletionProtection: "true",
+			},
+		},
+	}), stopCh)
+	require.Eventually(func() bool {
+		_, ok := m.caches.Namespaces.GetDefaultDeletionProtection(m.metaObj.GetNamespace())
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	_, err := ackrt.DeleteResource(m.r, ctx, m.rm, m.res)
+	require.True(errors.Is(err, ackerr.DeletionProtected))
+	m.rm.AssertNotCalled(t, "Delete", ctx, m.res)
+	m.rd.AssertNotCalled(t, "MarkUnmanaged", m.res)
+	require.NotNil(m.condition(ackv1alpha1.ConditionTypeTerminal))
+
+	// The annotation of the CR overrides the namespace default
+	m.metaObj.SetAnnotations(map[string]string{
+		ackv1alpha1.AnnotationDeletionProtection: "false",
+	})
+	_, err = ackrt.DeleteResource(m.r, ctx, m.rm, m.res)
+	require.Nil(err)
+	m.rm.AssertCalled(t, "Delete", ctx, m.res)
+	m.rd.AssertCalled(t, "MarkUnmanaged", m.res)
+}
+
+// syncedBookMocks returns the mocks of the reconciliation of the supplied
+// specBook CR, whose AWS resource is in sync with its Spec
+func syncedBookMocks(
+	ctx context.Context,
+	desired *specBookResource,
+) (
+	acktypes.AWSResourceReconciler,
+	*ackmocks.AWSResourceManager,
+	*specBookResource,
+	*ctrlrtclientmock.StatusWriter,
+) {
+	latest := desired.DeepCopy().(*specBookResource)
+	latest.obj.Status.Conditions = nil
+
+	rm := &ackmocks.AWSResourceManager{}
+	rm.On("ResolveReferences", ctx, nil, mock.Anything).Return(desired, nil)
+	rm.On("ReadOne", ctx, desired).Return(latest, nil)
+	rm.On("LateInitialize", ctx, latest).Return(latest, nil)
+
+	rmf, rd := managedResourceManagerFactoryMocks(desired, latest)
+	rmf.On("RequeueOnSuccessSeconds").Return(0)
+	rd.On("Delta", desired, latest).Return(ackcompare.NewDelta())
+	rd.On("Delta", latest, latest).Return(ackcompare.NewDelta())
+
+	r, kc := reconcilerMocks(rmf)
+	statusWriter := &ctrlrtclientmock.StatusWriter{}
+	kc.On("Status").Return(statusWriter)
+	statusWriter.On("Patch", ctx, latest.obj, mock.AnythingOfType("*client.mergeFromPatch")).Return(nil)
+	return r, rm, latest, statusWriter
+}
+
+func TestReconcilerSync_ObservedGenerationAndLastSyncedTime(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.TODO()
+	res, _, _ := resourceMocks()
+	desired := &specBookResource{
+		AWSResource: res,
+		obj: &specBook{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "mybook",
+				Namespace:  "default",
+				Generation: 3,
+			},
+		},
+	}
+	r, rm, latest, statusWriter := syncedBookMocks(ctx, desired)
+
+	before := metav1.Now()
+	_, err := r.Sync(ctx, rm, desired)
+	require.Nil(err)
+	_, err = r.HandleReconcileError(ctx, desired, latest, err)
+	require.Nil(err)
+	statusWriter.AssertNumberOfCalls(t, "Patch", 1)
+
+	// Every condition records the generation of the Spec it was computed
+	// for, and the synced condition records when the resource was synced
+	require.NotEmpty(latest.obj.Status.Conditions)
+	for _, cond := range latest.obj.Status.Conditions {
+		require.Equal(int64(3), cond.ObservedGeneration)
+	}
+	synced := condition.Synced(latest)
+	require.NotNil(synced)
+	require.Equal(corev1.ConditionTrue, synced.Status)
+	require.NotNil(synced.LastSyncedTime)
+	require.False(synced.LastSyncedTime.Before(&before))
+}
+
+func TestReconcilerHandleReconcileError_ObservedGeneration(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.TODO()
+	res, _, _ := resourceMocks()
+	desired := &specBookResource{
+		AWSResource: res,
+		obj: &specBook{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "mybook",
+				Namespace:  "default",
+				Generation: 4,
+			},
+		},
+	}
+	r, _, latest, statusWriter := syncedBookMocks(ctx, desired)
+
+	// Statuses set outside of Sync, like the terminal condition of an early
+	// return, record the observed generation as well
+	msg := "deletion protected"
+	condition.SetTerminal(latest, corev1.ConditionTrue, &msg, nil)
+	_, err := r.HandleReconcileError(ctx, desired, latest, ackerr.Terminal)
+	require.Nil(err)
+	statusWriter.AssertNumberOfCalls(t, "Patch", 1)
+	terminal := condition.Terminal(latest)
+	require.NotNil(terminal)
+	require.Equal(int64(4), terminal.ObservedGeneration)
+}
+
+func TestReconcilerHandleReconcileError_SkipStatusPatch(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.TODO()
+	res, _, _ := resourceMocks()
+	desired := &specBookResource{
+		AWSResource: res,
+		obj: &specBook{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "mybook",
+				Namespace:  "default",
+				Generation: 1,
+			},
+		},
+	}
+	// reconcile sets the status of the CR, as read at the next loop
+	reconcile := func(desired *specBookResource) (*specBookResource, *ctrlrtclientmock.StatusWriter) {
+		r, rm, latest, statusWriter := syncedBookMocks(ctx, desired)
+		_, err := r.Sync(ctx, rm, desired)
+		require.Nil(err)
+		_, err = r.HandleReconcileError(ctx, desired, latest, err)
+		require.Nil(err)
+		return latest, statusWriter
+	}
+
+	latest, statusWriter := reconcile(desired)
+	statusWriter.AssertNumberOfCalls(t, "Patch", 1)
+
+	// Nothing changed since the last loop, so the status is not patched even
+	// though the resource was synced again, whatever the age of the last sync
+	// time
+	for _, age := range []time.Duration{10 * time.Second, time.Hour} {
+		lastSynced := metav1.NewTime(time.Now().Add(-age).Truncate(time.Second))
+		condition.Synced(latest).LastSyncedTime = &lastSynced
+		latest, statusWriter = reconcile(latest.DeepCopy().(*specBookResource))
+		statusWriter.AssertNumberOfCalls(t, "Patch", 0)
+	}
+
+	// Once the status changes, the patch carries the time of the sync
+	stale := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	condition.Synced(latest).LastSyncedTime = &stale
+	desired = latest.DeepCopy().(*specBookResource)
+	desired.obj.Status.Conditions = append(
+		desired.obj.Status.Conditions,
+		&ackv1alpha1.Condition{
+			Type:   ackv1alpha1.ConditionTypeRecoverable,
+			Status: corev1.ConditionTrue,
+		},
+	)
+	latest, statusWriter = reconcile(desired)
+	statusWriter.AssertNumberOfCalls(t, "Patch", 1)
+	require.True(condition.Synced(latest).LastSyncedTime.After(stale.Time))
+}
+
+// adoptedBookMock returns an AdoptedResource of the team-a namespace that
+// adopted the named fakeBook CR from the supplied AWS account
+func adoptedBookMock(name string, acctID string) ackv1alpha1.AdoptedResource {
+	owner := ackv1alpha1.AWSAccountID(acctID)
+	return ackv1alpha1.AdoptedResource{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "team-a",
+			Name:      "adopt-" + name,
+		},
+		Spec: ackv1alpha1.AdoptedResourceSpec{
+			Kubernetes: &ackv1alpha1.TargetKubernetesResource{
+				Group: "bookstore.services.k8s.aws",
+				Kind:  "fakeBook",
+				Metadata: &ackv1alpha1.PartialObjectMeta{
+					Name: name,
+				},
+			},
+			AWS: &ackv1alpha1.AWSIdentifiers{
+				NameOrID:       name,
+				OwnerAccountID: &owner,
+			},
+		},
+	}
+}
+
+func TestReconcilerGetOwnerAccountID(t *testing.T) {
+	require := require.New(t)
+
+	zapOptions := ctrlrtzap.Options{
+		Development: true,
+		Level:       zapcore.InfoLevel,
+	}
+	fakeLogger := ctrlrtzap.New(ctrlrtzap.UseFlagOptions(&zapOptions))
+	caches := ackrtcache.New(fakeLogger)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	caches.Namespaces.Run(k8sfake.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "team-a",
+			Annotations: map[string]string{
+				ackv1alpha1.AnnotationOwnerAccountID: "111111111111",
+			},
+		},
+	}), stopCh)
+	require.Eventually(func() bool {
+		_, ok := caches.Namespaces.GetOwnerAccountID("team-a")
+		return ok
+	}, time.Second, 10*time.Millisecond)
+
+	for _, tc := range []struct {
+		name           string
+		annotations    map[string]string
+		adopted        []ackv1alpha1.AdoptedResource
+		allowCrossAcct bool
+		expected       ackv1alpha1.AWSAccountID
+		notAllowed     bool
+	}{
+		{
+			name:     "namespace account",
+			expected: "111111111111",
+		},
+		{
+			name: "annotation matching the namespace account",
+			annotations: map[string]string{
+				ackv1alpha1.AnnotationOwnerAccountID: "111111111111",
+			},
+			expected: "111111111111",
+		},
+		{
+			name: "mismatched annotation",
+			annotations: map[string]string{
+				ackv1alpha1.AnnotationOwnerAccountID: "222222222222",
+			},
+			notAllowed: true,
+		},
+		{
+			name: "mismatched annotation on an adopted resource",
+			annotations: map[string]string{
+				ackv1alpha1.AnnotationOwnerAccountID: "222222222222",
+				ackv1alpha1.AnnotationAdopted:        "true",
+			},
+			notAllowed: true,
+		},
+		{
+			name: "mismatched annotation with cross-account adoption",
+			annotations: map[string]string{
+				ackv1alpha1.AnnotationOwnerAccountID: "222222222222",
+			},
+			allowCrossAcct: true,
+			notAllowed:     true,
+		},
+		{
+			name: "mismatched annotation on an adopted resource with cross-account adoption",
+			annotations: map[string]string{
+				ackv1alpha1.AnnotationOwnerAccountID: "222222222222",
+				ackv1alpha1.AnnotationAdopted:        "true",
+			},
+			adopted:        []ackv1alpha1.AdoptedResource{adoptedBookMock("mybook", "222222222222")},
+			allowCrossAcct: true,
+			expected:       "222222222222",
+		},
+		{
+			name: "forged adopted annotation with cross-account adoption",
+			annotations: map[string]string{
+				ackv1alpha1.AnnotationOwnerAccountID: "222222222222",
+				ackv1alpha1.AnnotationAdopted:        "true",
+			},
+			allowCrossAcct: true,
+			notAllowed:     true,
+		},
+		{
+			name: "annotation differing from the adopted account with cross-account adoption",
+			annotations: map[string]string{
+				ackv1alpha1.AnnotationOwnerAccountID: "222222222222",
+				ackv1alpha1.AnnotationAdopted:        "true",
+			},
+			adopted:        []ackv1alpha1.AdoptedResource{adoptedBookMock("mybook", "333333333333")},
+			allowCrossAcct: true,
+			notAllowed:     true,
+		},
+		{
+			name: "annotation matching the account of another adopted resource",
+			annotations: map[string]string{
+				ackv1alpha1.AnnotationOwnerAccountID: "222222222222",
+				ackv1alpha1.AnnotationAdopted:        "true",
+			},
+			adopted:        []ackv1alpha1.AdoptedResource{adoptedBookMock("otherbook", "222222222222")},
+			allowCrossAcct: true,
+			notAllowed:     true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ids := &ackmocks.AWSResourceIdentifiers{}
+			ids.On("OwnerAccountID").Return(nil)
+			res := &ackmocks.AWSResource{}
+			res.On("Identifiers").Return(ids)
+			res.On("MetaObject").Return(&metav1.ObjectMeta{
+				Namespace:   "team-a",
+				Name:        "mybook",
+				Annotations: tc.annotations,
+			})
+			rmf, _ := managedResourceManagerFactoryMocks(res, res)
+			kc := &ctrlrtclientmock.Client{}
+			kc.On(
+				"List", mock.Anything, mock.AnythingOfType("*v1alpha1.AdoptedResourceList"),
+				rtclient.InNamespace("team-a"),
+			).Return(nil).Run(func(args mock.Arguments) {
+				list := args.Get(1).(*ackv1alpha1.AdoptedResourceList)
+				list.Items = tc.adopted
+			})
+			r := ackrt.NewReconcilerWithClient(
+				&ackmocks.ServiceController{}, kc, rmf,
+				fakeLogger, ackcfg.Config{
+					AccountID:                 "000000000000",
+					AllowCrossAccountAdoption: tc.allowCrossAcct,
+				}, ackmetrics.NewMetrics("bookstore"), caches,
+			)
+
+			acctID, err := ackrt.GetOwnerAccountID(r, context.TODO(), res)
+			if tc.notAllowed {
+				require.True(errors.Is(err, ackerr.OwnerAccountIDNotAllowed))
+				return
+			}
+			require.Nil(err)
+			require.Equal(tc.expected, acctID)
+		})
+	}
+}
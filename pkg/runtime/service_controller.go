@@ -14,10 +14,14 @@
 package runtime
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"sync"
 
 	"github.com/go-logr/logr"
 	"github.com/prometheus/client_golang/prometheus"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
@@ -30,6 +34,8 @@ import (
 	ackmetrics "github.com/aws-controllers-k8s/runtime/pkg/metrics"
 	ackrtcache "github.com/aws-controllers-k8s/runtime/pkg/runtime/cache"
 	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	ackwebhook "github.com/aws-controllers-k8s/runtime/pkg/webhook"
+	ackcerts "github.com/aws-controllers-k8s/runtime/pkg/webhook/certs"
 )
 
 // VersionInfo contains information about the version of the runtime and
@@ -67,6 +73,14 @@ type serviceController struct {
 	// and is bound to the `controller-runtime.Manager` in
 	// `BindControllerManager`
 	adoptionReconciler acktypes.Reconciler
+	// bulkAdoptionReconciler contains a reconciler for the bulk adoption
+	// process and is bound to the `controller-runtime.Manager` in
+	// `BindControllerManager`
+	bulkAdoptionReconciler acktypes.Reconciler
+	// webhooks contains the webhooks that are set up with the
+	// `controller-runtime.Manager` in `BindControllerManager` when the webhook
+	// server is enabled
+	webhooks *ackwebhook.WebhookRegistry
 	// log refers to the logr.Logger object handling logging for the service
 	// controller
 	log logr.Logger
@@ -95,6 +109,22 @@ func (c *serviceController) GetResourceManagerFactories() map[string]acktypes.AW
 // GetAdoptedResourceInstalled returns whether the AdoptedResource CRD has been
 // installed into the cluster, and is accessible by the service controller.
 func (c *serviceController) GetAdoptedResourceInstalled(mgr ctrlrt.Manager) (bool, error) {
+	return c.isCoreResourceInstalled(mgr, "adoptedresources")
+}
+
+// GetBulkAdoptionInstalled returns whether the BulkAdoption CRD has been
+// installed into the cluster, and is accessible by the service controller.
+func (c *serviceController) GetBulkAdoptionInstalled(mgr ctrlrt.Manager) (bool, error) {
+	return c.isCoreResourceInstalled(mgr, "bulkadoptions")
+}
+
+// isCoreResourceInstalled returns whether the CRD of the supplied resource in
+// the services.k8s.aws API group has been installed into the cluster, and is
+// accessible by the service controller.
+func (c *serviceController) isCoreResourceInstalled(
+	mgr ctrlrt.Manager,
+	resource string,
+) (bool, error) {
 	clusterConfig := mgr.GetConfig()
 	clientSet, err := kubernetes.NewForConfig(clusterConfig)
 	if err != nil {
@@ -116,14 +146,14 @@ func (c *serviceController) GetAdoptedResourceInstalled(mgr ctrlrt.Manager) (boo
 		return false, err
 	}
 
-	adoptionResourceGVR := schema.GroupVersionResource{
+	gvr := schema.GroupVersionResource{
 		Group:    ackv1alpha1.GroupVersion.Group,
 		Version:  ackv1alpha1.GroupVersion.Version,
-		Resource: "adoptedresources",
+		Resource: resource,
 	}
 
 	// Ensure individual kind is supported
-	if _, err := restMapperClient.KindFor(adoptionResourceGVR); meta.IsNoMatchError(err) {
+	if _, err := restMapperClient.KindFor(gvr); meta.IsNoMatchError(err) {
 		return false, nil
 	}
 
@@ -171,15 +201,35 @@ func (c *serviceController) WithResourceManagerFactories(
 	return c
 }
 
+// WithWebhookRegistry sets the registry of the webhooks that are set up with
+// the controller manager when the webhook server is enabled. A nil registry
+// resets the service controller to the default webhook registry.
+func (c *serviceController) WithWebhookRegistry(
+	registry *ackwebhook.WebhookRegistry,
+) acktypes.ServiceController {
+	c.metaLock.Lock()
+	defer c.metaLock.Unlock()
+	if registry == nil {
+		registry = ackwebhook.DefaultWebhookRegistry()
+	}
+	c.webhooks = registry
+	return c
+}
+
 // BindControllerManager takes a `controller-runtime.Manager`, creates all the
 // AWSResourceReconcilers needed for the service and binds all of the
 // reconcilers within the service controller with that manager. The adoption
-// reconciler will only be started if the types have been registered in the
-// cluster.
+// and bulk adoption reconcilers will only be started if their types have been
+// registered in the cluster. The registered webhooks are only set up if the
+// webhook server is enabled.
 func (c *serviceController) BindControllerManager(mgr ctrlrt.Manager, cfg ackcfg.Config) error {
 	c.metaLock.Lock()
 	defer c.metaLock.Unlock()
 
+	if cfg.ControllerVersion == "" {
+		cfg.ControllerVersion = c.VersionInfo.GitVersion
+	}
+
 	cache := ackrtcache.New(c.log)
 	if cfg.WatchNamespace == "" {
 		clusterConfig := mgr.GetConfig()
@@ -212,9 +262,78 @@ func (c *serviceController) BindControllerManager(mgr ctrlrt.Manager, cfg ackcfg
 		c.adoptionReconciler = rec
 	}
 
+	bulkAdoptionInstalled, err := c.GetBulkAdoptionInstalled(mgr)
+	bulkAdoptionLogger := c.log.WithName("bulk-adoption")
+	if err != nil {
+		bulkAdoptionLogger.Error(err, "unable to determine if the BulkAdoption CRD is installed in the cluster")
+	} else if !bulkAdoptionInstalled {
+		bulkAdoptionLogger.Info("BulkAdoption CRD not installed. The bulk adoption reconciler will not be started")
+	} else {
+		rec := NewBulkAdoptionReconciler(c, bulkAdoptionLogger, cfg, c.metrics, cache)
+		if err := rec.BindControllerManager(mgr); err != nil {
+			return err
+		}
+		c.bulkAdoptionReconciler = rec
+	}
+
+	if cfg.EnableWebhookServer {
+		for _, wh := range c.webhooks.GetWebhooks() {
+			if err := wh.Setup(mgr); err != nil {
+				return err
+			}
+		}
+		if cfg.WebhookCertSecret != "" {
+			if err := c.bindWebhookCertRotator(mgr, cfg); err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
+// bindWebhookCertRotator provisions the serving certificate of the webhook
+// server in its certificate directory, and adds a Rotator rotating it and
+// injecting its CA bundle into the registered webhooks to the supplied
+// `controller-runtime.Manager`
+func (c *serviceController) bindWebhookCertRotator(
+	mgr ctrlrt.Manager,
+	cfg ackcfg.Config,
+) error {
+	clusterConfig := mgr.GetConfig()
+	clientSet, err := kubernetes.NewForConfig(clusterConfig)
+	if err != nil {
+		return err
+	}
+	crdClientSet, err := apiextensions.NewForConfig(clusterConfig)
+	if err != nil {
+		return err
+	}
+
+	server := mgr.GetWebhookServer()
+	if server.CertDir == "" {
+		server.CertDir = filepath.Join(
+			os.TempDir(), "k8s-webhook-server", "serving-certs",
+		)
+	}
+	bootstrapper := ackcerts.NewBootstrapper(c.log, clientSet, ackcerts.Options{
+		SecretNamespace:  cfg.WebhookNamespace,
+		SecretName:       cfg.WebhookCertSecret,
+		ServiceNamespace: cfg.WebhookNamespace,
+		ServiceName:      cfg.WebhookServiceName,
+		CertDir:          server.CertDir,
+	})
+	// The webhook server fails to start if its serving certificate is not in
+	// its certificate directory yet
+	if _, err := bootstrapper.Ensure(context.TODO()); err != nil {
+		return err
+	}
+	injector := ackcerts.NewInjector(
+		clientSet, crdClientSet, cfg.WebhookNamespace, cfg.WebhookServiceName,
+	)
+	return mgr.Add(ackcerts.NewRotator(c.log, bootstrapper, injector, c.webhooks, 0))
+}
+
 // NewServiceController returns a new serviceController instance
 func NewServiceController(
 	svcAlias string,
@@ -228,5 +347,6 @@ func NewServiceController(
 		ServiceEndpointsID: svcEndpointsID,
 		VersionInfo:        versionInfo,
 		metrics:            ackmetrics.NewMetrics(svcAlias),
+		webhooks:           ackwebhook.DefaultWebhookRegistry(),
 	}
 }
@@ -186,3 +186,16 @@ func TestServiceController(t *testing.T) {
 	require.True(foundfakeBookRecon)
 	rd.AssertCalled(t, "EmptyRuntimeObject")
 }
+
+func TestServiceController_NilWebhookRegistry(t *testing.T) {
+	require := require.New(t)
+
+	vi := ackrt.VersionInfo{GitVersion: "test-version"}
+	sc := ackrt.NewServiceController("bookstore", "bookstore.services.k8s.aws", "bookstore", vi)
+	sc.WithLogger(ctrlrtzap.New())
+
+	// A nil registry falls back to the default webhook registry
+	sc.WithWebhookRegistry(nil)
+	err := sc.BindControllerManager(&fakeManager{}, ackcfg.Config{EnableWebhookServer: true})
+	require.Nil(err)
+}
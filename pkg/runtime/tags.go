@@ -14,56 +14,89 @@
 package runtime
 
 import (
-	"strings"
-	"time"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	ackconfig "github.com/aws-controllers-k8s/runtime/pkg/config"
-	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	acktags "github.com/aws-controllers-k8s/runtime/pkg/tags"
 )
 
-// GetDefaultTags provides Default tags (key value pairs) for given resource
+// GetDefaultTags provides Default tags (key value pairs) for given resource.
+// The tag values are templates whose tokens, such as `%KUBERNETES_NAMESPACE%`
+// or `%K8S_LABEL:team%`, are expanded for the given resource. Tags that are
+// not valid AWS tags once expanded are left out, as well as all tags if the
+// configured tags cannot be parsed, which `Config.Validate` prevents.
 func GetDefaultTags(
 	config *ackconfig.Config,
 	object rtclient.Object,
 ) map[string]string {
-	if object == nil || config == nil || len(config.ResourceTags) == 0 {
+	if object == nil || config == nil {
 		return nil
 	}
-	var populatedTags = make(map[string]string)
-	for _, tagKeyVal := range config.ResourceTags {
-		keyVal := strings.Split(tagKeyVal, "=")
-		if keyVal == nil && len(keyVal) != 2 {
-			continue
-		}
-		key := strings.TrimSpace(keyVal[0])
-		val := strings.TrimSpace(keyVal[1])
-		if key == "" || val == "" {
-			continue
+	resourceTags, err := config.GetResourceTags()
+	if err != nil {
+		return nil
+	}
+	for key, val := range resourceTags {
+		if val == "" {
+			delete(resourceTags, key)
 		}
-		populatedValue := expandTagValue(&val, object)
-		populatedTags[key] = *populatedValue
 	}
+	populatedTags := expandTags(config, resourceTags, object)
 	if len(populatedTags) == 0 {
 		return nil
 	}
 	return populatedTags
 }
 
-func expandTagValue(
-	value *string,
-	obj rtclient.Object,
-) *string {
-	if value == nil || obj == nil {
+// expandTags returns the supplied tags with their values expanded for the
+// given resource, in the same way as the default tags. Tags that are not valid
+// AWS tags once expanded are left out.
+func expandTags(
+	config *ackconfig.Config,
+	tags map[string]string,
+	object rtclient.Object,
+) map[string]string {
+	if object == nil || config == nil || len(tags) == 0 {
 		return nil
 	}
-	var expandedValue string = ""
-	switch *value {
-	case "%UTCNOW%":
-		expandedValue = time.Now().UTC().String()
-	case "%KUBERNETES_NAMESPACE%":
-		expandedValue = obj.GetNamespace()
-	default:
-		expandedValue = *value
+	values := acktags.TemplateValues{
+		ControllerVersion: config.ControllerVersion,
+		ClusterName:       config.ClusterName,
+	}
+	var populatedTags = make(map[string]string, len(tags))
+	for key, val := range tags {
+		populatedValue := acktags.ExpandTemplate(val, object, values)
+		if acktags.Validate(key, populatedValue) != nil {
+			continue
+		}
+		populatedTags[key] = populatedValue
+	}
+	return populatedTags
+}
+
+// keepTimeBasedTags sets the supplied expanded default tags whose template
+// contains a time-based token, such as `%UTCNOW%`, to the value they already
+// have in the supplied resource tags, if any. Time-based tokens expand to a
+// different value at every reconciliation loop, which would otherwise never
+// match the resource's Spec and cause an update at every loop.
+func keepTimeBasedTags(
+	config *ackconfig.Config,
+	defaultTags map[string]string,
+	resourceTags map[string]string,
+) {
+	if config == nil || len(defaultTags) == 0 || len(resourceTags) == 0 {
+		return
+	}
+	templates, err := config.GetResourceTags()
+	if err != nil {
+		return
+	}
+	for key := range defaultTags {
+		if !acktags.IsTimeBased(templates[key]) {
+			continue
+		}
+		if val, ok := resourceTags[key]; ok && val != "" {
+			defaultTags[key] = val
+		}
 	}
-	return &expandedValue
 }
@@ -0,0 +1,79 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	ackcfg "github.com/aws-controllers-k8s/runtime/pkg/config"
+	ackrt "github.com/aws-controllers-k8s/runtime/pkg/runtime"
+)
+
+func TestGetDefaultTags(t *testing.T) {
+	assert := assert.New(t)
+	obj := &unstructured.Unstructured{}
+	obj.SetNamespace("books")
+	obj.SetName("my-book")
+	obj.SetLabels(map[string]string{"team": "payments"})
+	cfg := &ackcfg.Config{
+		ResourceTags: []string{
+			"services.k8s.aws/namespace=%KUBERNETES_NAMESPACE%",
+			"team=team-%K8S_LABEL:team%",
+			"cluster=%CLUSTER_NAME%",
+			"version=%CONTROLLER_VERSION%",
+			"aws:reserved=value",
+			"invalid=%K8S_LABEL:team%*",
+		},
+		ClusterName:       "prod",
+		ControllerVersion: "v0.1.0",
+	}
+
+	assert.Equal(map[string]string{
+		"services.k8s.aws/namespace": "books",
+		"team":                       "team-payments",
+		"cluster":                    "prod",
+		"version":                    "v0.1.0",
+	}, ackrt.GetDefaultTags(cfg, obj))
+	assert.Nil(ackrt.GetDefaultTags(&ackcfg.Config{}, obj))
+	assert.Nil(ackrt.GetDefaultTags(cfg, nil))
+}
+
+func TestGetDefaultTags_Parsing(t *testing.T) {
+	assert := assert.New(t)
+	obj := &unstructured.Unstructured{}
+	obj.SetNamespace("books")
+
+	// Values may contain equal signs, and structured tags override the
+	// key/value pairs with the same key
+	cfg := &ackcfg.Config{
+		ResourceTags: []string{
+			"query=a=b",
+			"team=payments",
+			"empty=",
+		},
+		StructuredResourceTags: `{"team": "checkout payments", "namespace": "%KUBERNETES_NAMESPACE%"}`,
+	}
+	assert.Equal(map[string]string{
+		"query":     "a=b",
+		"team":      "checkout payments",
+		"namespace": "books",
+	}, ackrt.GetDefaultTags(cfg, obj))
+
+	// Malformed tags do not panic
+	cfg = &ackcfg.Config{ResourceTags: []string{"team"}}
+	assert.Nil(ackrt.GetDefaultTags(cfg, obj))
+}
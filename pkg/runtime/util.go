@@ -14,11 +14,15 @@
 package runtime
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 
 	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
 	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
 )
 
@@ -42,6 +46,121 @@ func IsAdopted(res acktypes.AWSResource) bool {
 	return false
 }
 
+// GetAdoptionPolicy returns the adoption policy of the supplied AWSResource,
+// as set in its adoption-policy annotation. If the annotation is not set, the
+// policy is `adopt-only` for a resource annotated as adopted and `create-only`
+// otherwise. An error wrapping ackerr.InvalidAdoptionPolicy is returned if the
+// annotation value is not a supported adoption policy.
+func GetAdoptionPolicy(res acktypes.AWSResource) (ackv1alpha1.AdoptionPolicy, error) {
+	mo := res.MetaObject()
+	if mo == nil {
+		// Should never happen... if it does, it's buggy code.
+		panic("GetAdoptionPolicy received resource with nil RuntimeObject")
+	}
+	value, ok := mo.GetAnnotations()[ackv1alpha1.AnnotationAdoptionPolicy]
+	if !ok {
+		if IsAdopted(res) {
+			return ackv1alpha1.AdoptionPolicyAdoptOnly, nil
+		}
+		return ackv1alpha1.AdoptionPolicyCreateOnly, nil
+	}
+	policy := ackv1alpha1.AdoptionPolicy(strings.ToLower(strings.TrimSpace(value)))
+	switch policy {
+	case ackv1alpha1.AdoptionPolicyAdoptOrCreate,
+		ackv1alpha1.AdoptionPolicyAdoptOnly,
+		ackv1alpha1.AdoptionPolicyCreateOnly:
+		return policy, nil
+	}
+	return "", fmt.Errorf("%w: %q", ackerr.InvalidAdoptionPolicy, value)
+}
+
+// isObservingAdoptionDrift returns true if the supplied AWSResource was created
+// for an adopted AWS resource with the Observe drift policy, and its Spec has
+// not been modified since, i.e. its generation is not higher than the one
+// recorded at adoption. The differences between the Spec of such a resource
+// and the AWS resource must not be applied.
+func isObservingAdoptionDrift(res acktypes.AWSResource) bool {
+	mo := res.MetaObject()
+	if mo == nil {
+		// Should never happen... if it does, it's buggy code.
+		panic("isObservingAdoptionDrift received resource with nil RuntimeObject")
+	}
+	annotations := mo.GetAnnotations()
+	policy := annotations[ackv1alpha1.AnnotationAdoptionDriftPolicy]
+	if policy != string(ackv1alpha1.AdoptionDriftPolicyObserve) {
+		return false
+	}
+	observed, err := strconv.ParseInt(
+		annotations[ackv1alpha1.AnnotationAdoptionObservedGeneration], 10, 64,
+	)
+	return err == nil && mo.GetGeneration() <= observed
+}
+
+// getIgnoredPaths returns the field paths listed in the ignore-paths
+// annotation of the supplied AWSResource, whose differences must not be
+// applied to the AWS resource.
+func getIgnoredPaths(res acktypes.AWSResource) []string {
+	mo := res.MetaObject()
+	if mo == nil {
+		// Should never happen... if it does, it's buggy code.
+		panic("getIgnoredPaths received resource with nil RuntimeObject")
+	}
+	paths := []string{}
+	for _, p := range strings.Split(mo.GetAnnotations()[ackv1alpha1.AnnotationIgnorePaths], ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// isStrictDriftPolicy returns true if the supplied AWSResource was annotated
+// by the Kubernetes user to indicate that all the differences between its Spec
+// and the AWS resource must be applied, including at the fields that are not
+// set in the Spec.
+func isStrictDriftPolicy(res acktypes.AWSResource) bool {
+	mo := res.MetaObject()
+	if mo == nil {
+		// Should never happen... if it does, it's buggy code.
+		panic("isStrictDriftPolicy received resource with nil RuntimeObject")
+	}
+	policy := mo.GetAnnotations()[ackv1alpha1.AnnotationDriftPolicy]
+	return strings.EqualFold(
+		strings.TrimSpace(policy), string(ackv1alpha1.DriftPolicyStrict),
+	)
+}
+
+// isTerminalImmutableFieldPolicy returns true if the supplied AWSResource was
+// annotated by the Kubernetes user to indicate that the modification of its
+// immutable fields must set a Terminal condition.
+func isTerminalImmutableFieldPolicy(res acktypes.AWSResource) bool {
+	mo := res.MetaObject()
+	if mo == nil {
+		// Should never happen... if it does, it's buggy code.
+		panic("isTerminalImmutableFieldPolicy received resource with nil RuntimeObject")
+	}
+	policy := mo.GetAnnotations()[ackv1alpha1.AnnotationImmutableFieldPolicy]
+	return strings.EqualFold(
+		strings.TrimSpace(policy), string(ackv1alpha1.ImmutableFieldPolicyTerminal),
+	)
+}
+
+// withLastSyncedTimeOf returns a copy of the supplied AWSResource whose
+// ACK.ResourceSynced condition has the last sync time of the supplied previous
+// AWSResource, if both have such a condition
+func withLastSyncedTimeOf(
+	res acktypes.AWSResource,
+	prev acktypes.AWSResource,
+) acktypes.AWSResource {
+	res = res.DeepCopy()
+	synced := ackcondition.Synced(res)
+	prevSynced := ackcondition.Synced(prev)
+	if synced != nil && prevSynced != nil {
+		synced.LastSyncedTime = prevSynced.LastSyncedTime.DeepCopy()
+	}
+	return res
+}
+
 // IsSynced returns true if the supplied AWSResource's CR and associated
 // backend AWS service API resource are in sync.
 func IsSynced(res acktypes.AWSResource) bool {
@@ -52,3 +171,20 @@ func IsSynced(res acktypes.AWSResource) bool {
 	}
 	return false
 }
+
+// IsCascadeDeletion returns true if the supplied AWSResource's CR was
+// annotated by the Kubernetes user to indicate that the CRs referencing it
+// should be deleted along with it.
+func IsCascadeDeletion(res acktypes.AWSResource) bool {
+	mo := res.MetaObject()
+	if mo == nil {
+		// Should never happen... if it does, it's buggy code.
+		panic("IsCascadeDeletion received resource with nil RuntimeObject")
+	}
+	for k, v := range mo.GetAnnotations() {
+		if k == ackv1alpha1.AnnotationCascadeDeletion {
+			return strings.ToLower(v) == "true"
+		}
+	}
+	return false
+}
@@ -21,6 +21,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
 	ackrt "github.com/aws-controllers-k8s/runtime/pkg/runtime"
 
 	mocks "github.com/aws-controllers-k8s/runtime/mocks/pkg/types"
@@ -42,6 +43,46 @@ func TestIsAdopted(t *testing.T) {
 	require.False(ackrt.IsAdopted(res))
 }
 
+func TestGetAdoptionPolicy(t *testing.T) {
+	require := require.New(t)
+
+	res := &mocks.AWSResource{}
+	res.On("MetaObject").Return(&metav1.ObjectMeta{})
+	policy, err := ackrt.GetAdoptionPolicy(res)
+	require.Nil(err)
+	require.Equal(ackv1alpha1.AdoptionPolicyCreateOnly, policy)
+
+	res = &mocks.AWSResource{}
+	res.On("MetaObject").Return(&metav1.ObjectMeta{
+		Annotations: map[string]string{
+			ackv1alpha1.AnnotationAdopted: "true",
+		},
+	})
+	policy, err = ackrt.GetAdoptionPolicy(res)
+	require.Nil(err)
+	require.Equal(ackv1alpha1.AdoptionPolicyAdoptOnly, policy)
+
+	res = &mocks.AWSResource{}
+	res.On("MetaObject").Return(&metav1.ObjectMeta{
+		Annotations: map[string]string{
+			ackv1alpha1.AnnotationAdopted:        "true",
+			ackv1alpha1.AnnotationAdoptionPolicy: "Adopt-Or-Create",
+		},
+	})
+	policy, err = ackrt.GetAdoptionPolicy(res)
+	require.Nil(err)
+	require.Equal(ackv1alpha1.AdoptionPolicyAdoptOrCreate, policy)
+
+	res = &mocks.AWSResource{}
+	res.On("MetaObject").Return(&metav1.ObjectMeta{
+		Annotations: map[string]string{
+			ackv1alpha1.AnnotationAdoptionPolicy: "adopt-if-you-like",
+		},
+	})
+	_, err = ackrt.GetAdoptionPolicy(res)
+	require.ErrorIs(err, ackerr.InvalidAdoptionPolicy)
+}
+
 func TestIsSynced(t *testing.T) {
 	require := require.New(t)
 
@@ -0,0 +1,57 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tags
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// ParseKeyValue parses the supplied `key=value` string into a tag key and
+// value. The string is split on its first `=`, so that the value may itself
+// contain `=`. Leading and trailing spaces are trimmed from both the key and
+// the value.
+func ParseKeyValue(subject string) (string, string, error) {
+	parts := strings.SplitN(subject, "=", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf(
+			"expected a key=value pair, found %q", subject,
+		)
+	}
+	key := strings.TrimSpace(parts[0])
+	if key == "" {
+		return "", "", fmt.Errorf("missing tag key in %q", subject)
+	}
+	return key, strings.TrimSpace(parts[1]), nil
+}
+
+// ParseStructured parses the supplied YAML or JSON object into a map of tag
+// values keyed by tag key
+func ParseStructured(subject string) (map[string]string, error) {
+	tags := map[string]string{}
+	if err := yaml.UnmarshalStrict([]byte(subject), &tags); err != nil {
+		return nil, fmt.Errorf(
+			"expected a YAML or JSON object of tag keys to string values: %v",
+			err,
+		)
+	}
+	for key := range tags {
+		if strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("missing tag key in %q", subject)
+		}
+	}
+	return tags, nil
+}
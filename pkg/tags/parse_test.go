@@ -0,0 +1,139 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tags_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws-controllers-k8s/runtime/pkg/tags"
+)
+
+func TestParseKeyValue(t *testing.T) {
+	assert := assert.New(t)
+	testCases := []struct {
+		name      string
+		subject   string
+		wantKey   string
+		wantValue string
+		wantErr   bool
+	}{
+		{
+			name:      "key and value",
+			subject:   "team=payments",
+			wantKey:   "team",
+			wantValue: "payments",
+		},
+		{
+			name:      "value containing equal signs",
+			subject:   "query=a=b==c",
+			wantKey:   "query",
+			wantValue: "a=b==c",
+		},
+		{
+			name:      "spaces and unicode",
+			subject:   " équipe = paiements en ligne ",
+			wantKey:   "équipe",
+			wantValue: "paiements en ligne",
+		},
+		{
+			name:      "empty value",
+			subject:   "team=",
+			wantKey:   "team",
+			wantValue: "",
+		},
+		{
+			name:    "missing equal sign",
+			subject: "team",
+			wantErr: true,
+		},
+		{
+			name:    "missing key",
+			subject: " =payments",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, value, err := tags.ParseKeyValue(tc.subject)
+			if tc.wantErr {
+				assert.NotNil(err)
+				return
+			}
+			assert.Nil(err)
+			assert.Equal(tc.wantKey, key)
+			assert.Equal(tc.wantValue, value)
+		})
+	}
+}
+
+func TestParseStructured(t *testing.T) {
+	assert := assert.New(t)
+	testCases := []struct {
+		name    string
+		subject string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:    "JSON object",
+			subject: `{"team": "payments", "query": "a=b,c"}`,
+			want:    map[string]string{"team": "payments", "query": "a=b,c"},
+		},
+		{
+			name:    "YAML object",
+			subject: "team: payments\napp: team-%K8S_LABEL:app%\n",
+			want:    map[string]string{"team": "payments", "app": "team-%K8S_LABEL:app%"},
+		},
+		{
+			name:    "empty object",
+			subject: "{}",
+			want:    map[string]string{},
+		},
+		{
+			name:    "not an object",
+			subject: `["team=payments"]`,
+			wantErr: true,
+		},
+		{
+			name:    "number value",
+			subject: `{"cost-center": 1234}`,
+			want:    map[string]string{"cost-center": "1234"},
+		},
+		{
+			name:    "object value",
+			subject: `{"team": {"name": "payments"}}`,
+			wantErr: true,
+		},
+		{
+			name:    "empty key",
+			subject: `{"": "payments"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tags.ParseStructured(tc.subject)
+			if tc.wantErr {
+				assert.NotNil(err)
+				return
+			}
+			assert.Nil(err)
+			assert.Equal(tc.want, got)
+		})
+	}
+}
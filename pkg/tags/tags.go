@@ -0,0 +1,121 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package tags contains helpers for the AWS resource tags that ACK service
+// controllers set on the resources they manage.
+package tags
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MaxKeyLength is the maximum number of Unicode characters in a tag key
+	MaxKeyLength = 128
+	// MaxValueLength is the maximum number of Unicode characters in a tag
+	// value
+	MaxValueLength = 256
+	// ReservedKeyPrefix is the prefix of the tag keys reserved for use by AWS
+	ReservedKeyPrefix = "aws:"
+	// ACKKeyPrefix is the prefix of the tag keys reserved for use by the ACK
+	// service controllers. Only the controller's default tags may use it.
+	ACKKeyPrefix = "services.k8s.aws/"
+)
+
+var (
+	// allowedCharacters matches the strings made of the characters allowed in
+	// tag keys and values: letters, numbers, spaces and the `_.:/=+-@`
+	// symbols
+	allowedCharacters = regexp.MustCompile(`^[\p{L}\p{Z}\p{N}_.:/=+\-@]*$`)
+)
+
+// ValidateKey returns an error if the supplied string is not a valid AWS tag
+// key
+func ValidateKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("tag key must not be empty")
+	}
+	if utf8.RuneCountInString(key) > MaxKeyLength {
+		return fmt.Errorf(
+			"tag key %q exceeds %d characters", key, MaxKeyLength,
+		)
+	}
+	if strings.HasPrefix(strings.ToLower(key), ReservedKeyPrefix) {
+		return fmt.Errorf(
+			"tag key %q uses the reserved prefix %q", key, ReservedKeyPrefix,
+		)
+	}
+	if !allowedCharacters.MatchString(key) {
+		return fmt.Errorf("tag key %q contains invalid characters", key)
+	}
+	return nil
+}
+
+// ValidateValue returns an error if the supplied string is not a valid AWS
+// tag value
+func ValidateValue(value string) error {
+	if utf8.RuneCountInString(value) > MaxValueLength {
+		return fmt.Errorf(
+			"tag value %q exceeds %d characters", value, MaxValueLength,
+		)
+	}
+	if !allowedCharacters.MatchString(value) {
+		return fmt.Errorf("tag value %q contains invalid characters", value)
+	}
+	return nil
+}
+
+// Validate returns an error if the supplied key and value are not a valid
+// AWS tag
+func Validate(key string, value string) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+	return ValidateValue(value)
+}
+
+// IsACKKey returns true if the supplied tag key is reserved for use by the ACK
+// service controllers
+func IsACKKey(key string) bool {
+	return strings.HasPrefix(key, ACKKeyPrefix)
+}
+
+// Merge returns the union of the supplied controller, namespace and resource
+// tags. Resource tags take precedence over namespace tags, which take
+// precedence over controller tags. Namespace and resource tags whose key is
+// reserved for use by the ACK service controllers are left out.
+func Merge(
+	controllerTags map[string]string,
+	namespaceTags map[string]string,
+	resourceTags map[string]string,
+) map[string]string {
+	merged := make(
+		map[string]string,
+		len(controllerTags)+len(namespaceTags)+len(resourceTags),
+	)
+	for k, v := range controllerTags {
+		merged[k] = v
+	}
+	for _, tags := range []map[string]string{namespaceTags, resourceTags} {
+		for k, v := range tags {
+			if IsACKKey(k) {
+				continue
+			}
+			merged[k] = v
+		}
+	}
+	return merged
+}
@@ -0,0 +1,134 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tags_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws-controllers-k8s/runtime/pkg/tags"
+)
+
+func TestValidate(t *testing.T) {
+	assert := assert.New(t)
+	testCases := []struct {
+		name    string
+		key     string
+		value   string
+		wantErr bool
+	}{
+		{
+			name:  "valid tag",
+			key:   "team",
+			value: "payments",
+		},
+		{
+			name:  "valid symbols and spaces",
+			key:   "k8s.io/app_name:@+-=",
+			value: "my app 1.0",
+		},
+		{
+			name:  "unicode letters",
+			key:   "équipe",
+			value: "paiements",
+		},
+		{
+			name:  "empty value",
+			key:   "team",
+			value: "",
+		},
+		{
+			name:    "empty key",
+			key:     "",
+			value:   "payments",
+			wantErr: true,
+		},
+		{
+			name:    "reserved key prefix",
+			key:     "AWS:cloudformation:stack-name",
+			value:   "stack",
+			wantErr: true,
+		},
+		{
+			name:    "invalid key characters",
+			key:     "team#1",
+			value:   "payments",
+			wantErr: true,
+		},
+		{
+			name:    "invalid value characters",
+			key:     "team",
+			value:   "pay*ments",
+			wantErr: true,
+		},
+		{
+			name:    "key too long",
+			key:     strings.Repeat("k", tags.MaxKeyLength+1),
+			value:   "payments",
+			wantErr: true,
+		},
+		{
+			name:  "value of maximum length",
+			key:   "team",
+			value: strings.Repeat("é", tags.MaxValueLength),
+		},
+		{
+			name:    "value too long",
+			key:     "team",
+			value:   strings.Repeat("v", tags.MaxValueLength+1),
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tags.Validate(tc.key, tc.value)
+			if tc.wantErr {
+				assert.NotNil(err)
+			} else {
+				assert.Nil(err)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	assert := assert.New(t)
+	controllerTags := map[string]string{
+		"services.k8s.aws/namespace": "books",
+		"cost-center":                "0000",
+		"environment":                "prod",
+	}
+	namespaceTags := map[string]string{
+		"services.k8s.aws/namespace": "other",
+		"cost-center":                "1234",
+		"team":                       "payments",
+	}
+	resourceTags := map[string]string{
+		"services.k8s.aws/controller": "mine",
+		"team":                        "checkout",
+		"app":                         "bookstore",
+	}
+
+	assert.Equal(map[string]string{
+		"services.k8s.aws/namespace": "books",
+		"cost-center":                "1234",
+		"environment":                "prod",
+		"team":                       "checkout",
+		"app":                        "bookstore",
+	}, tags.Merge(controllerTags, namespaceTags, resourceTags))
+	assert.Equal(map[string]string{}, tags.Merge(nil, nil, nil))
+}
@@ -0,0 +1,143 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tags
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// TokenUTCNow expands to the current UTC time, in RFC 3339 format
+	TokenUTCNow = "UTCNOW"
+	// TokenKubernetesNamespace expands to the namespace of the custom resource
+	TokenKubernetesNamespace = "KUBERNETES_NAMESPACE"
+	// TokenKubernetesName expands to the name of the custom resource
+	TokenKubernetesName = "KUBERNETES_NAME"
+	// TokenKubernetesKind expands to the kind of the custom resource
+	TokenKubernetesKind = "KUBERNETES_KIND"
+	// TokenKubernetesUID expands to the UID of the custom resource
+	TokenKubernetesUID = "KUBERNETES_UID"
+	// TokenLabel expands to the value of the custom resource's label whose key
+	// follows the token name, e.g. `%K8S_LABEL:team%`
+	TokenLabel = "K8S_LABEL"
+	// TokenAnnotation expands to the value of the custom resource's annotation
+	// whose key follows the token name, e.g. `%K8S_ANNOTATION:owner%`
+	TokenAnnotation = "K8S_ANNOTATION"
+	// TokenControllerVersion expands to the version of the service controller
+	TokenControllerVersion = "CONTROLLER_VERSION"
+	// TokenClusterName expands to the name of the Kubernetes cluster
+	TokenClusterName = "CLUSTER_NAME"
+)
+
+var (
+	// templateToken matches the tokens of a tag value template, e.g.
+	// `%UTCNOW%` or `%K8S_LABEL:team%`. The first group is the token name and
+	// the second group, if any, is its argument.
+	templateToken = regexp.MustCompile(`%([A-Z0-9_]+)(?::([^%]+))?%`)
+)
+
+// TemplateValues contains the values of the tag value template tokens that
+// do not depend on the custom resource being tagged
+type TemplateValues struct {
+	// ControllerVersion is the version of the service controller
+	ControllerVersion string
+	// ClusterName is the name of the Kubernetes cluster
+	ClusterName string
+}
+
+// ExpandTemplate returns the supplied tag value template with its tokens
+// expanded for the supplied custom resource. Tokens may be embedded anywhere
+// in the template, e.g. `team-%K8S_LABEL:team%`. Labels and annotations that
+// are not set expand to an empty string, and unknown tokens are left as-is.
+func ExpandTemplate(
+	tmpl string,
+	obj rtclient.Object,
+	values TemplateValues,
+) string {
+	return templateToken.ReplaceAllStringFunc(tmpl, func(token string) string {
+		match := templateToken.FindStringSubmatch(token)
+		name, arg := match[1], match[2]
+		if !isKnownToken(name, arg) {
+			return token
+		}
+		switch name {
+		case TokenUTCNow:
+			return time.Now().UTC().Format(time.RFC3339)
+		case TokenKubernetesNamespace:
+			return obj.GetNamespace()
+		case TokenKubernetesName:
+			return obj.GetName()
+		case TokenKubernetesKind:
+			return obj.GetObjectKind().GroupVersionKind().Kind
+		case TokenKubernetesUID:
+			return string(obj.GetUID())
+		case TokenLabel:
+			return obj.GetLabels()[arg]
+		case TokenAnnotation:
+			return obj.GetAnnotations()[arg]
+		case TokenControllerVersion:
+			return values.ControllerVersion
+		case TokenClusterName:
+			return values.ClusterName
+		}
+		return token
+	})
+}
+
+// IsTimeBased returns true if the supplied tag value template contains a
+// token, such as `%UTCNOW%`, that expands to a different value every time the
+// template is expanded
+func IsTimeBased(tmpl string) bool {
+	for _, match := range templateToken.FindAllStringSubmatch(tmpl, -1) {
+		if match[1] == TokenUTCNow && match[2] == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTemplate returns an error if the supplied tag value template can
+// not expand to a valid AWS tag value, ignoring the length and content of the
+// values its tokens expand to
+func ValidateTemplate(tmpl string) error {
+	literal := templateToken.ReplaceAllStringFunc(tmpl, func(token string) string {
+		match := templateToken.FindStringSubmatch(token)
+		if isKnownToken(match[1], match[2]) {
+			return ""
+		}
+		return token
+	})
+	if err := ValidateValue(literal); err != nil {
+		return fmt.Errorf("tag value template %q: %v", tmpl, err)
+	}
+	return nil
+}
+
+// isKnownToken returns true if the supplied token name and argument form a
+// token that ExpandTemplate expands
+func isKnownToken(name string, arg string) bool {
+	switch name {
+	case TokenLabel, TokenAnnotation:
+		return arg != ""
+	case TokenUTCNow, TokenKubernetesNamespace, TokenKubernetesName,
+		TokenKubernetesKind, TokenKubernetesUID, TokenControllerVersion,
+		TokenClusterName:
+		return arg == ""
+	}
+	return false
+}
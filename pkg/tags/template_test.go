@@ -0,0 +1,136 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tags_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/aws-controllers-k8s/runtime/pkg/tags"
+)
+
+func TestExpandTemplate(t *testing.T) {
+	assert := assert.New(t)
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("bookstore.services.k8s.aws/v1alpha1")
+	obj.SetKind("Book")
+	obj.SetNamespace("books")
+	obj.SetName("my-book")
+	obj.SetUID("7a8f3d3e-0d4c-4d5e-9c4b-0e1d2f3a4b5c")
+	obj.SetLabels(map[string]string{
+		"team":                   "payments",
+		"app.kubernetes.io/name": "checkout",
+	})
+	obj.SetAnnotations(map[string]string{"owner": "someone"})
+	values := tags.TemplateValues{
+		ControllerVersion: "v0.1.0",
+		ClusterName:       "prod",
+	}
+
+	testCases := []struct {
+		name string
+		tmpl string
+		want string
+	}{
+		{
+			name: "no token",
+			tmpl: "payments",
+			want: "payments",
+		},
+		{
+			name: "namespace",
+			tmpl: "%KUBERNETES_NAMESPACE%",
+			want: "books",
+		},
+		{
+			name: "name, kind and UID",
+			tmpl: "%KUBERNETES_KIND%/%KUBERNETES_NAME%/%KUBERNETES_UID%",
+			want: "Book/my-book/7a8f3d3e-0d4c-4d5e-9c4b-0e1d2f3a4b5c",
+		},
+		{
+			name: "embedded label",
+			tmpl: "team-%K8S_LABEL:team%",
+			want: "team-payments",
+		},
+		{
+			name: "label key with prefix",
+			tmpl: "%K8S_LABEL:app.kubernetes.io/name%",
+			want: "checkout",
+		},
+		{
+			name: "missing label",
+			tmpl: "team-%K8S_LABEL:missing%",
+			want: "team-",
+		},
+		{
+			name: "annotation",
+			tmpl: "%K8S_ANNOTATION:owner%",
+			want: "someone",
+		},
+		{
+			name: "controller version and cluster name",
+			tmpl: "%CLUSTER_NAME%-%CONTROLLER_VERSION%",
+			want: "prod-v0.1.0",
+		},
+		{
+			name: "unknown token",
+			tmpl: "%UNKNOWN%-%K8S_LABEL%",
+			want: "%UNKNOWN%-%K8S_LABEL%",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(tc.want, tags.ExpandTemplate(tc.tmpl, obj, values))
+		})
+	}
+}
+
+func TestExpandTemplate_UTCNow(t *testing.T) {
+	assert := assert.New(t)
+	obj := &metav1.PartialObjectMetadata{}
+
+	expanded := tags.ExpandTemplate("created-%UTCNOW%", obj, tags.TemplateValues{})
+	assert.True(strings.HasPrefix(expanded, "created-"))
+	created, err := time.Parse(time.RFC3339, strings.TrimPrefix(expanded, "created-"))
+	assert.Nil(err)
+	assert.Equal(time.UTC, created.Location())
+	assert.Nil(tags.ValidateValue(expanded))
+}
+
+func TestIsTimeBased(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(tags.IsTimeBased("%UTCNOW%"))
+	assert.True(tags.IsTimeBased("created-%UTCNOW%"))
+	assert.False(tags.IsTimeBased("%KUBERNETES_NAME%"))
+	assert.False(tags.IsTimeBased("UTCNOW"))
+	assert.False(tags.IsTimeBased(""))
+}
+
+func TestValidateTemplate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(tags.ValidateTemplate("team-%K8S_LABEL:team%"))
+	assert.Nil(tags.ValidateTemplate("%UTCNOW%"))
+	assert.Nil(tags.ValidateTemplate(""))
+	assert.NotNil(tags.ValidateTemplate("%UNKNOWN%"))
+	assert.NotNil(tags.ValidateTemplate("%K8S_LABEL%"))
+	assert.NotNil(tags.ValidateTemplate("team*%KUBERNETES_NAME%"))
+}
@@ -49,3 +49,14 @@ type AWSResource interface {
 	// DeepCopy will return a copy of the resource
 	DeepCopy() AWSResource
 }
+
+// TaggableAWSResource is an optional interface implemented by AWSResources
+// whose Spec contains the tags of the backend AWS service API resource. The
+// ACK runtime uses it to set the default tags of the service controller and
+// of the CR's namespace on the resource.
+type TaggableAWSResource interface {
+	// GetTags returns the tags in the resource's Spec, keyed by tag key
+	GetTags() map[string]string
+	// SetTags replaces the tags in the resource's Spec
+	SetTags(map[string]string)
+}
@@ -59,3 +59,53 @@ type AWSResourceDescriptor interface {
 	// resource was not created from within ACK.
 	MarkAdopted(AWSResource)
 }
+
+// ResourceReference identifies a custom resource (CR) that is referenced by
+// another CR through one of its AWSResourceReferenceWrapper fields
+type ResourceReference struct {
+	// GroupKind is the API Group and Kind of the referenced CR
+	GroupKind metav1.GroupKind
+	// Namespace is the namespace of the referenced CR
+	Namespace string
+	// Name is the name of the referenced CR
+	Name string
+}
+
+// AWSResourceReferenceDescriptor is an optional interface implemented by
+// AWSResourceDescriptors describing CRs that contain
+// AWSResourceReferenceWrapper fields. It exposes the reference graph between
+// CRs, which the ACK runtime uses to hold the deletion of a referenced CR until
+// all of its dependents are gone.
+type AWSResourceReferenceDescriptor interface {
+	// EmptyRuntimeObjectList returns an empty list prototype that may be used
+	// to list all the CRs described by the descriptor
+	EmptyRuntimeObjectList() rtclient.ObjectList
+	// ReferencedResources returns the CRs referenced by name from the
+	// supplied AWSResource. References made by ARN are not returned.
+	ReferencedResources(AWSResource) []ResourceReference
+}
+
+// AWSResourceComparatorDescriptor is an optional interface implemented by
+// AWSResourceDescriptors describing CRs with fields that the AWS service
+// normalises, e.g. policy documents or case-insensitive enumerations. The ACK
+// runtime uses its Comparators to ignore the differences between the values of
+// such fields that are equivalent, instead of updating the AWS resource on
+// every reconciliation.
+type AWSResourceComparatorDescriptor interface {
+	// Comparators returns the registry of the Comparators of the fields of
+	// the CRs described by the descriptor
+	Comparators() *ackcompare.ComparatorRegistry
+}
+
+// AWSResourceImmutableFieldsDescriptor is an optional interface implemented by
+// AWSResourceDescriptors describing CRs with Spec fields that cannot be
+// modified once the AWS resource is created. The ACK runtime reports the
+// modification of such fields with an ACK.Advisory condition and leaves them
+// out of the Delta passed to AWSResourceManager.Update, instead of attempting
+// an update that the AWS service API would reject.
+type AWSResourceImmutableFieldsDescriptor interface {
+	// ImmutableFields returns the paths of the immutable fields of the CRs
+	// described by the descriptor, e.g. "Spec.Name". A "*" part matches any
+	// field name, list index or map key.
+	ImmutableFields() []string
+}
@@ -87,6 +87,19 @@ type AWSResourceManager interface {
 	IsSynced(context.Context, AWSResource) (bool, error)
 }
 
+// AWSResourceLister is an optional interface that AWSResourceManagers may
+// implement when the backend AWS service API is able to enumerate the
+// resources of the kind managed by the resource manager. It is used to resolve
+// the tag filters of a BulkAdoption.
+type AWSResourceLister interface {
+	// ListIdentifiers returns the identifiers of all the AWS resources of the
+	// managed kind whose tags match every one of the supplied filters
+	ListIdentifiers(
+		context.Context,
+		[]*ackv1alpha1.AWSTagFilter,
+	) ([]*ackv1alpha1.AWSIdentifiers, error)
+}
+
 // AWSResourceManagerFactory returns an AWSResourceManager that can be used to
 // manage AWS resources for a particular AWS account
 // TODO(jaypipes): Move AWSResourceManagerFactory into its own file
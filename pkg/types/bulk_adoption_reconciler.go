@@ -0,0 +1,41 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package types
+
+import (
+	"context"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+)
+
+// BulkAdoptionReconciler is responsible for reconciling a BulkAdoption, by
+// creating an AdoptedResource for every AWS resource matched by the
+// BulkAdoption.
+// It implements the upstream controller-runtime `Reconciler`
+// interface.
+type BulkAdoptionReconciler interface {
+	Reconciler
+	// Sync ensures that an AdoptedResource exists for every AWS resource
+	// matched by the supplied BulkAdoption and updates the progress counts
+	// in the BulkAdoption's Status
+	//
+	// NOTE: This is really only here for dependency injection purposes in
+	// unit testing in order to simplify test setups.
+	Sync(
+		context.Context,
+		AWSResourceDescriptor,
+		AWSResourceManager,
+		*ackv1alpha1.BulkAdoption,
+	) error
+}
@@ -22,6 +22,7 @@ import (
 
 	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
 	ackcfg "github.com/aws-controllers-k8s/runtime/pkg/config"
+	ackwebhook "github.com/aws-controllers-k8s/runtime/pkg/webhook"
 )
 
 // ServiceController wraps one or more reconcilers (for individual resources in
@@ -45,10 +46,17 @@ type ServiceController interface {
 	WithResourceManagerFactories(
 		[]AWSResourceManagerFactory,
 	) ServiceController
+	// WithWebhookRegistry sets the registry of the webhooks that are set up
+	// with the controller manager when the webhook server is enabled. By
+	// default, the webhooks registered with the package-level
+	// `pkg/webhook.RegisterWebhook` function are set up.
+	WithWebhookRegistry(*ackwebhook.WebhookRegistry) ServiceController
 
 	// BindControllerManager takes a `controller-runtime.Manager`, creates all
 	// the AWSResourceReconcilers needed for the service and binds all of the
-	// reconcilers within the service controller with that manager
+	// reconcilers within the service controller with that manager. The
+	// registered webhooks are set up with the manager when the webhook server
+	// is enabled.
 	BindControllerManager(
 		ctrlrt.Manager,
 		ackcfg.Config,
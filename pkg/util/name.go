@@ -0,0 +1,69 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package util
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+)
+
+const (
+	// maxDNS1123SubdomainLength is the maximum length of a DNS-1123
+	// subdomain, and therefore of the name of most Kubernetes objects
+	maxDNS1123SubdomainLength = 253
+)
+
+// ToDNS1123Subdomain converts the supplied string into a valid DNS-1123
+// subdomain, usable as the name of a Kubernetes object. Upper case letters are
+// lowered, any character that is not permitted is replaced by a dash and the
+// result is trimmed to start and end with an alphanumeric character and to not
+// exceed 253 characters.
+func ToDNS1123Subdomain(subject string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(subject) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '.':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+	res := trimNonAlphanumeric(b.String())
+	if len(res) > maxDNS1123SubdomainLength {
+		res = trimNonAlphanumeric(res[:maxDNS1123SubdomainLength])
+	}
+	return res
+}
+
+// NameFromARN returns the last segment of the resource portion of the
+// supplied ARN, which usually is the name or identifier of the AWS resource.
+// Returns an empty string if the supplied string is not a valid ARN.
+func NameFromARN(subject string) string {
+	parsed, err := arn.Parse(subject)
+	if err != nil {
+		return ""
+	}
+	resource := parsed.Resource
+	if i := strings.LastIndexAny(resource, "/:"); i >= 0 {
+		resource = resource[i+1:]
+	}
+	return resource
+}
+
+// trimNonAlphanumeric removes any leading and trailing dashes and dots from
+// the supplied string
+func trimNonAlphanumeric(subject string) string {
+	return strings.Trim(subject, "-.")
+}
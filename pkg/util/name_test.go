@@ -0,0 +1,92 @@
+package util_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws-controllers-k8s/runtime/pkg/util"
+)
+
+func TestToDNS1123Subdomain(t *testing.T) {
+	assert := assert.New(t)
+	testCases := []struct {
+		name    string
+		subject string
+		want    string
+	}{
+		{
+			name:    "empty string",
+			subject: "",
+			want:    "",
+		},
+		{
+			name:    "already valid",
+			subject: "my-bucket.logs",
+			want:    "my-bucket.logs",
+		},
+		{
+			name:    "upper case",
+			subject: "MyBucket",
+			want:    "mybucket",
+		},
+		{
+			name:    "invalid characters",
+			subject: "my_bucket:logs/2021",
+			want:    "my-bucket-logs-2021",
+		},
+		{
+			name:    "leading and trailing invalid characters",
+			subject: "_my-bucket_",
+			want:    "my-bucket",
+		},
+		{
+			name:    "too long",
+			subject: strings.Repeat("a", 252) + "-b",
+			want:    strings.Repeat("a", 252),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(tc.want, util.ToDNS1123Subdomain(tc.subject))
+		})
+	}
+}
+
+func TestNameFromARN(t *testing.T) {
+	assert := assert.New(t)
+	testCases := []struct {
+		name    string
+		subject string
+		want    string
+	}{
+		{
+			name:    "not an ARN",
+			subject: "my-bucket",
+			want:    "",
+		},
+		{
+			name:    "resource without type",
+			subject: "arn:aws:s3:::my-bucket",
+			want:    "my-bucket",
+		},
+		{
+			name:    "resource type and name separated by a slash",
+			subject: "arn:aws:ecr:us-west-2:012345678912:repository/team/my-repo",
+			want:    "my-repo",
+		},
+		{
+			name:    "resource type and name separated by a colon",
+			subject: "arn:aws:sns:us-west-2:012345678912:topic:my-topic",
+			want:    "my-topic",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(tc.want, util.NameFromARN(tc.subject))
+		})
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package admission contains helpers to build the validating and mutating
+// admission webhooks of the custom resources (CRs) managed by ACK service
+// controllers.
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	ctrladmission "sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	ackwebhook "github.com/aws-controllers-k8s/runtime/pkg/webhook"
+)
+
+// ValidateFunc validates a CR on admission. The supplied `latest` resource is
+// the CR as it was before the update, and is nil when the CR is created.
+type ValidateFunc func(
+	ctx context.Context,
+	desired acktypes.AWSResource,
+	latest acktypes.AWSResource,
+) error
+
+// DefaultFunc sets the default values of a CR on admission
+type DefaultFunc func(
+	ctx context.Context,
+	res acktypes.AWSResource,
+) error
+
+// validatingHandler is a controller-runtime admission handler that validates
+// CRs with a set of ValidateFuncs
+type validatingHandler struct {
+	rd         acktypes.AWSResourceDescriptor
+	validators []ValidateFunc
+}
+
+// Handle implements `controller-runtime/pkg/webhook/admission.Handler`. The
+// request is denied with the errors of all the validators that fail.
+func (h *validatingHandler) Handle(
+	ctx context.Context,
+	req ctrladmission.Request,
+) ctrladmission.Response {
+	var latest acktypes.AWSResource
+	switch req.Operation {
+	case admissionv1.Create:
+	case admissionv1.Update:
+		res, err := decode(h.rd, req.OldObject.Raw)
+		if err != nil {
+			return ctrladmission.Errored(http.StatusBadRequest, err)
+		}
+		latest = res
+	default:
+		return ctrladmission.Allowed("")
+	}
+	desired, err := decode(h.rd, req.Object.Raw)
+	if err != nil {
+		return ctrladmission.Errored(http.StatusBadRequest, err)
+	}
+
+	errs := []error{}
+	for _, validate := range h.validators {
+		if err := validate(ctx, desired, latest); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return ctrladmission.Denied(utilerrors.NewAggregate(errs).Error())
+	}
+	return ctrladmission.Allowed("")
+}
+
+// mutatingHandler is a controller-runtime admission handler that sets the
+// default values of CRs with a set of DefaultFuncs
+type mutatingHandler struct {
+	rd         acktypes.AWSResourceDescriptor
+	defaulters []DefaultFunc
+}
+
+// Handle implements `controller-runtime/pkg/webhook/admission.Handler`. The
+// response patches the CR with the changes of all the defaulters.
+func (h *mutatingHandler) Handle(
+	ctx context.Context,
+	req ctrladmission.Request,
+) ctrladmission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return ctrladmission.Allowed("")
+	}
+	res, err := decode(h.rd, req.Object.Raw)
+	if err != nil {
+		return ctrladmission.Errored(http.StatusBadRequest, err)
+	}
+	for _, setDefaults := range h.defaulters {
+		if err := setDefaults(ctx, res); err != nil {
+			return ctrladmission.Denied(err.Error())
+		}
+	}
+	defaulted, err := json.Marshal(res.RuntimeObject())
+	if err != nil {
+		return ctrladmission.Errored(http.StatusInternalServerError, err)
+	}
+	return ctrladmission.PatchResponseFromRaw(req.Object.Raw, defaulted)
+}
+
+// decode returns the AWSResource described by the supplied resource
+// descriptor for the supplied raw object
+func decode(
+	rd acktypes.AWSResourceDescriptor,
+	raw []byte,
+) (acktypes.AWSResource, error) {
+	obj := rd.EmptyRuntimeObject()
+	if err := json.Unmarshal(raw, obj); err != nil {
+		return nil, fmt.Errorf("unable to decode %s: %v", rd.GroupKind().Kind, err)
+	}
+	return rd.ResourceFromRuntimeObject(obj), nil
+}
+
+// NewValidatingHandler returns a controller-runtime admission handler that
+// validates the CRs described by the supplied resource descriptor with the
+// supplied validators on creation and update
+func NewValidatingHandler(
+	rd acktypes.AWSResourceDescriptor,
+	validators ...ValidateFunc,
+) ctrladmission.Handler {
+	return &validatingHandler{
+		rd:         rd,
+		validators: validators,
+	}
+}
+
+// NewMutatingHandler returns a controller-runtime admission handler that sets
+// the default values of the CRs described by the supplied resource descriptor
+// with the supplied defaulters on creation and update
+func NewMutatingHandler(
+	rd acktypes.AWSResourceDescriptor,
+	defaulters ...DefaultFunc,
+) ctrladmission.Handler {
+	return &mutatingHandler{
+		rd:         rd,
+		defaulters: defaulters,
+	}
+}
+
+// Path returns the path that the admission webhook of the supplied type
+// serves for the CRs of the supplied API version and resource descriptor,
+// e.g. `/validate-s3-services-k8s-aws-v1alpha1-bucket`
+func Path(
+	type_ ackwebhook.WebhookType,
+	apiVersion string,
+	rd acktypes.AWSResourceDescriptor,
+) string {
+	gk := rd.GroupKind()
+	prefix := "validate"
+	if type_ == ackwebhook.WebhookTypeMutating {
+		prefix = "mutate"
+	}
+	return "/" + strings.Join([]string{
+		prefix,
+		strings.ReplaceAll(gk.Group, ".", "-"),
+		apiVersion,
+		strings.ToLower(gk.Kind),
+	}, "-")
+}
+
+// NewValidatingWebhook returns a validating webhook for the CRs of the
+// supplied API version and resource descriptor, ready to be registered with
+// `pkg/webhook.WebhookRegistry`
+func NewValidatingWebhook(
+	apiVersion string,
+	rd acktypes.AWSResourceDescriptor,
+	validators ...ValidateFunc,
+) *ackwebhook.Webhook {
+	return newWebhook(
+		ackwebhook.WebhookTypeValidating, apiVersion, rd,
+		NewValidatingHandler(rd, validators...),
+	)
+}
+
+// NewMutatingWebhook returns a mutating webhook for the CRs of the supplied
+// API version and resource descriptor, ready to be registered with
+// `pkg/webhook.WebhookRegistry`
+func NewMutatingWebhook(
+	apiVersion string,
+	rd acktypes.AWSResourceDescriptor,
+	defaulters ...DefaultFunc,
+) *ackwebhook.Webhook {
+	return newWebhook(
+		ackwebhook.WebhookTypeMutating, apiVersion, rd,
+		NewMutatingHandler(rd, defaulters...),
+	)
+}
+
+// newWebhook returns a webhook of the supplied type that registers the
+// supplied handler within the webhook server of the manager
+func newWebhook(
+	type_ ackwebhook.WebhookType,
+	apiVersion string,
+	rd acktypes.AWSResourceDescriptor,
+	handler ctrladmission.Handler,
+) *ackwebhook.Webhook {
+	path := Path(type_, apiVersion, rd)
+	return ackwebhook.New(
+		apiVersion,
+		rd.GroupKind().Kind,
+		string(type_),
+		func(mgr ctrlrt.Manager) error {
+			mgr.GetWebhookServer().Register(
+				path, &ctrladmission.Webhook{Handler: handler},
+			)
+			return nil
+		},
+	)
+}
@@ -0,0 +1,227 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package admission_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sobj "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrladmission "sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	ackmocks "github.com/aws-controllers-k8s/runtime/mocks/pkg/types"
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	ackwebhook "github.com/aws-controllers-k8s/runtime/pkg/webhook"
+	"github.com/aws-controllers-k8s/runtime/pkg/webhook/admission"
+)
+
+// resourceFromObject returns an AWSResource backed by the supplied object
+func resourceFromObject(obj rtclient.Object) acktypes.AWSResource {
+	res := &ackmocks.AWSResource{}
+	res.On("RuntimeObject").Return(obj)
+	return res
+}
+
+// specOf returns the Spec of the unstructured object backing the supplied
+// AWSResource
+func specOf(res acktypes.AWSResource) map[string]interface{} {
+	spec, _ := res.RuntimeObject().(*k8sobj.Unstructured).Object["spec"].(map[string]interface{})
+	return spec
+}
+
+// bookDescriptor returns a resource descriptor for Book CRs backed by
+// unstructured objects, whose Delta compares the `spec.name` fields
+func bookDescriptor() *ackmocks.AWSResourceDescriptor {
+	rd := &ackmocks.AWSResourceDescriptor{}
+	rd.On("GroupKind").Return(&metav1.GroupKind{
+		Group: "bookstore.services.k8s.aws",
+		Kind:  "Book",
+	})
+	rd.On("EmptyRuntimeObject").Return(func() rtclient.Object {
+		return &k8sobj.Unstructured{}
+	})
+	rd.On("ResourceFromRuntimeObject", mock.Anything).Return(resourceFromObject)
+	rd.On("Delta", mock.Anything, mock.Anything).Return(
+		func(a, b acktypes.AWSResource) *ackcompare.Delta {
+			delta := ackcompare.NewDelta()
+			if specOf(a)["name"] != specOf(b)["name"] {
+				delta.Add("Spec.Name", specOf(a)["name"], specOf(b)["name"])
+			}
+			return delta
+		},
+	)
+	return rd
+}
+
+// bookRaw returns the raw JSON of a Book CR with the supplied Spec
+func bookRaw(spec map[string]interface{}) k8sruntime.RawExtension {
+	raw, _ := json.Marshal(map[string]interface{}{
+		"apiVersion": "bookstore.services.k8s.aws/v1alpha1",
+		"kind":       "Book",
+		"metadata": map[string]interface{}{
+			"name":      "my-book",
+			"namespace": "default",
+		},
+		"spec": spec,
+	})
+	return k8sruntime.RawExtension{Raw: raw}
+}
+
+func admissionRequest(
+	op admissionv1.Operation,
+	object k8sruntime.RawExtension,
+	oldObject k8sruntime.RawExtension,
+) ctrladmission.Request {
+	return ctrladmission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: op,
+			Object:    object,
+			OldObject: oldObject,
+		},
+	}
+}
+
+func TestValidatingHandler(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	rd := bookDescriptor()
+	requireAuthor := func(
+		ctx context.Context,
+		desired acktypes.AWSResource,
+		latest acktypes.AWSResource,
+	) error {
+		if specOf(desired)["author"] == nil {
+			return errors.New("author is required")
+		}
+		return nil
+	}
+	h := admission.NewValidatingHandler(
+		rd, requireAuthor, admission.ImmutableFields(rd, "Spec.Name"),
+	)
+
+	// Valid creation
+	resp := h.Handle(ctx, admissionRequest(
+		admissionv1.Create,
+		bookRaw(map[string]interface{}{"name": "a", "author": "b"}),
+		k8sruntime.RawExtension{},
+	))
+	require.True(resp.Allowed)
+
+	// Invalid creation
+	resp = h.Handle(ctx, admissionRequest(
+		admissionv1.Create,
+		bookRaw(map[string]interface{}{"name": "a"}),
+		k8sruntime.RawExtension{},
+	))
+	require.False(resp.Allowed)
+	require.Equal("author is required", string(resp.Result.Reason))
+
+	// The errors of all the failing validators are reported
+	resp = h.Handle(ctx, admissionRequest(
+		admissionv1.Update,
+		bookRaw(map[string]interface{}{"name": "b"}),
+		bookRaw(map[string]interface{}{"name": "a", "author": "b"}),
+	))
+	require.False(resp.Allowed)
+	require.Equal(
+		"[author is required, immutable fields cannot be modified: Spec.Name]",
+		string(resp.Result.Reason),
+	)
+
+	// Updates of mutable fields are allowed
+	resp = h.Handle(ctx, admissionRequest(
+		admissionv1.Update,
+		bookRaw(map[string]interface{}{"name": "a", "author": "c"}),
+		bookRaw(map[string]interface{}{"name": "a", "author": "b"}),
+	))
+	require.True(resp.Allowed)
+
+	// Deletions are not validated
+	resp = h.Handle(ctx, admissionRequest(
+		admissionv1.Delete,
+		k8sruntime.RawExtension{},
+		bookRaw(map[string]interface{}{"name": "a"}),
+	))
+	require.True(resp.Allowed)
+
+	// Objects that cannot be decoded are rejected
+	resp = h.Handle(ctx, admissionRequest(
+		admissionv1.Create,
+		k8sruntime.RawExtension{Raw: []byte("{")},
+		k8sruntime.RawExtension{},
+	))
+	require.False(resp.Allowed)
+}
+
+func TestMutatingHandler(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	rd := bookDescriptor()
+	defaultStorageClass := func(
+		ctx context.Context,
+		res acktypes.AWSResource,
+	) error {
+		spec := specOf(res)
+		if _, ok := spec["storageClass"]; !ok {
+			spec["storageClass"] = "STANDARD"
+		}
+		return nil
+	}
+	h := admission.NewMutatingHandler(rd, defaultStorageClass)
+
+	resp := h.Handle(ctx, admissionRequest(
+		admissionv1.Create,
+		bookRaw(map[string]interface{}{"name": "a"}),
+		k8sruntime.RawExtension{},
+	))
+	require.True(resp.Allowed)
+	require.Len(resp.Patches, 1)
+	require.Equal("add", resp.Patches[0].Operation)
+	require.Equal("/spec/storageClass", resp.Patches[0].Path)
+	require.Equal("STANDARD", resp.Patches[0].Value)
+
+	resp = h.Handle(ctx, admissionRequest(
+		admissionv1.Update,
+		bookRaw(map[string]interface{}{"name": "a", "storageClass": "GLACIER"}),
+		bookRaw(map[string]interface{}{"name": "a"}),
+	))
+	require.True(resp.Allowed)
+	require.Empty(resp.Patches)
+}
+
+func TestPath(t *testing.T) {
+	require := require.New(t)
+	rd := bookDescriptor()
+
+	require.Equal(
+		"/validate-bookstore-services-k8s-aws-v1alpha1-book",
+		admission.Path(ackwebhook.WebhookTypeValidating, "v1alpha1", rd),
+	)
+	require.Equal(
+		"/mutate-bookstore-services-k8s-aws-v1alpha1-book",
+		admission.Path(ackwebhook.WebhookTypeMutating, "v1alpha1", rd),
+	)
+
+	wh := admission.NewValidatingWebhook("v1alpha1", rd)
+	require.Equal("validating/Book/v1alpha1", wh.UID())
+}
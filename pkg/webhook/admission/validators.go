@@ -0,0 +1,150 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package admission
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+)
+
+// ImmutableFields returns a ValidateFunc that rejects the updates of a CR that
+// modify any of the supplied fields. The fields are identified by their path
+// in the `ackcompare.Delta` returned by the supplied resource descriptor, e.g.
+// `Spec.Name`.
+func ImmutableFields(
+	rd acktypes.AWSResourceDescriptor,
+	paths ...string,
+) ValidateFunc {
+	return func(
+		ctx context.Context,
+		desired acktypes.AWSResource,
+		latest acktypes.AWSResource,
+	) error {
+		if ackcompare.IsNil(latest) {
+			return nil
+		}
+		delta := rd.Delta(desired, latest)
+		modified := []string{}
+		for _, path := range paths {
+			if delta.DifferentAt(path) {
+				modified = append(modified, path)
+			}
+		}
+		if len(modified) > 0 {
+			return fmt.Errorf(
+				"immutable fields cannot be modified: %s",
+				strings.Join(modified, ", "),
+			)
+		}
+		return nil
+	}
+}
+
+// SecretReferencesExist returns a ValidateFunc that rejects a CR if any of the
+// SecretKeyReferences returned by the supplied function points to a Secret,
+// or a key within a Secret, that does not exist. Like the ACK service
+// controller, Secrets without a namespace are looked up in the `default`
+// namespace.
+func SecretReferencesExist(
+	reader client.Reader,
+	refsOf func(acktypes.AWSResource) []*ackv1alpha1.SecretKeyReference,
+) ValidateFunc {
+	return func(
+		ctx context.Context,
+		desired acktypes.AWSResource,
+		latest acktypes.AWSResource,
+	) error {
+		for _, ref := range refsOf(desired) {
+			if ref == nil {
+				continue
+			}
+			namespace := ref.Namespace
+			if namespace == "" {
+				namespace = "default"
+			}
+			var secret corev1.Secret
+			err := reader.Get(ctx, client.ObjectKey{
+				Namespace: namespace,
+				Name:      ref.Name,
+			}, &secret)
+			if apierrors.IsNotFound(err) {
+				return fmt.Errorf("secret %s/%s not found", namespace, ref.Name)
+			}
+			if err != nil {
+				return err
+			}
+			if secret.Type != corev1.SecretTypeOpaque {
+				return fmt.Errorf(
+					"secret %s/%s is of unsupported type %s",
+					namespace, ref.Name, secret.Type,
+				)
+			}
+			if _, ok := secret.Data[ref.Key]; !ok {
+				return fmt.Errorf(
+					"key %q not found in secret %s/%s",
+					ref.Key, namespace, ref.Name,
+				)
+			}
+		}
+		return nil
+	}
+}
+
+// ReferenceOrID returns a ValidateFunc that rejects a CR that sets both the
+// reference field and the identifier field at the supplied paths. The paths
+// are dot-separated JSON field names, e.g. `spec.kmsKeyRef` and
+// `spec.kmsKeyID`.
+func ReferenceOrID(
+	refPath string,
+	idPath string,
+) ValidateFunc {
+	return func(
+		ctx context.Context,
+		desired acktypes.AWSResource,
+		latest acktypes.AWSResource,
+	) error {
+		content, err := k8sruntime.DefaultUnstructuredConverter.ToUnstructured(
+			desired.RuntimeObject(),
+		)
+		if err != nil {
+			return err
+		}
+		if isSet(content, refPath) && isSet(content, idPath) {
+			return fmt.Errorf(
+				"only one of %s and %s may be set", refPath, idPath,
+			)
+		}
+		return nil
+	}
+}
+
+// isSet returns true if the supplied object has a non-null value at the
+// supplied dot-separated path
+func isSet(obj map[string]interface{}, path string) bool {
+	val, found, err := unstructured.NestedFieldNoCopy(
+		obj, strings.Split(path, ".")...,
+	)
+	return err == nil && found && val != nil
+}
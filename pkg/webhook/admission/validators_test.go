@@ -0,0 +1,129 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package admission_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sobj "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	"github.com/aws-controllers-k8s/runtime/pkg/webhook/admission"
+)
+
+func book(spec map[string]interface{}) acktypes.AWSResource {
+	return resourceFromObject(&k8sobj.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "bookstore.services.k8s.aws/v1alpha1",
+		"kind":       "Book",
+		"spec":       spec,
+	}})
+}
+
+func TestImmutableFields(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	validate := admission.ImmutableFields(bookDescriptor(), "Spec.Name")
+
+	// Creations are always valid
+	require.Nil(validate(ctx, book(map[string]interface{}{"name": "a"}), nil))
+	require.Nil(validate(
+		ctx,
+		book(map[string]interface{}{"name": "a"}),
+		book(map[string]interface{}{"name": "a"}),
+	))
+	require.NotNil(validate(
+		ctx,
+		book(map[string]interface{}{"name": "b"}),
+		book(map[string]interface{}{"name": "a"}),
+	))
+}
+
+func TestSecretReferencesExist(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	reader := fake.NewClientBuilder().WithObjects(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "creds"},
+			Type:       corev1.SecretTypeOpaque,
+			Data:       map[string][]byte{"password": []byte("secret")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "tls"},
+			Type:       corev1.SecretTypeTLS,
+			Data:       map[string][]byte{"tls.key": []byte("key")},
+		},
+	).Build()
+	ref := func(namespace, name, key string) *ackv1alpha1.SecretKeyReference {
+		return &ackv1alpha1.SecretKeyReference{
+			SecretReference: corev1.SecretReference{
+				Namespace: namespace,
+				Name:      name,
+			},
+			Key: key,
+		}
+	}
+	validate := func(refs ...*ackv1alpha1.SecretKeyReference) error {
+		return admission.SecretReferencesExist(
+			reader,
+			func(acktypes.AWSResource) []*ackv1alpha1.SecretKeyReference {
+				return refs
+			},
+		)(ctx, book(map[string]interface{}{}), nil)
+	}
+
+	require.Nil(validate())
+	require.Nil(validate(nil, ref("default", "creds", "password")))
+	// Secrets without a namespace are looked up in the default namespace
+	require.Nil(validate(ref("", "creds", "password")))
+	require.EqualError(
+		validate(ref("default", "missing", "password")),
+		"secret default/missing not found",
+	)
+	require.EqualError(
+		validate(ref("default", "creds", "username")),
+		`key "username" not found in secret default/creds`,
+	)
+	require.EqualError(
+		validate(ref("default", "tls", "tls.key")),
+		"secret default/tls is of unsupported type kubernetes.io/tls",
+	)
+}
+
+func TestReferenceOrID(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	validate := admission.ReferenceOrID("spec.kmsKeyRef", "spec.kmsKeyID")
+
+	require.Nil(validate(ctx, book(map[string]interface{}{}), nil))
+	require.Nil(validate(ctx, book(map[string]interface{}{
+		"kmsKeyID": "key",
+	}), nil))
+	require.Nil(validate(ctx, book(map[string]interface{}{
+		"kmsKeyRef": map[string]interface{}{"from": map[string]interface{}{"name": "key"}},
+		"kmsKeyID":  nil,
+	}), nil))
+	require.EqualError(
+		validate(ctx, book(map[string]interface{}{
+			"kmsKeyRef": map[string]interface{}{"from": map[string]interface{}{"name": "key"}},
+			"kmsKeyID":  "key",
+		}), nil),
+		"only one of spec.kmsKeyRef and spec.kmsKeyID may be set",
+	)
+}
@@ -0,0 +1,315 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// CACertKey is the key of the CA certificate in the certificates Secret
+	CACertKey = "ca.crt"
+	// CAKeyKey is the key of the CA private key in the certificates Secret
+	CAKeyKey = "ca.key"
+	// PreviousCACertKey is the key of the certificate of the CA replaced by
+	// the last CA rotation in the certificates Secret. It is kept in the CA
+	// bundle until it expires.
+	PreviousCACertKey = "ca.previous.crt"
+
+	// defaultCAValidity is the default validity of the self-signed CA
+	defaultCAValidity = 5 * 365 * 24 * time.Hour
+	// defaultCertValidity is the default validity of the serving certificate
+	defaultCertValidity = 365 * 24 * time.Hour
+	// defaultRotateBefore is the default duration before the expiry of a
+	// certificate at which the certificate is rotated
+	defaultRotateBefore = 30 * 24 * time.Hour
+)
+
+// Options configures the certificates managed by a Bootstrapper
+type Options struct {
+	// SecretNamespace is the namespace of the Secret storing the CA and the
+	// serving certificate
+	SecretNamespace string
+	// SecretName is the name of the Secret storing the CA and the serving
+	// certificate
+	SecretName string
+	// ServiceNamespace is the namespace of the Service exposing the webhook
+	// server
+	ServiceNamespace string
+	// ServiceName is the name of the Service exposing the webhook server
+	ServiceName string
+	// CertDir is the directory from which the webhook server reads its
+	// serving certificate. If empty, the certificate is only stored in the
+	// Secret.
+	CertDir string
+	// CAValidity is the validity of the self-signed CA. Defaults to 5 years.
+	CAValidity time.Duration
+	// CertValidity is the validity of the serving certificate. Defaults to 1
+	// year.
+	CertValidity time.Duration
+	// RotateBefore is the duration before the expiry of a certificate at
+	// which the certificate is rotated. Defaults to 30 days.
+	RotateBefore time.Duration
+}
+
+// Bootstrapper provisions a self-signed CA and a serving certificate for the
+// webhook server, stores them in a Secret and rotates them before they expire
+type Bootstrapper struct {
+	log       logr.Logger
+	clientSet kubernetes.Interface
+	opts      Options
+}
+
+// DNSNames returns the DNS names that the serving certificate is valid for
+func (b *Bootstrapper) DNSNames() []string {
+	svc := b.opts.ServiceName + "." + b.opts.ServiceNamespace + ".svc"
+	return []string{svc, svc + ".cluster.local"}
+}
+
+// Ensure ensures that the Secret contains a valid CA and a valid serving
+// certificate signed by the CA, rotating any of them that expires within the
+// rotation period, and writes the serving certificate to the certificate
+// directory. It returns the PEM encoded CA bundle, which contains the current
+// CA certificate followed by the previous one, if the CA was rotated and the
+// previous CA has not expired yet. Keeping the previous CA in the bundle lets
+// the API server trust the webhook server while the serving certificate
+// signed by the new CA is rolled out.
+//
+// Concurrent updates of the Secret, e.g. by another replica of the service
+// controller, are retried against the latest content of the Secret, so that
+// all the replicas end up serving the same certificates.
+func (b *Bootstrapper) Ensure(ctx context.Context) ([]byte, error) {
+	var certs *keyPairs
+	err := retry.OnError(retry.DefaultRetry, isWriteConflict, func() error {
+		var err error
+		certs, err = b.ensureSecret(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := b.writeCertDir(certs.cert); err != nil {
+		return nil, err
+	}
+	return certs.caBundle(), nil
+}
+
+// isWriteConflict returns true if the supplied error was returned by the API
+// server because the Secret was created or updated concurrently
+func isWriteConflict(err error) bool {
+	return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+}
+
+// ensureSecret reads the Secret, replaces the certificates that are missing,
+// invalid or about to expire and writes the Secret back if any of them was
+// replaced. It returns the certificates stored in the Secret.
+func (b *Bootstrapper) ensureSecret(ctx context.Context) (*keyPairs, error) {
+	secrets := b.clientSet.CoreV1().Secrets(b.opts.SecretNamespace)
+	secret, err := secrets.Get(ctx, b.opts.SecretName, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		secret = nil
+	}
+
+	certs, changed, err := b.ensureKeyPairs(secret)
+	if err != nil || !changed {
+		return certs, err
+	}
+	data := map[string][]byte{
+		CACertKey:               certs.ca.certPEM,
+		CAKeyKey:                certs.ca.keyPEM,
+		corev1.TLSCertKey:       certs.cert.certPEM,
+		corev1.TLSPrivateKeyKey: certs.cert.keyPEM,
+	}
+	if certs.previousCAPEM != nil {
+		data[PreviousCACertKey] = certs.previousCAPEM
+	}
+	if secret == nil {
+		_, err = secrets.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: b.opts.SecretNamespace,
+				Name:      b.opts.SecretName,
+			},
+			Type: corev1.SecretTypeTLS,
+			Data: data,
+		}, metav1.CreateOptions{})
+	} else {
+		secret = secret.DeepCopy()
+		secret.Data = data
+		_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	b.log.Info(
+		"rotated webhook certificates",
+		"expiry", certs.cert.cert.NotAfter,
+		"ca_expiry", certs.ca.cert.NotAfter,
+	)
+	return certs, nil
+}
+
+// keyPairs are the certificates stored in the certificates Secret
+type keyPairs struct {
+	ca   *keyPair
+	cert *keyPair
+	// previousCAPEM is the PEM encoded certificate of the CA replaced by ca,
+	// or nil if there is none or it has expired
+	previousCAPEM []byte
+}
+
+// caBundle returns the PEM encoded certificates of the CAs that the serving
+// certificate may be signed by
+func (kps *keyPairs) caBundle() []byte {
+	bundle := append([]byte{}, kps.ca.certPEM...)
+	return append(bundle, kps.previousCAPEM...)
+}
+
+// ensureKeyPairs returns the certificates stored in the supplied Secret, after
+// replacing the ones that are missing, invalid or about to expire and dropping
+// the previous CA once it has expired. The returned boolean is true if any of
+// them was replaced or dropped.
+func (b *Bootstrapper) ensureKeyPairs(
+	secret *corev1.Secret,
+) (*keyPairs, bool, error) {
+	now := time.Now()
+	dnsNames := b.DNSNames()
+	var data map[string][]byte
+	if secret != nil {
+		data = secret.Data
+	}
+
+	certs := &keyPairs{}
+	changed := false
+	if previousCAPEM, ok := data[PreviousCACertKey]; ok {
+		previousCA, err := parseCertificate(previousCAPEM)
+		if err == nil && now.Before(previousCA.NotAfter) {
+			certs.previousCAPEM = previousCAPEM
+		} else {
+			changed = true
+		}
+	}
+
+	ca, err := parseKeyPair(data[CACertKey], data[CAKeyKey])
+	if err == nil && !ca.cert.IsCA {
+		err = fmt.Errorf("%s is not a CA certificate", CACertKey)
+	}
+	if err != nil || ca.expiresWithin(now, b.opts.RotateBefore) {
+		if err == nil && !ca.expiresWithin(now, 0) {
+			// The replaced CA is kept in the CA bundle until it expires,
+			// so that the serving certificates it signed remain trusted
+			certs.previousCAPEM = ca.certPEM
+		}
+		if certs.ca, err = newCA(now, b.opts.CAValidity); err != nil {
+			return nil, false, err
+		}
+		certs.cert, err = newServingCert(certs.ca, dnsNames, now, b.opts.CertValidity)
+		return certs, true, err
+	}
+	certs.ca = ca
+
+	cert, err := parseKeyPair(data[corev1.TLSCertKey], data[corev1.TLSPrivateKeyKey])
+	if err == nil {
+		err = verifyServingCert(cert, ca, dnsNames, now)
+	}
+	if err != nil || cert.expiresWithin(now, b.opts.RotateBefore) {
+		certs.cert, err = newServingCert(ca, dnsNames, now, b.opts.CertValidity)
+		return certs, true, err
+	}
+	certs.cert = cert
+	return certs, changed, nil
+}
+
+// writeCertDir writes the supplied serving certificate to the certificate
+// directory, unless it already contains it
+func (b *Bootstrapper) writeCertDir(cert *keyPair) error {
+	if b.opts.CertDir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(b.opts.CertDir, 0o700); err != nil {
+		return err
+	}
+	// The key is written first, so that the webhook server, which watches
+	// the certificate file, never loads a certificate with a stale key
+	for _, f := range []struct {
+		name    string
+		content []byte
+	}{
+		{corev1.TLSPrivateKeyKey, cert.keyPEM},
+		{corev1.TLSCertKey, cert.certPEM},
+	} {
+		path := filepath.Join(b.opts.CertDir, f.name)
+		if current, err := os.ReadFile(path); err == nil && string(current) == string(f.content) {
+			continue
+		}
+		if err := writeFileAtomically(path, f.content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFileAtomically replaces the content of the file at the supplied path
+// by writing a temporary file and renaming it
+func writeFileAtomically(path string, content []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// NewBootstrapper returns a new Bootstrapper managing the certificates
+// described by the supplied options
+func NewBootstrapper(
+	log logr.Logger,
+	clientSet kubernetes.Interface,
+	opts Options,
+) *Bootstrapper {
+	if opts.CAValidity == 0 {
+		opts.CAValidity = defaultCAValidity
+	}
+	if opts.CertValidity == 0 {
+		opts.CertValidity = defaultCertValidity
+	}
+	if opts.RotateBefore == 0 {
+		opts.RotateBefore = defaultRotateBefore
+	}
+	return &Bootstrapper{
+		log:       log.WithName("webhook.certs"),
+		clientSet: clientSet,
+		opts:      opts,
+	}
+}
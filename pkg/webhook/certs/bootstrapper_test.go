@@ -0,0 +1,300 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certs_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	ctrlrtzap "sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/aws-controllers-k8s/runtime/pkg/webhook/certs"
+)
+
+const (
+	testNamespace  = "ack-system"
+	testSecretName = "ack-webhook-certs"
+)
+
+func parseCert(t *testing.T, certPEM []byte) *x509.Certificate {
+	block, _ := pem.Decode(certPEM)
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.Nil(t, err)
+	return cert
+}
+
+// newExpiredCA returns the PEM encoded certificate of a CA that has expired
+func newExpiredCA(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ack-webhook-ca"},
+		NotBefore:             time.Now().Add(-2 * time.Hour),
+		NotAfter:              time.Now().Add(-time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.Nil(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func getSecret(t *testing.T, k8sClient *k8sfake.Clientset) *corev1.Secret {
+	secret, err := k8sClient.CoreV1().Secrets(testNamespace).Get(
+		context.TODO(), testSecretName, metav1.GetOptions{},
+	)
+	require.Nil(t, err)
+	return secret
+}
+
+func testOptions(certDir string) certs.Options {
+	return certs.Options{
+		SecretNamespace:  testNamespace,
+		SecretName:       testSecretName,
+		ServiceNamespace: testNamespace,
+		ServiceName:      "ack-s3-webhook",
+		CertDir:          certDir,
+	}
+}
+
+func TestBootstrapper_Ensure(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	k8sClient := k8sfake.NewSimpleClientset()
+	certDir := filepath.Join(t.TempDir(), "certs")
+	b := certs.NewBootstrapper(ctrlrtzap.New(), k8sClient, testOptions(certDir))
+	require.Equal([]string{
+		"ack-s3-webhook.ack-system.svc",
+		"ack-s3-webhook.ack-system.svc.cluster.local",
+	}, b.DNSNames())
+
+	// The CA and serving certificate are provisioned
+	caBundle, err := b.Ensure(ctx)
+	require.Nil(err)
+	secret := getSecret(t, k8sClient)
+	require.Equal(corev1.SecretTypeTLS, secret.Type)
+	require.Equal(caBundle, secret.Data[certs.CACertKey])
+
+	roots := x509.NewCertPool()
+	require.True(roots.AppendCertsFromPEM(caBundle))
+	cert := parseCert(t, secret.Data[corev1.TLSCertKey])
+	for _, dnsName := range b.DNSNames() {
+		_, err := cert.Verify(x509.VerifyOptions{DNSName: dnsName, Roots: roots})
+		require.Nil(err)
+	}
+
+	// The serving certificate is written to the certificate directory
+	certFile, err := os.ReadFile(filepath.Join(certDir, corev1.TLSCertKey))
+	require.Nil(err)
+	require.Equal(secret.Data[corev1.TLSCertKey], certFile)
+	keyFile, err := os.ReadFile(filepath.Join(certDir, corev1.TLSPrivateKeyKey))
+	require.Nil(err)
+	require.Equal(secret.Data[corev1.TLSPrivateKeyKey], keyFile)
+
+	// Valid certificates are left untouched
+	again, err := b.Ensure(ctx)
+	require.Nil(err)
+	require.Equal(caBundle, again)
+	require.Equal(secret.Data, getSecret(t, k8sClient).Data)
+}
+
+func TestBootstrapper_Rotation(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	k8sClient := k8sfake.NewSimpleClientset()
+	caBundle, err := certs.NewBootstrapper(
+		ctrlrtzap.New(), k8sClient, testOptions(""),
+	).Ensure(ctx)
+	require.Nil(err)
+	secret := getSecret(t, k8sClient)
+
+	// A serving certificate expiring within the rotation period is rotated,
+	// while the CA is kept
+	opts := testOptions("")
+	opts.RotateBefore = 2 * 365 * 24 * time.Hour
+	rotated, err := certs.NewBootstrapper(ctrlrtzap.New(), k8sClient, opts).Ensure(ctx)
+	require.Nil(err)
+	require.Equal(caBundle, rotated)
+	rotatedSecret := getSecret(t, k8sClient)
+	require.NotEqual(secret.Data[corev1.TLSCertKey], rotatedSecret.Data[corev1.TLSCertKey])
+	require.NotEqual(secret.Data[corev1.TLSPrivateKeyKey], rotatedSecret.Data[corev1.TLSPrivateKeyKey])
+
+	// A CA expiring within the rotation period is rotated along with the
+	// serving certificate
+	opts.RotateBefore = 6 * 365 * 24 * time.Hour
+	rotated, err = certs.NewBootstrapper(ctrlrtzap.New(), k8sClient, opts).Ensure(ctx)
+	require.Nil(err)
+	rotatedSecret = getSecret(t, k8sClient)
+	require.NotEqual(caBundle, rotatedSecret.Data[certs.CACertKey])
+	require.Equal(caBundle, rotatedSecret.Data[certs.PreviousCACertKey])
+
+	// The rotated CA is kept in the CA bundle until it expires, so that both
+	// the previous and the new serving certificates are trusted
+	require.Equal(
+		append(rotatedSecret.Data[certs.CACertKey], caBundle...), rotated,
+	)
+	roots := x509.NewCertPool()
+	require.True(roots.AppendCertsFromPEM(rotated))
+	for _, certPEM := range [][]byte{
+		secret.Data[corev1.TLSCertKey],
+		rotatedSecret.Data[corev1.TLSCertKey],
+	} {
+		_, err := parseCert(t, certPEM).Verify(x509.VerifyOptions{
+			DNSName: "ack-s3-webhook.ack-system.svc",
+			Roots:   roots,
+		})
+		require.Nil(err)
+	}
+}
+
+func TestBootstrapper_ExpiredPreviousCA(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	k8sClient := k8sfake.NewSimpleClientset()
+	caBundle, err := certs.NewBootstrapper(
+		ctrlrtzap.New(), k8sClient, testOptions(""),
+	).Ensure(ctx)
+	require.Nil(err)
+
+	// A previous CA that has expired is dropped from the CA bundle and the
+	// Secret
+	secret := getSecret(t, k8sClient)
+	secret.Data[certs.PreviousCACertKey] = newExpiredCA(t)
+	_, err = k8sClient.CoreV1().Secrets(testNamespace).Update(
+		ctx, secret, metav1.UpdateOptions{},
+	)
+	require.Nil(err)
+
+	bundle, err := certs.NewBootstrapper(
+		ctrlrtzap.New(), k8sClient, testOptions(""),
+	).Ensure(ctx)
+	require.Nil(err)
+	require.Equal(caBundle, bundle)
+	require.NotContains(getSecret(t, k8sClient).Data, certs.PreviousCACertKey)
+}
+
+func TestBootstrapper_ConcurrentCreate(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+
+	// The certificates created by another replica of the service controller
+	other := k8sfake.NewSimpleClientset()
+	stored, err := certs.NewBootstrapper(
+		ctrlrtzap.New(), other, testOptions(""),
+	).Ensure(ctx)
+	require.Nil(err)
+	storedSecret := getSecret(t, other)
+	k8sClient := k8sfake.NewSimpleClientset()
+
+	// The other replica creates the Secret between the Get and the Create
+	created := false
+	k8sClient.PrependReactor("create", "secrets", func(
+		action k8stesting.Action,
+	) (bool, k8sruntime.Object, error) {
+		if created {
+			return false, nil, nil
+		}
+		created = true
+		require.Nil(k8sClient.Tracker().Add(storedSecret))
+		return true, nil, apierrors.NewAlreadyExists(
+			corev1.Resource("secrets"), testSecretName,
+		)
+	})
+
+	// The certificates stored by the other replica are used
+	caBundle, err := certs.NewBootstrapper(
+		ctrlrtzap.New(), k8sClient, testOptions(""),
+	).Ensure(ctx)
+	require.Nil(err)
+	require.True(created)
+	require.Equal(stored, caBundle)
+	require.Equal(storedSecret.Data, getSecret(t, k8sClient).Data)
+}
+
+func TestBootstrapper_ConcurrentUpdate(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	k8sClient := k8sfake.NewSimpleClientset()
+	_, err := certs.NewBootstrapper(
+		ctrlrtzap.New(), k8sClient, testOptions(""),
+	).Ensure(ctx)
+	require.Nil(err)
+
+	// The first update of the Secret conflicts with another replica
+	conflicts := 0
+	k8sClient.PrependReactor("update", "secrets", func(
+		action k8stesting.Action,
+	) (bool, k8sruntime.Object, error) {
+		if conflicts > 0 {
+			return false, nil, nil
+		}
+		conflicts++
+		return true, nil, apierrors.NewConflict(
+			corev1.Resource("secrets"), testSecretName, errors.New("conflict"),
+		)
+	})
+
+	opts := testOptions("")
+	opts.RotateBefore = 2 * 365 * 24 * time.Hour
+	caBundle, err := certs.NewBootstrapper(ctrlrtzap.New(), k8sClient, opts).Ensure(ctx)
+	require.Nil(err)
+	require.Equal(1, conflicts)
+	secret := getSecret(t, k8sClient)
+	require.Equal(secret.Data[certs.CACertKey], caBundle)
+}
+
+func TestBootstrapper_InvalidSecret(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	k8sClient := k8sfake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      testSecretName,
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			certs.CACertKey:         []byte("invalid"),
+			corev1.TLSCertKey:       []byte("invalid"),
+			corev1.TLSPrivateKeyKey: []byte("invalid"),
+		},
+	})
+
+	caBundle, err := certs.NewBootstrapper(
+		ctrlrtzap.New(), k8sClient, testOptions(""),
+	).Ensure(ctx)
+	require.Nil(err)
+	require.True(parseCert(t, caBundle).IsCA)
+	require.Equal(caBundle, getSecret(t, k8sClient).Data[certs.CACertKey])
+}
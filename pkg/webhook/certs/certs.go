@@ -0,0 +1,184 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package certs provisions and rotates the serving certificate of the webhook
+// server of ACK service controllers, signed by a self-signed certificate
+// authority (CA), and injects the CA bundle into the configuration of the
+// webhooks served by the controller.
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+const (
+	// caCommonName is the common name of the self-signed CA
+	caCommonName = "ack-webhook-ca"
+	// certificateBlockType is the PEM block type of certificates
+	certificateBlockType = "CERTIFICATE"
+	// privateKeyBlockType is the PEM block type of EC private keys
+	privateKeyBlockType = "EC PRIVATE KEY"
+)
+
+// keyPair is a certificate along with its private key
+type keyPair struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// expiresWithin returns true if the certificate of the key pair is not valid
+// anymore at the supplied time plus the supplied duration
+func (kp *keyPair) expiresWithin(now time.Time, d time.Duration) bool {
+	return !now.Add(d).Before(kp.cert.NotAfter)
+}
+
+// newCA returns a new self-signed CA valid for the supplied duration
+func newCA(now time.Time, validity time.Duration) (*keyPair, error) {
+	tmpl := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: caCommonName},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	return newKeyPair(tmpl, nil)
+}
+
+// newServingCert returns a new serving certificate for the supplied DNS names,
+// signed by the supplied CA and valid for the supplied duration
+func newServingCert(
+	ca *keyPair,
+	dnsNames []string,
+	now time.Time,
+	validity time.Duration,
+) (*keyPair, error) {
+	notAfter := now.Add(validity)
+	if notAfter.After(ca.cert.NotAfter) {
+		notAfter = ca.cert.NotAfter
+	}
+	tmpl := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:    dnsNames,
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	return newKeyPair(tmpl, ca)
+}
+
+// newKeyPair generates a private key and a certificate from the supplied
+// template, signed by the supplied CA or self-signed if the CA is nil
+func newKeyPair(tmpl *x509.Certificate, ca *keyPair) (*keyPair, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	tmpl.SerialNumber = serial
+
+	parent, signer := tmpl, key
+	if ca != nil {
+		parent, signer = ca.cert, ca.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, signer)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return &keyPair{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: certificateBlockType, Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: privateKeyBlockType, Bytes: keyDER}),
+	}, nil
+}
+
+// parseKeyPair returns the key pair encoded in the supplied PEM certificate
+// and EC private key
+func parseKeyPair(certPEM []byte, keyPEM []byte) (*keyPair, error) {
+	cert, err := parseCertificate(certPEM)
+	if err != nil {
+		return nil, err
+	}
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil || keyBlock.Type != privateKeyBlockType {
+		return nil, errors.New("invalid PEM private key")
+	}
+	key, err := x509.ParseECPrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if !key.PublicKey.Equal(cert.PublicKey) {
+		return nil, errors.New("private key does not match certificate")
+	}
+	return &keyPair{
+		cert:    cert,
+		key:     key,
+		certPEM: certPEM,
+		keyPEM:  keyPEM,
+	}, nil
+}
+
+// parseCertificate returns the certificate encoded in the supplied PEM block
+func parseCertificate(certPEM []byte) (*x509.Certificate, error) {
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil || certBlock.Type != certificateBlockType {
+		return nil, errors.New("invalid PEM certificate")
+	}
+	return x509.ParseCertificate(certBlock.Bytes)
+}
+
+// verifyServingCert returns an error if the supplied serving certificate is
+// not signed by the supplied CA or is not valid for all the supplied DNS names
+func verifyServingCert(
+	cert *keyPair,
+	ca *keyPair,
+	dnsNames []string,
+	now time.Time,
+) error {
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	for _, dnsName := range dnsNames {
+		if _, err := cert.cert.Verify(x509.VerifyOptions{
+			DNSName:     dnsName,
+			Roots:       roots,
+			CurrentTime: now,
+		}); err != nil {
+			return fmt.Errorf("serving certificate not valid for %s: %v", dnsName, err)
+		}
+	}
+	return nil
+}
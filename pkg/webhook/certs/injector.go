@@ -0,0 +1,204 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certs
+
+import (
+	"bytes"
+	"context"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	ackwebhook "github.com/aws-controllers-k8s/runtime/pkg/webhook"
+)
+
+// Injector keeps the CA bundle of the webhooks served by the webhook server
+// up to date. Validating and mutating webhooks are injected within the
+// ValidatingWebhookConfigurations and MutatingWebhookConfigurations, and
+// conversion webhooks within the CustomResourceDefinitions of their kind,
+// whenever their client configuration targets the webhook server's Service.
+type Injector struct {
+	clientSet        kubernetes.Interface
+	crdClientSet     apiextensionsclientset.Interface
+	serviceNamespace string
+	serviceName      string
+}
+
+// Inject sets the supplied CA bundle on the configuration of the supplied
+// webhooks. The CA bundle may contain several PEM encoded CA certificates, as
+// returned by Bootstrapper.Ensure while a rotated CA has not expired.
+func (i *Injector) Inject(
+	ctx context.Context,
+	caBundle []byte,
+	webhooks []*ackwebhook.Webhook,
+) error {
+	var validating, mutating bool
+	conversionKinds := map[string]bool{}
+	for _, wh := range webhooks {
+		switch ackwebhook.WebhookType(wh.Type) {
+		case ackwebhook.WebhookTypeValidating:
+			validating = true
+		case ackwebhook.WebhookTypeMutating:
+			mutating = true
+		case ackwebhook.WebhookTypeConversion:
+			conversionKinds[wh.CRDKind] = true
+		}
+	}
+	if validating {
+		if err := i.injectValidating(ctx, caBundle); err != nil {
+			return err
+		}
+	}
+	if mutating {
+		if err := i.injectMutating(ctx, caBundle); err != nil {
+			return err
+		}
+	}
+	if len(conversionKinds) > 0 {
+		if err := i.injectConversion(ctx, caBundle, conversionKinds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// injectValidating sets the supplied CA bundle on the validating webhooks
+// targeting the webhook server's Service
+func (i *Injector) injectValidating(ctx context.Context, caBundle []byte) error {
+	client := i.clientSet.AdmissionregistrationV1().ValidatingWebhookConfigurations()
+	configs, err := client.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, config := range configs.Items {
+		config := config
+		updated := false
+		for idx := range config.Webhooks {
+			if i.inject(&config.Webhooks[idx].ClientConfig, caBundle) {
+				updated = true
+			}
+		}
+		if !updated {
+			continue
+		}
+		if _, err := client.Update(ctx, &config, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// injectMutating sets the supplied CA bundle on the mutating webhooks
+// targeting the webhook server's Service
+func (i *Injector) injectMutating(ctx context.Context, caBundle []byte) error {
+	client := i.clientSet.AdmissionregistrationV1().MutatingWebhookConfigurations()
+	configs, err := client.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, config := range configs.Items {
+		config := config
+		updated := false
+		for idx := range config.Webhooks {
+			if i.inject(&config.Webhooks[idx].ClientConfig, caBundle) {
+				updated = true
+			}
+		}
+		if !updated {
+			continue
+		}
+		if _, err := client.Update(ctx, &config, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// injectConversion sets the supplied CA bundle on the conversion webhooks of
+// the CustomResourceDefinitions of the supplied kinds that target the webhook
+// server's Service
+func (i *Injector) injectConversion(
+	ctx context.Context,
+	caBundle []byte,
+	kinds map[string]bool,
+) error {
+	client := i.crdClientSet.ApiextensionsV1().CustomResourceDefinitions()
+	crds, err := client.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, crd := range crds.Items {
+		crd := crd
+		conversion := crd.Spec.Conversion
+		if !kinds[crd.Spec.Names.Kind] ||
+			conversion == nil ||
+			conversion.Strategy != apiextensionsv1.WebhookConverter ||
+			conversion.Webhook == nil ||
+			conversion.Webhook.ClientConfig == nil {
+			continue
+		}
+		clientConfig := conversion.Webhook.ClientConfig
+		if clientConfig.Service == nil ||
+			!i.targetsService(clientConfig.Service.Namespace, clientConfig.Service.Name) ||
+			bytes.Equal(clientConfig.CABundle, caBundle) {
+			continue
+		}
+		clientConfig.CABundle = caBundle
+		if _, err := client.Update(ctx, &crd, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inject sets the supplied CA bundle on the supplied admission webhook client
+// configuration if it targets the webhook server's Service. Returns true if
+// the client configuration was modified.
+func (i *Injector) inject(
+	clientConfig *admissionregistrationv1.WebhookClientConfig,
+	caBundle []byte,
+) bool {
+	if clientConfig.Service == nil ||
+		!i.targetsService(clientConfig.Service.Namespace, clientConfig.Service.Name) ||
+		bytes.Equal(clientConfig.CABundle, caBundle) {
+		return false
+	}
+	clientConfig.CABundle = caBundle
+	return true
+}
+
+// targetsService returns true if the supplied Service namespace and name are
+// the ones of the webhook server's Service
+func (i *Injector) targetsService(namespace string, name string) bool {
+	return namespace == i.serviceNamespace && name == i.serviceName
+}
+
+// NewInjector returns a new Injector for the webhooks targeting the Service
+// of the supplied namespace and name
+func NewInjector(
+	clientSet kubernetes.Interface,
+	crdClientSet apiextensionsclientset.Interface,
+	serviceNamespace string,
+	serviceName string,
+) *Injector {
+	return &Injector{
+		clientSet:        clientSet,
+		crdClientSet:     crdClientSet,
+		serviceNamespace: serviceNamespace,
+		serviceName:      serviceName,
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certs_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	ackwebhook "github.com/aws-controllers-k8s/runtime/pkg/webhook"
+	"github.com/aws-controllers-k8s/runtime/pkg/webhook/certs"
+)
+
+func admissionClientConfig(serviceName string) admissionregistrationv1.WebhookClientConfig {
+	return admissionregistrationv1.WebhookClientConfig{
+		Service: &admissionregistrationv1.ServiceReference{
+			Namespace: testNamespace,
+			Name:      serviceName,
+		},
+	}
+}
+
+func conversionCRD(name, kind, serviceName string) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: kind},
+			Conversion: &apiextensionsv1.CustomResourceConversion{
+				Strategy: apiextensionsv1.WebhookConverter,
+				Webhook: &apiextensionsv1.WebhookConversion{
+					ClientConfig: &apiextensionsv1.WebhookClientConfig{
+						Service: &apiextensionsv1.ServiceReference{
+							Namespace: testNamespace,
+							Name:      serviceName,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestInjector_Inject(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	k8sClient := k8sfake.NewSimpleClientset(
+		&admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "ack-s3-validating"},
+			Webhooks: []admissionregistrationv1.ValidatingWebhook{
+				{Name: "vbucket.s3.services.k8s.aws", ClientConfig: admissionClientConfig("ack-s3-webhook")},
+				{Name: "other.example.com", ClientConfig: admissionClientConfig("other-webhook")},
+			},
+		},
+		&admissionregistrationv1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "ack-s3-mutating"},
+			Webhooks: []admissionregistrationv1.MutatingWebhook{
+				{Name: "mbucket.s3.services.k8s.aws", ClientConfig: admissionClientConfig("ack-s3-webhook")},
+			},
+		},
+	)
+	crdClient := apiextensionsfake.NewSimpleClientset(
+		conversionCRD("buckets.s3.services.k8s.aws", "Bucket", "ack-s3-webhook"),
+		conversionCRD("policies.s3.services.k8s.aws", "Policy", "ack-s3-webhook"),
+		conversionCRD("buckets.example.com", "Bucket", "other-webhook"),
+	)
+	injector := certs.NewInjector(k8sClient, crdClient, testNamespace, "ack-s3-webhook")
+	caBundle := []byte("ca-bundle")
+
+	require.Nil(injector.Inject(ctx, caBundle, []*ackwebhook.Webhook{
+		ackwebhook.New("v1alpha1", "Bucket", string(ackwebhook.WebhookTypeValidating), nil),
+		ackwebhook.New("v1alpha1", "Bucket", string(ackwebhook.WebhookTypeConversion), nil),
+	}))
+
+	// Only the webhooks targeting the Service are injected
+	validating, err := k8sClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(
+		ctx, "ack-s3-validating", metav1.GetOptions{},
+	)
+	require.Nil(err)
+	require.Equal(caBundle, validating.Webhooks[0].ClientConfig.CABundle)
+	require.Nil(validating.Webhooks[1].ClientConfig.CABundle)
+
+	// No mutating webhook is registered
+	mutating, err := k8sClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(
+		ctx, "ack-s3-mutating", metav1.GetOptions{},
+	)
+	require.Nil(err)
+	require.Nil(mutating.Webhooks[0].ClientConfig.CABundle)
+
+	// Only the CRDs of the registered conversion webhooks are injected
+	crds := crdClient.ApiextensionsV1().CustomResourceDefinitions()
+	for name, want := range map[string][]byte{
+		"buckets.s3.services.k8s.aws":  caBundle,
+		"policies.s3.services.k8s.aws": nil,
+		"buckets.example.com":          nil,
+	} {
+		crd, err := crds.Get(ctx, name, metav1.GetOptions{})
+		require.Nil(err)
+		require.Equal(want, crd.Spec.Conversion.Webhook.ClientConfig.CABundle, name)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certs
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	ackwebhook "github.com/aws-controllers-k8s/runtime/pkg/webhook"
+)
+
+const (
+	// defaultCheckInterval is the default interval at which the certificates
+	// and CA bundles are checked
+	defaultCheckInterval = time.Hour
+)
+
+// Rotator periodically ensures that the webhook server certificates are valid
+// and that the webhooks registered in the supplied registry trust their CA.
+// It implements `controller-runtime/pkg/manager.Runnable` and is added to the
+// controller manager by the service controller.
+type Rotator struct {
+	log           logr.Logger
+	bootstrapper  *Bootstrapper
+	injector      *Injector
+	registry      *ackwebhook.WebhookRegistry
+	checkInterval time.Duration
+}
+
+// Start implements `controller-runtime/pkg/manager.Runnable`. It returns once
+// the supplied context is done.
+func (r *Rotator) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.checkInterval)
+	defer ticker.Stop()
+	for {
+		if err := r.Rotate(ctx); err != nil {
+			r.log.Error(err, "unable to rotate webhook certificates")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection implements
+// `controller-runtime/pkg/manager.LeaderElectionRunnable`. Every replica of
+// the controller serves webhooks and needs the serving certificate.
+func (r *Rotator) NeedLeaderElection() bool {
+	return false
+}
+
+// Rotate ensures the webhook server certificates are valid and injects their
+// CA bundle into the registered webhooks
+func (r *Rotator) Rotate(ctx context.Context) error {
+	caBundle, err := r.bootstrapper.Ensure(ctx)
+	if err != nil {
+		return err
+	}
+	return r.injector.Inject(ctx, caBundle, r.registry.GetWebhooks())
+}
+
+// NewRotator returns a new Rotator checking the certificates of the supplied
+// bootstrapper at the supplied interval, which defaults to an hour
+func NewRotator(
+	log logr.Logger,
+	bootstrapper *Bootstrapper,
+	injector *Injector,
+	registry *ackwebhook.WebhookRegistry,
+	checkInterval time.Duration,
+) *Rotator {
+	if checkInterval == 0 {
+		checkInterval = defaultCheckInterval
+	}
+	return &Rotator{
+		log:           log.WithName("webhook.certs"),
+		bootstrapper:  bootstrapper,
+		injector:      injector,
+		registry:      registry,
+		checkInterval: checkInterval,
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package conversion contains helpers to build the hub-and-spoke conversion
+// webhooks of the custom resources (CRs) managed by ACK service controllers
+// that are served in multiple API versions.
+//
+// The storage version of a CR, the hub, implements
+// `controller-runtime/pkg/conversion.Hub` and every other version, a spoke,
+// implements `controller-runtime/pkg/conversion.Convertible`, usually by
+// calling ConvertToHub and ConvertFromHub. Those convert the fields that
+// both versions have in common and preserve the fields of the hub that the
+// spoke does not have in the AnnotationConversionData annotation, so that
+// converting a CR to an older version and back is lossless.
+package conversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+)
+
+// MutateFunc modifies the JSON representation of a CR while it is converted
+// from one API version to another, e.g. to rename a field. The supplied
+// object has no `apiVersion` or `kind` field.
+type MutateFunc func(obj map[string]interface{}) error
+
+// RenameField returns a MutateFunc moving the value of the field at the
+// supplied dot-separated path, e.g. "spec.name", to another path
+func RenameField(from string, to string) MutateFunc {
+	return func(obj map[string]interface{}) error {
+		fromPath := strings.Split(from, ".")
+		value, found, err := unstructured.NestedFieldNoCopy(obj, fromPath...)
+		if err != nil || !found {
+			return err
+		}
+		unstructured.RemoveNestedField(obj, fromPath...)
+		return unstructured.SetNestedField(obj, value, strings.Split(to, ".")...)
+	}
+}
+
+// ConvertToHub converts the supplied spoke, i.e. a CR in an API version other
+// than the storage version, to the supplied hub. The fields both versions
+// have in common are copied as-is and the supplied MutateFuncs are then
+// applied. Finally, the fields of the hub preserved in the
+// AnnotationConversionData annotation of the spoke are restored, unless the
+// spoke has a value for them.
+func ConvertToHub(
+	spoke runtime.Object,
+	hub runtime.Object,
+	mutators ...MutateFunc,
+) error {
+	obj, err := toMap(spoke)
+	if err != nil {
+		return err
+	}
+	preserved, err := popConversionData(obj)
+	if err != nil {
+		return err
+	}
+	for _, mutate := range mutators {
+		if err := mutate(obj); err != nil {
+			return err
+		}
+	}
+	restoreFields(obj, preserved)
+	return fromMap(obj, hub)
+}
+
+// ConvertFromHub converts the supplied hub, i.e. a CR in the storage API
+// version, to the supplied spoke. The supplied MutateFuncs are applied to
+// the hub before the fields both versions have in common are copied as-is.
+// The fields of the hub that have no equivalent in the spoke are preserved
+// in the AnnotationConversionData annotation of the spoke. Lists are
+// preserved as a whole, and only if the spoke has no such field.
+func ConvertFromHub(
+	hub runtime.Object,
+	spoke runtime.Object,
+	mutators ...MutateFunc,
+) error {
+	obj, err := toMap(hub)
+	if err != nil {
+		return err
+	}
+	for _, mutate := range mutators {
+		if err := mutate(obj); err != nil {
+			return err
+		}
+	}
+	if err := fromMap(obj, spoke); err != nil {
+		return err
+	}
+	converted, err := toMap(spoke)
+	if err != nil {
+		return err
+	}
+	dropped := droppedFields(obj, converted)
+	if len(dropped) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(dropped)
+	if err != nil {
+		return err
+	}
+	metaObj, err := meta.Accessor(spoke)
+	if err != nil {
+		return err
+	}
+	annotations := metaObj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[ackv1alpha1.AnnotationConversionData] = string(data)
+	metaObj.SetAnnotations(annotations)
+	return nil
+}
+
+// toMap returns the JSON representation of the supplied object, without its
+// `apiVersion` and `kind` fields
+func toMap(obj runtime.Object) (map[string]interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	res := map[string]interface{}{}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+	delete(res, "apiVersion")
+	delete(res, "kind")
+	return res, nil
+}
+
+// fromMap decodes the supplied JSON representation into the supplied object.
+// Fields the object does not have are ignored.
+func fromMap(obj map[string]interface{}, into runtime.Object) error {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, into)
+}
+
+// popConversionData removes the AnnotationConversionData annotation from the
+// supplied JSON representation of a CR, and returns the fields it holds
+func popConversionData(obj map[string]interface{}) (map[string]interface{}, error) {
+	annotations, found, err := unstructured.NestedStringMap(obj, "metadata", "annotations")
+	if err != nil || !found {
+		return nil, err
+	}
+	data, found := annotations[ackv1alpha1.AnnotationConversionData]
+	if !found {
+		return nil, nil
+	}
+	delete(annotations, ackv1alpha1.AnnotationConversionData)
+	if len(annotations) == 0 {
+		unstructured.RemoveNestedField(obj, "metadata", "annotations")
+	} else if err := unstructured.SetNestedStringMap(obj, annotations, "metadata", "annotations"); err != nil {
+		return nil, err
+	}
+	preserved := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(data), &preserved); err != nil {
+		return nil, fmt.Errorf(
+			"invalid %s annotation: %v", ackv1alpha1.AnnotationConversionData, err,
+		)
+	}
+	return preserved, nil
+}
+
+// droppedFields returns the fields of the supplied source object that are
+// missing from the supplied destination object
+func droppedFields(src, dst map[string]interface{}) map[string]interface{} {
+	res := map[string]interface{}{}
+	for key, srcValue := range src {
+		dstValue, found := dst[key]
+		if !found {
+			res[key] = srcValue
+			continue
+		}
+		srcMap, srcIsMap := srcValue.(map[string]interface{})
+		dstMap, dstIsMap := dstValue.(map[string]interface{})
+		if !srcIsMap || !dstIsMap {
+			continue
+		}
+		if dropped := droppedFields(srcMap, dstMap); len(dropped) > 0 {
+			res[key] = dropped
+		}
+	}
+	return res
+}
+
+// restoreFields sets the supplied preserved fields on the supplied object,
+// unless it already has a value for them
+func restoreFields(obj, preserved map[string]interface{}) {
+	for key, value := range preserved {
+		current, found := obj[key]
+		if !found {
+			obj[key] = value
+			continue
+		}
+		currentMap, currentIsMap := current.(map[string]interface{})
+		valueMap, valueIsMap := value.(map[string]interface{})
+		if currentIsMap && valueIsMap {
+			restoreFields(currentMap, valueMap)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package conversion_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackmetrics "github.com/aws-controllers-k8s/runtime/pkg/metrics"
+	ackconversion "github.com/aws-controllers-k8s/runtime/pkg/webhook/conversion"
+)
+
+var (
+	hubGV   = schema.GroupVersion{Group: "test.services.k8s.aws", Version: "v1beta1"}
+	spokeGV = schema.GroupVersion{Group: "test.services.k8s.aws", Version: "v1alpha1"}
+)
+
+type hubSpec struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Config      struct {
+		Size    int64 `json:"size,omitempty"`
+		Encrypt bool  `json:"encrypt,omitempty"`
+	} `json:"config"`
+}
+
+// widgetHub is the storage version of the Widget kind
+type widgetHub struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              hubSpec `json:"spec"`
+}
+
+func (*widgetHub) Hub() {}
+
+func (w *widgetHub) DeepCopyObject() runtime.Object {
+	res := &widgetHub{}
+	deepCopy(w, res)
+	return res
+}
+
+type spokeSpec struct {
+	Title  string `json:"title"`
+	Config struct {
+		Size int64 `json:"size,omitempty"`
+	} `json:"config"`
+}
+
+// widgetSpoke is an older version of the Widget kind, in which the name is
+// called a title and the description, tags and encryption don't exist
+type widgetSpoke struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              spokeSpec `json:"spec"`
+}
+
+func (w *widgetSpoke) ConvertTo(dst conversion.Hub) error {
+	if w.Spec.Title == "invalid" {
+		return errors.New("invalid title")
+	}
+	return ackconversion.ConvertToHub(
+		w, dst, ackconversion.RenameField("spec.title", "spec.name"),
+	)
+}
+
+func (w *widgetSpoke) ConvertFrom(src conversion.Hub) error {
+	return ackconversion.ConvertFromHub(
+		src, w, ackconversion.RenameField("spec.name", "spec.title"),
+	)
+}
+
+func (w *widgetSpoke) DeepCopyObject() runtime.Object {
+	res := &widgetSpoke{}
+	deepCopy(w, res)
+	return res
+}
+
+// lossySpoke is a spoke whose conversion functions don't preserve the
+// fields of the hub it doesn't have
+type lossySpoke struct {
+	widgetSpoke
+}
+
+func (w *lossySpoke) ConvertFrom(src conversion.Hub) error {
+	hub := src.(*widgetHub)
+	w.ObjectMeta = hub.ObjectMeta
+	w.Spec.Title = hub.Spec.Name
+	w.Spec.Config.Size = hub.Spec.Config.Size
+	return nil
+}
+
+func (w *lossySpoke) DeepCopyObject() runtime.Object {
+	res := &lossySpoke{}
+	deepCopy(w, res)
+	return res
+}
+
+func deepCopy(src, dst interface{}) {
+	data, err := json.Marshal(src)
+	if err != nil {
+		panic(err)
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		panic(err)
+	}
+}
+
+func newHub() *widgetHub {
+	hub := &widgetHub{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: hubGV.String(),
+			Kind:       "Widget",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "my-widget",
+			Namespace:   "default",
+			Annotations: map[string]string{"team": "storage"},
+		},
+		Spec: hubSpec{
+			Name:        "my-widget",
+			Description: "my widget",
+			Tags:        map[string]string{"env": "prod"},
+		},
+	}
+	hub.Spec.Config.Size = 42
+	hub.Spec.Config.Encrypt = true
+	return hub
+}
+
+func TestConvertFromHub(t *testing.T) {
+	require := require.New(t)
+	spoke := &widgetSpoke{}
+	require.Nil(spoke.ConvertFrom(newHub()))
+
+	require.Equal("my-widget", spoke.Spec.Title)
+	require.Equal(int64(42), spoke.Spec.Config.Size)
+	require.Equal("storage", spoke.Annotations["team"])
+	require.JSONEq(
+		`{"spec": {"description": "my widget", "tags": {"env": "prod"}, "config": {"encrypt": true}}}`,
+		spoke.Annotations[ackv1alpha1.AnnotationConversionData],
+	)
+}
+
+func TestConvertToHub(t *testing.T) {
+	require := require.New(t)
+	spoke := &widgetSpoke{}
+	require.Nil(spoke.ConvertFrom(newHub()))
+
+	// The values of the spoke take precedence over the preserved fields, and
+	// the annotation is not carried over to the hub
+	spoke.Spec.Title = "new-name"
+	hub := &widgetHub{}
+	require.Nil(spoke.ConvertTo(hub))
+	want := newHub()
+	want.TypeMeta = metav1.TypeMeta{}
+	want.Spec.Name = "new-name"
+	require.Equal(want, hub)
+
+	// Invalid preserved fields are reported
+	spoke.Annotations[ackv1alpha1.AnnotationConversionData] = "{"
+	require.NotNil(spoke.ConvertTo(&widgetHub{}))
+}
+
+func TestCheckRoundTrip(t *testing.T) {
+	require := require.New(t)
+	spoke := &widgetSpoke{}
+	spoke.Spec.Title = "my-widget"
+	spoke.Spec.Config.Size = 42
+
+	require.Nil(ackconversion.CheckHubRoundTrip(newHub(), &widgetSpoke{}))
+	require.Nil(ackconversion.CheckSpokeRoundTrip(spoke, &widgetHub{}))
+
+	err := ackconversion.CheckHubRoundTrip(newHub(), &lossySpoke{})
+	require.NotNil(err)
+	require.Contains(err.Error(), "changed after a round trip conversion")
+
+	spoke.Spec.Title = "invalid"
+	require.NotNil(ackconversion.CheckSpokeRoundTrip(spoke, &widgetHub{}))
+}
+
+func convert(
+	t *testing.T,
+	h http.Handler,
+	desiredAPIVersion string,
+	obj runtime.Object,
+) *apiextensionsv1.ConversionReview {
+	raw, err := json.Marshal(obj)
+	require.Nil(t, err)
+	body, err := json.Marshal(&apiextensionsv1.ConversionReview{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiextensionsv1.SchemeGroupVersion.String(),
+			Kind:       "ConversionReview",
+		},
+		Request: &apiextensionsv1.ConversionRequest{
+			UID:               types.UID("uid"),
+			DesiredAPIVersion: desiredAPIVersion,
+			Objects:           []runtime.RawExtension{{Raw: raw}},
+		},
+	})
+	require.Nil(t, err)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, ackconversion.Path, bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	review := &apiextensionsv1.ConversionReview{}
+	require.Nil(t, json.Unmarshal(rec.Body.Bytes(), review))
+	return review
+}
+
+func TestHandler(t *testing.T) {
+	require := require.New(t)
+	scheme := runtime.NewScheme()
+	scheme.AddKnownTypeWithName(hubGV.WithKind("Widget"), &widgetHub{})
+	scheme.AddKnownTypeWithName(spokeGV.WithKind("Widget"), &widgetSpoke{})
+	metrics := ackmetrics.NewMetrics("test")
+	h, err := ackconversion.NewHandler(scheme, metrics)
+	require.Nil(err)
+
+	errorCount := func() float64 {
+		for _, c := range metrics.Collectors() {
+			if vec, ok := c.(*prometheus.CounterVec); ok {
+				counter, err := vec.GetMetricWith(prometheus.Labels{
+					"service":      "test",
+					"kind":         "Widget",
+					"from_version": spokeGV.String(),
+					"to_version":   hubGV.String(),
+				})
+				if err == nil {
+					return testutil.ToFloat64(counter)
+				}
+			}
+		}
+		return -1
+	}
+	errorsBefore := errorCount()
+
+	// The hub is converted to the spoke
+	review := convert(t, h, spokeGV.String(), newHub())
+	require.Equal(metav1.StatusSuccess, review.Response.Result.Status)
+	require.Len(review.Response.ConvertedObjects, 1)
+	spoke := &widgetSpoke{}
+	require.Nil(json.Unmarshal(review.Response.ConvertedObjects[0].Raw, spoke))
+	require.Equal(spokeGV.String(), spoke.APIVersion)
+	require.Equal("my-widget", spoke.Spec.Title)
+	require.Contains(spoke.Annotations, ackv1alpha1.AnnotationConversionData)
+	require.Equal(errorsBefore, errorCount())
+
+	// Failed conversions are recorded
+	spoke.Spec.Title = "invalid"
+	review = convert(t, h, hubGV.String(), spoke)
+	require.Equal(metav1.StatusFailure, review.Response.Result.Status)
+	require.Equal(errorsBefore+1, errorCount())
+}
@@ -0,0 +1,82 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package conversion
+
+import (
+	"fmt"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/diff"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// CheckHubRoundTrip converts the supplied hub to the type of the supplied
+// spoke and back, and returns an error describing how the resulting hub
+// differs from the supplied one, if it does. It is meant to be called from
+// the tests of the conversion functions of every spoke.
+func CheckHubRoundTrip(
+	hub conversion.Hub,
+	spoke conversion.Convertible,
+) error {
+	converted := newObject(spoke).(conversion.Convertible)
+	if err := converted.ConvertFrom(hub.DeepCopyObject().(conversion.Hub)); err != nil {
+		return fmt.Errorf("failed to convert %T to %T: %v", hub, spoke, err)
+	}
+	got := newObject(hub).(conversion.Hub)
+	if err := converted.ConvertTo(got); err != nil {
+		return fmt.Errorf("failed to convert %T to %T: %v", spoke, hub, err)
+	}
+	return checkEqual(hub, got)
+}
+
+// CheckSpokeRoundTrip converts the supplied spoke to the type of the
+// supplied hub and back, and returns an error describing how the resulting
+// spoke differs from the supplied one, if it does. It is meant to be called
+// from the tests of the conversion functions of every spoke.
+func CheckSpokeRoundTrip(
+	spoke conversion.Convertible,
+	hub conversion.Hub,
+) error {
+	converted := newObject(hub).(conversion.Hub)
+	if err := spoke.DeepCopyObject().(conversion.Convertible).ConvertTo(converted); err != nil {
+		return fmt.Errorf("failed to convert %T to %T: %v", spoke, hub, err)
+	}
+	got := newObject(spoke).(conversion.Convertible)
+	if err := got.ConvertFrom(converted); err != nil {
+		return fmt.Errorf("failed to convert %T to %T: %v", hub, spoke, err)
+	}
+	return checkEqual(spoke, got)
+}
+
+// newObject returns a new zero value object of the type of the supplied
+// object, with the same GroupVersionKind
+func newObject(obj runtime.Object) runtime.Object {
+	res := reflect.New(reflect.TypeOf(obj).Elem()).Interface().(runtime.Object)
+	res.GetObjectKind().SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+	return res
+}
+
+// checkEqual returns an error describing the differences between the
+// supplied objects, if any
+func checkEqual(want, got runtime.Object) error {
+	if equality.Semantic.DeepEqual(want, got) {
+		return nil
+	}
+	return fmt.Errorf(
+		"%T changed after a round trip conversion:\n%s",
+		want, diff.ObjectReflectDiff(want, got),
+	)
+}
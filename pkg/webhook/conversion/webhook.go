@@ -0,0 +1,154 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package conversion
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	ctrlconversion "sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	ackmetrics "github.com/aws-controllers-k8s/runtime/pkg/metrics"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	ackwebhook "github.com/aws-controllers-k8s/runtime/pkg/webhook"
+)
+
+// Path is the path under which the conversion webhook is served. A single
+// webhook converts the CRs of every kind.
+const Path = "/convert"
+
+// handler is an HTTP handler serving the CRD conversion requests with the
+// controller-runtime conversion webhook, and recording the CRs it fails to
+// convert
+type handler struct {
+	webhook *ctrlconversion.Webhook
+	metrics *ackmetrics.Metrics
+}
+
+// ServeHTTP implements `net/http.Handler`
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	rw := &bufferedResponseWriter{ResponseWriter: w, status: http.StatusOK}
+	h.webhook.ServeHTTP(rw, r)
+	h.recordErrors(body, rw.body.Bytes())
+	w.WriteHeader(rw.status)
+	_, _ = w.Write(rw.body.Bytes())
+}
+
+// recordErrors increments the conversion error metric for every CR of the
+// supplied conversion request if the supplied response is a failure
+func (h *handler) recordErrors(reqBody []byte, respBody []byte) {
+	if h.metrics == nil {
+		return
+	}
+	req := &apiextensionsv1.ConversionReview{}
+	resp := &apiextensionsv1.ConversionReview{}
+	if json.Unmarshal(reqBody, req) != nil || req.Request == nil {
+		return
+	}
+	if json.Unmarshal(respBody, resp) == nil && resp.Response != nil &&
+		resp.Response.Result.Status == metav1.StatusSuccess {
+		return
+	}
+	for _, obj := range req.Request.Objects {
+		typeMeta := &metav1.TypeMeta{}
+		if err := json.Unmarshal(obj.Raw, typeMeta); err != nil {
+			continue
+		}
+		h.metrics.RecordConversionError(
+			typeMeta.Kind, typeMeta.APIVersion, req.Request.DesiredAPIVersion,
+		)
+	}
+}
+
+// bufferedResponseWriter is an `net/http.ResponseWriter` holding the status
+// and body of the response until they are written to the underlying one
+type bufferedResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+// WriteHeader implements `net/http.ResponseWriter`
+func (w *bufferedResponseWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+// Write implements `net/http.ResponseWriter`
+func (w *bufferedResponseWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+// NewHandler returns an HTTP handler converting the CRs of the supplied
+// scheme between API versions. The CRs that fail to be converted are
+// recorded in the supplied metrics, if any.
+func NewHandler(
+	scheme *runtime.Scheme,
+	metrics *ackmetrics.Metrics,
+) (http.Handler, error) {
+	wh := &ctrlconversion.Webhook{}
+	if err := wh.InjectScheme(scheme); err != nil {
+		return nil, err
+	}
+	return &handler{
+		webhook: wh,
+		metrics: metrics,
+	}, nil
+}
+
+// NewWebhook returns the conversion webhook of the CRs described by the
+// supplied resource descriptor, whose hub is served in the supplied API
+// version, ready to be registered with `pkg/webhook.WebhookRegistry`. The
+// types of every API version of the CRs must be registered in the scheme of
+// the manager.
+func NewWebhook(
+	hubAPIVersion string,
+	rd acktypes.AWSResourceDescriptor,
+	metrics *ackmetrics.Metrics,
+) *ackwebhook.Webhook {
+	return ackwebhook.New(
+		hubAPIVersion,
+		rd.GroupKind().Kind,
+		string(ackwebhook.WebhookTypeConversion),
+		func(mgr ctrlrt.Manager) error {
+			srv := mgr.GetWebhookServer()
+			// The conversion webhook is shared by every kind, and only
+			// registered with the webhook server once
+			if srv.WebhookMux != nil {
+				h, p := srv.WebhookMux.Handler(&http.Request{URL: &url.URL{Path: Path}})
+				if p == Path && h != nil {
+					return nil
+				}
+			}
+			h, err := NewHandler(mgr.GetScheme(), metrics)
+			if err != nil {
+				return err
+			}
+			srv.Register(Path, h)
+			return nil
+		},
+	)
+}
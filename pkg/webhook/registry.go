@@ -13,36 +13,77 @@
 
 package webhook
 
-import "fmt"
-
-var (
-	// webhookRegistry is a map of webhooks, keyed by their unique
-	// identifier.
-	webhooksRegistry map[string]*Webhook
+import (
+	"fmt"
+	"sync"
 )
 
-// GetWebhooks returns the list of webhooks that were registred with
-// RegisterWebhook function.
-func GetWebhooks() []*Webhook {
-	webhooks := make([]*Webhook, 0, len(webhooksRegistry))
-	for _, wh := range webhooksRegistry {
+// WebhookRegistry is a thread-safe registry of webhooks. Every service
+// controller has its own registry, so that multiple controllers can run in
+// the same process.
+type WebhookRegistry struct {
+	sync.RWMutex
+	// webhooks is a map of webhooks, keyed by their unique identifier
+	webhooks map[string]*Webhook
+}
+
+// GetWebhooks returns the list of webhooks that were registered with the
+// registry
+func (r *WebhookRegistry) GetWebhooks() []*Webhook {
+	r.RLock()
+	defer r.RUnlock()
+	webhooks := make([]*Webhook, 0, len(r.webhooks))
+	for _, wh := range r.webhooks {
 		webhooks = append(webhooks, wh)
 	}
 	return webhooks
 }
 
-// RegisterWebhook registers a new webhook within the webhook registry.
-// This function will return an error if it tries to register two webhooks
-// with the same unique identifier.
-func RegisterWebhook(w *Webhook) error {
-	if webhooksRegistry == nil {
-		webhooksRegistry = make(map[string]*Webhook)
+// RegisterWebhook registers a new webhook within the registry. This method
+// will return an error if it tries to register two webhooks with the same
+// unique identifier.
+func (r *WebhookRegistry) RegisterWebhook(w *Webhook) error {
+	r.Lock()
+	defer r.Unlock()
+	if _, ok := r.webhooks[w.UID()]; ok {
+		return fmt.Errorf("webhook %s already registered", w.UID())
 	}
+	r.webhooks[w.UID()] = w
+	return nil
+}
+
+// defaultRegistry is the registry used by the package-level RegisterWebhook
+// and GetWebhooks functions, and by the service controllers that were not
+// supplied a registry of their own
+var defaultRegistry = NewWebhookRegistry()
+
+// DefaultWebhookRegistry returns the registry used by the package-level
+// RegisterWebhook and GetWebhooks functions
+func DefaultWebhookRegistry() *WebhookRegistry {
+	return defaultRegistry
+}
 
-	_, ok := webhooksRegistry[w.UID()]
-	if ok {
-		return fmt.Errorf("webhook %s already registred", w.UID())
+// GetWebhooks returns the list of webhooks that were registered with the
+// RegisterWebhook function.
+//
+// Deprecated: Use the GetWebhooks method of a WebhookRegistry instead.
+func GetWebhooks() []*Webhook {
+	return defaultRegistry.GetWebhooks()
+}
+
+// RegisterWebhook registers a new webhook within the default webhook
+// registry. This function will return an error if it tries to register two
+// webhooks with the same unique identifier.
+//
+// Deprecated: Use the RegisterWebhook method of a WebhookRegistry supplied to
+// the service controller with WithWebhookRegistry instead.
+func RegisterWebhook(w *Webhook) error {
+	return defaultRegistry.RegisterWebhook(w)
+}
+
+// NewWebhookRegistry returns a thread-safe WebhookRegistry object
+func NewWebhookRegistry() *WebhookRegistry {
+	return &WebhookRegistry{
+		webhooks: map[string]*Webhook{},
 	}
-	webhooksRegistry[w.UID()] = w
-	return nil
 }
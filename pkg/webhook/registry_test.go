@@ -0,0 +1,66 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package webhook_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ackwebhook "github.com/aws-controllers-k8s/runtime/pkg/webhook"
+)
+
+func TestWebhookRegistry(t *testing.T) {
+	require := require.New(t)
+	registry := ackwebhook.NewWebhookRegistry()
+	other := ackwebhook.NewWebhookRegistry()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			wh := ackwebhook.New(
+				"v1alpha1", fmt.Sprintf("Kind%d", i),
+				string(ackwebhook.WebhookTypeValidating), nil,
+			)
+			require.Nil(registry.RegisterWebhook(wh))
+		}(i)
+	}
+	wg.Wait()
+	require.Len(registry.GetWebhooks(), 10)
+
+	// Webhooks must be unique within a registry, but not across registries
+	wh := ackwebhook.New(
+		"v1alpha1", "Kind0", string(ackwebhook.WebhookTypeValidating), nil,
+	)
+	require.NotNil(registry.RegisterWebhook(wh))
+	require.Nil(other.RegisterWebhook(wh))
+	require.Len(other.GetWebhooks(), 1)
+}
+
+func TestDefaultWebhookRegistry(t *testing.T) {
+	require := require.New(t)
+
+	// The deprecated package-level functions use the default registry
+	wh := ackwebhook.New(
+		"v1alpha1", "DefaultKind", string(ackwebhook.WebhookTypeValidating), nil,
+	)
+	require.Nil(ackwebhook.RegisterWebhook(wh))
+	require.NotNil(ackwebhook.RegisterWebhook(wh))
+	require.Contains(ackwebhook.GetWebhooks(), wh)
+	require.Contains(ackwebhook.DefaultWebhookRegistry().GetWebhooks(), wh)
+}
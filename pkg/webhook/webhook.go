@@ -24,7 +24,8 @@ type WebhookType string
 const (
 	WebhookTypeUnknown    WebhookType = "unknown"
 	WebhookTypeConversion WebhookType = "conversion"
-	//TODO(a-hilaly) add validating and defaulting types
+	WebhookTypeValidating WebhookType = "validating"
+	WebhookTypeMutating   WebhookType = "mutating"
 )
 
 // Webhook contains information about a custom Webhook